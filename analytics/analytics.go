@@ -0,0 +1,168 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+)
+
+// LogReturns returns the log return of each currency between successive points at which every currency is quoted;
+// returns[i][t] is the return of currencies[i] over step t
+func LogReturns(points []ratehistory.Point, currencies []string) [][]float64 {
+	returns := make([][]float64, len(currencies))
+	var previous []float64
+	for _, point := range points {
+		current := make([]float64, len(currencies))
+		complete := true
+		for i, currency := range currencies {
+			rate, ok := point.Rates[currency]
+			if !ok || rate <= 0 {
+				complete = false
+				break
+			}
+			current[i] = rate
+		}
+		if !complete {
+			continue
+		}
+		if previous != nil {
+			for i := range currencies {
+				returns[i] = append(returns[i], math.Log(current[i]/previous[i]))
+			}
+		}
+		previous = current
+	}
+	return returns
+}
+
+// Covariance returns the sample covariance matrix of equally long series; it is all zeros with fewer than two observations
+func Covariance(series [][]float64) [][]float64 {
+	n := len(series)
+	covariance := make([][]float64, n)
+	for i := range covariance {
+		covariance[i] = make([]float64, n)
+	}
+	if n == 0 || len(series[0]) < 2 {
+		return covariance
+	}
+
+	observations := len(series[0])
+	means := make([]float64, n)
+	for i, values := range series {
+		for _, value := range values {
+			means[i] += value
+		}
+		means[i] /= float64(observations)
+	}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			var sum float64
+			for t := 0; t < observations; t++ {
+				sum += (series[i][t] - means[i]) * (series[j][t] - means[j])
+			}
+			covariance[i][j] = sum / float64(observations-1)
+			covariance[j][i] = covariance[i][j]
+		}
+	}
+	return covariance
+}
+
+// Correlation scales a covariance matrix to correlations; a series with zero variance correlates 1 with itself and 0 with the others
+func Correlation(covariance [][]float64) [][]float64 {
+	n := len(covariance)
+	correlation := make([][]float64, n)
+	for i := range correlation {
+		correlation[i] = make([]float64, n)
+		for j := range correlation[i] {
+			switch scale := math.Sqrt(covariance[i][i] * covariance[j][j]); {
+			case i == j:
+				correlation[i][j] = 1
+			case scale > 0:
+				correlation[i][j] = math.Max(-1, math.Min(1, covariance[i][j]/scale))
+			}
+		}
+	}
+	return correlation
+}
+
+// PortfolioVariance returns weightsᵀ × covariance × weights
+func PortfolioVariance(weights []float64, covariance [][]float64) float64 {
+	var variance float64
+	for i := range weights {
+		for j := range weights {
+			variance += weights[i] * weights[j] * covariance[i][j]
+		}
+	}
+	return math.Max(variance, 0)
+}
+
+// NormalQuantile returns the z-score below which a standard normal variable falls with probability p
+func NormalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// EWMACovariance returns the exponentially weighted covariance matrix of equally long zero-mean series, oldest first; the
+// weight of each observation is lambda times that of the next, so recent returns dominate as lambda falls
+func EWMACovariance(series [][]float64, lambda float64) [][]float64 {
+	n := len(series)
+	covariance := make([][]float64, n)
+	for i := range covariance {
+		covariance[i] = make([]float64, n)
+	}
+	if n == 0 || len(series[0]) == 0 {
+		return covariance
+	}
+
+	observations := len(series[0])
+	weights := make([]float64, observations)
+	var total float64
+	for t := observations - 1; t >= 0; t-- {
+		weights[t] = math.Pow(lambda, float64(observations-1-t))
+		total += weights[t]
+	}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			var sum float64
+			for t, weight := range weights {
+				sum += weight * series[i][t] * series[j][t]
+			}
+			covariance[i][j] = sum / total
+			covariance[j][i] = covariance[i][j]
+		}
+	}
+	return covariance
+}
+
+// SpearmanCorrelation returns the rank correlation matrix of equally long series
+func SpearmanCorrelation(series [][]float64) [][]float64 {
+	ranked := make([][]float64, len(series))
+	for i, values := range series {
+		ranked[i] = Ranks(values)
+	}
+	return Correlation(Covariance(ranked))
+}
+
+// Ranks returns the 1-based rank of each value, giving tied values the average of their ranks
+func Ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+
+	ranks := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && values[order[end]] == values[order[start]] {
+			end++
+		}
+		// Positions start to end-1 hold ranks start+1 to end
+		rank := float64(start+end+1) / 2
+		for _, index := range order[start:end] {
+			ranks[index] = rank
+		}
+		start = end
+	}
+	return ranks
+}
@@ -0,0 +1,187 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+)
+
+func TestLogReturns_SkipsIncompletePoints(t *testing.T) {
+	points := []ratehistory.Point{
+		{Date: "2024-03-01", Rates: map[string]float64{"EUR": 1.0, "GBP": 2.0}},
+		{Date: "2024-03-02", Rates: map[string]float64{"EUR": 1.1}},
+		{Date: "2024-03-03", Rates: map[string]float64{"EUR": 1.2, "GBP": 2.2}},
+		{Date: "2024-03-04", Rates: map[string]float64{"EUR": 1.2, "GBP": 1.1}},
+	}
+
+	returns := LogReturns(points, []string{"EUR", "GBP"})
+	expected := [][]float64{{math.Log(1.2), 0}, {math.Log(1.1), math.Log(0.5)}}
+	for i := range expected {
+		if len(returns[i]) != len(expected[i]) {
+			t.Fatalf("Expected %d returns, got %v", len(expected[i]), returns[i])
+		}
+		for j := range expected[i] {
+			if math.Abs(returns[i][j]-expected[i][j]) > 1e-12 {
+				t.Errorf("Expected return %v, got %v", expected[i][j], returns[i][j])
+			}
+		}
+	}
+}
+
+func TestCovarianceAndCorrelation(t *testing.T) {
+	series := [][]float64{
+		{1, 2, 3, 4},
+		{2, 4, 6, 8},
+		{4, 3, 2, 1},
+		{5, 5, 5, 5},
+	}
+	covariance := Covariance(series)
+	if math.Abs(covariance[0][0]-5.0/3) > 1e-12 || math.Abs(covariance[0][1]-10.0/3) > 1e-12 {
+		t.Errorf("Expected sample variance 5/3 and covariance 10/3, got %v and %v", covariance[0][0], covariance[0][1])
+	}
+
+	correlation := Correlation(covariance)
+	tests := []struct {
+		i, j     int
+		expected float64
+	}{
+		{0, 1, 1},
+		{0, 2, -1},
+		{0, 3, 0},
+		{3, 3, 1},
+	}
+	for _, tt := range tests {
+		if math.Abs(correlation[tt.i][tt.j]-tt.expected) > 1e-12 {
+			t.Errorf("Expected correlation[%d][%d] = %v, got %v", tt.i, tt.j, tt.expected, correlation[tt.i][tt.j])
+		}
+	}
+
+	if short := Covariance([][]float64{{1}}); short[0][0] != 0 {
+		t.Errorf("Expected zero covariance from one observation, got %v", short)
+	}
+}
+
+func TestPortfolioVariance(t *testing.T) {
+	covariance := [][]float64{{0.04, 0.01}, {0.01, 0.09}}
+	// 0.25×0.04 + 2×0.5×0.5×0.01 + 0.25×0.09
+	if variance := PortfolioVariance([]float64{0.5, 0.5}, covariance); math.Abs(variance-0.0375) > 1e-12 {
+		t.Errorf("Expected variance 0.0375, got %v", variance)
+	}
+}
+
+func TestNormalQuantile(t *testing.T) {
+	tests := []struct {
+		p, z float64
+	}{
+		{0.5, 0},
+		{0.975, 1.959964},
+		{0.95, 1.644854},
+		{0.01, -2.326348},
+	}
+	for _, tt := range tests {
+		if z := NormalQuantile(tt.p); math.Abs(z-tt.z) > 1e-6 {
+			t.Errorf("Expected quantile %v of %v, got %v", tt.z, tt.p, z)
+		}
+	}
+}
+
+func TestTailRisk(t *testing.T) {
+	// Losses of 1 to 100
+	pnl := make([]float64, 100)
+	for i := range pnl {
+		pnl[i] = -float64(i + 1)
+	}
+
+	tests := []struct {
+		confidence float64
+		var_, es   float64
+	}{
+		{0.99, 100, 100},
+		{0.95, 96, 98},
+		{0.9, 91, 95.5},
+	}
+	for _, tt := range tests {
+		valueAtRisk, expectedShortfall := TailRisk(pnl, tt.confidence)
+		if math.Abs(valueAtRisk-tt.var_) > 1e-9 || math.Abs(expectedShortfall-tt.es) > 1e-9 {
+			t.Errorf("Expected VaR %v and ES %v at %v, got %v and %v", tt.var_, tt.es, tt.confidence, valueAtRisk, expectedShortfall)
+		}
+	}
+}
+
+func TestNormalTailRisk(t *testing.T) {
+	valueAtRisk, expectedShortfall := NormalTailRisk(100, 0.975)
+	if math.Abs(valueAtRisk-195.9964) > 1e-3 || math.Abs(expectedShortfall-233.7803) > 1e-3 {
+		t.Errorf("Expected VaR 195.9964 and ES 233.7803, got %v and %v", valueAtRisk, expectedShortfall)
+	}
+}
+
+func TestCholesky(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+	}{
+		{"positive definite", [][]float64{{4, 2, 0.4}, {2, 5, 1}, {0.4, 1, 3}}},
+		{"perfectly correlated", [][]float64{{1, 2}, {2, 4}}},
+		{"zero variance", [][]float64{{0, 0}, {0, 9}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lower := Cholesky(tt.matrix)
+			for i := range tt.matrix {
+				for j := range tt.matrix {
+					var product float64
+					for k := range lower {
+						product += lower[i][k] * lower[j][k]
+					}
+					if math.Abs(product-tt.matrix[i][j]) > 1e-9 {
+						t.Errorf("Expected L×Lᵀ[%d][%d] = %v, got %v", i, j, tt.matrix[i][j], product)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRollingSums(t *testing.T) {
+	sums := RollingSums([][]float64{{1, 2, 3, 4}}, 3)
+	if len(sums[0]) != 2 || sums[0][0] != 6 || sums[0][1] != 9 {
+		t.Errorf("Expected [6 9], got %v", sums[0])
+	}
+}
+
+func TestEWMACovariance(t *testing.T) {
+	series := [][]float64{{1, 2}, {3, -1}}
+	// Weights 0.5 and 1, normalized to 1/3 and 2/3
+	covariance := EWMACovariance(series, 0.5)
+	expected := [][]float64{{1.0/3 + 8.0/3, 1 - 4.0/3}, {1 - 4.0/3, 3 + 2.0/3}}
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(covariance[i][j]-expected[i][j]) > 1e-12 {
+				t.Errorf("Expected covariance[%d][%d] = %v, got %v", i, j, expected[i][j], covariance[i][j])
+			}
+		}
+	}
+}
+
+func TestRanks(t *testing.T) {
+	ranks := Ranks([]float64{30, 10, 20, 10})
+	expected := []float64{4, 1.5, 3, 1.5}
+	for i := range expected {
+		if ranks[i] != expected[i] {
+			t.Errorf("Expected ranks %v, got %v", expected, ranks)
+			break
+		}
+	}
+}
+
+func TestSpearmanCorrelation(t *testing.T) {
+	// Monotonic but not linear, so rank correlation is exactly 1 while Pearson is not
+	series := [][]float64{{1, 2, 3, 4, 5}, {1, 4, 9, 16, 125}}
+	if rho := SpearmanCorrelation(series)[0][1]; math.Abs(rho-1) > 1e-12 {
+		t.Errorf("Expected rank correlation 1, got %v", rho)
+	}
+	if pearson := Correlation(Covariance(series))[0][1]; pearson > 0.95 {
+		t.Errorf("Expected Pearson correlation below 0.95, got %v", pearson)
+	}
+}
@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+// TailRisk returns the value at risk and expected shortfall of a profit and loss sample at a confidence level, as positive losses.
+// VaR is the loss exceeded in at most 1 - confidence of the scenarios; ES is the mean loss over those worst scenarios.
+func TailRisk(pnl []float64, confidence float64) (valueAtRisk, expectedShortfall float64) {
+	if len(pnl) == 0 {
+		return 0, 0
+	}
+	sorted := append([]float64(nil), pnl...)
+	sort.Float64s(sorted)
+
+	// Tolerance keeps n × (1 - confidence) from rounding up past a whole number, such as 100 × (1 - 0.99)
+	tail := int(math.Ceil(float64(len(sorted))*(1-confidence) - 1e-9))
+	tail = max(1, min(tail, len(sorted)))
+
+	var sum float64
+	for _, value := range sorted[:tail] {
+		sum += value
+	}
+	return -sorted[tail-1], -sum / float64(tail)
+}
+
+// NormalTailRisk returns the value at risk and expected shortfall of a zero-mean normal profit and loss with standard deviation stddev
+func NormalTailRisk(stddev, confidence float64) (valueAtRisk, expectedShortfall float64) {
+	z := NormalQuantile(confidence)
+	return z * stddev, stddev * NormalDensity(z) / (1 - confidence)
+}
+
+// NormalDensity returns the standard normal probability density at z
+func NormalDensity(z float64) float64 {
+	return math.Exp(-z*z/2) / math.Sqrt(2*math.Pi)
+}
+
+// Cholesky returns the lower triangular L with L × Lᵀ equal to a positive semi-definite matrix; columns for directions with
+// no variance, such as perfectly correlated series, are left zero
+func Cholesky(matrix [][]float64) [][]float64 {
+	n := len(matrix)
+	lower := make([][]float64, n)
+	for i := range lower {
+		lower[i] = make([]float64, n)
+	}
+	for j := 0; j < n; j++ {
+		diagonal := matrix[j][j]
+		for k := 0; k < j; k++ {
+			diagonal -= lower[j][k] * lower[j][k]
+		}
+		if diagonal <= 1e-15*math.Max(1, math.Abs(matrix[j][j])) {
+			continue
+		}
+		lower[j][j] = math.Sqrt(diagonal)
+		for i := j + 1; i < n; i++ {
+			sum := matrix[i][j]
+			for k := 0; k < j; k++ {
+				sum -= lower[i][k] * lower[j][k]
+			}
+			lower[i][j] = sum / lower[j][j]
+		}
+	}
+	return lower
+}
+
+// RollingSums returns the sums of every run of window consecutive values in each series
+func RollingSums(series [][]float64, window int) [][]float64 {
+	sums := make([][]float64, len(series))
+	for i, values := range series {
+		for start := 0; start+window <= len(values); start++ {
+			var sum float64
+			for _, value := range values[start : start+window] {
+				sum += value
+			}
+			sums[i] = append(sums[i], sum)
+		}
+	}
+	return sums
+}
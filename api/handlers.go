@@ -1,17 +1,24 @@
 package api
 
 import (
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/dalfonso89/financial-forecasting-service/config"
+	"github.com/dalfonso89/financial-forecasting-service/history"
 	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
 	"github.com/dalfonso89/financial-forecasting-service/middleware"
 	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
 	"github.com/dalfonso89/financial-forecasting-service/service"
+	"github.com/dalfonso89/financial-forecasting-service/version"
 )
 
 // HandlerConfig contains all dependencies for the Handlers
@@ -47,14 +54,26 @@ func (handlers *Handlers) SetupRoutes() *gin.Engine {
 	router := gin.New()
 
 	// Apply middleware
+	router.Use(middleware.Metrics())
 	router.Use(middleware.RequestLogger(handlers.logger))
 	router.Use(gin.Recovery())
 	router.Use(middleware.SecurityHeaders())
 	router.Use(middleware.RequestID())
-	router.Use(handlers.corsMiddleware())
+	router.Use(middleware.Tracing())
+	router.Use(middleware.CORS(handlers.corsConfig()))
 
 	// Health check endpoint
 	router.GET("/health", handlers.HealthCheck)
+	router.GET("/livez", handlers.Livez)
+	router.GET("/readyz", handlers.Readyz)
+
+	// Prometheus metrics endpoint
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// API documentation
+	router.GET("/openapi.json", handlers.OpenAPISpec)
+	router.GET("/docs", handlers.APIDocs)
+	router.GET("/docs/redoc.standalone.js", handlers.APIDocsScript)
 
 	// API v1 routes
 	apiV1 := router.Group("/api/v1")
@@ -62,13 +81,29 @@ func (handlers *Handlers) SetupRoutes() *gin.Engine {
 		// Forecasting routes
 		apiV1.POST("/forecast", handlers.GenerateForecast)
 		apiV1.POST("/forecast/multi-currency", handlers.GenerateMultiCurrencyForecast)
+		apiV1.POST("/forecast/portfolio", handlers.GeneratePortfolioForecast)
+		apiV1.POST("/forecast/scenario", handlers.GenerateScenarioForecast)
+		apiV1.GET("/forecast/scenarios", handlers.GetStressScenarios)
+		apiV1.POST("/forecast/hedging", handlers.GenerateHedgingAnalysis)
 		apiV1.GET("/forecast/trend/:base/:target", handlers.AnalyzeTrend)
 		apiV1.GET("/forecast/latest/:base/:target", handlers.GetLatestForecast)
 		apiV1.DELETE("/forecast/cache", handlers.ClearCache)
+		apiV1.GET("/forecast/accuracy", handlers.GetForecastAccuracy)
+
+		// Forecast history routes
+		apiV1.GET("/forecasts", handlers.ListForecasts)
+		apiV1.GET("/forecasts/:id", handlers.GetForecast)
 
 		// Currency information routes
 		apiV1.GET("/currencies", handlers.GetSupportedCurrencies)
+		apiV1.GET("/currencies/:code", handlers.GetCurrency)
 		apiV1.GET("/currencies/rates/:base", handlers.GetCurrentRates)
+
+		// Risk routes
+		apiV1.POST("/risk/var", handlers.CalculateValueAtRisk)
+
+		// Analytics routes
+		apiV1.GET("/analytics/correlation", handlers.GetCorrelation)
 	}
 
 	return router
@@ -76,10 +111,12 @@ func (handlers *Handlers) SetupRoutes() *gin.Engine {
 
 // HealthCheck handles health check requests
 func (handlers *Handlers) HealthCheck(context *gin.Context) {
+	buildInfo := version.Get()
 	healthCheckResponse := models.HealthCheck{
 		Status:    "healthy",
 		Timestamp: time.Now(),
-		Version:   "1.0.0",
+		Version:   buildInfo.Version,
+		Commit:    buildInfo.Commit,
 		Uptime:    time.Since(handlers.startTime).String(),
 	}
 
@@ -90,7 +127,7 @@ func (handlers *Handlers) HealthCheck(context *gin.Context) {
 func (handlers *Handlers) GenerateForecast(context *gin.Context) {
 	var req models.ForecastRequest
 	if err := context.ShouldBindJSON(&req); err != nil {
-		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid request", err.Error())
+		handlers.writeBindingError(context, err, &req)
 		return
 	}
 
@@ -100,14 +137,14 @@ func (handlers *Handlers) GenerateForecast(context *gin.Context) {
 		return
 	}
 
-	context.JSON(http.StatusOK, forecast)
+	handlers.writeJSON(context, http.StatusOK, forecast)
 }
 
 // GenerateMultiCurrencyForecast handles multi-currency forecast requests
 func (handlers *Handlers) GenerateMultiCurrencyForecast(context *gin.Context) {
 	var req models.MultiCurrencyForecastRequest
 	if err := context.ShouldBindJSON(&req); err != nil {
-		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid request", err.Error())
+		handlers.writeBindingError(context, err, &req)
 		return
 	}
 
@@ -117,7 +154,80 @@ func (handlers *Handlers) GenerateMultiCurrencyForecast(context *gin.Context) {
 		return
 	}
 
-	context.JSON(http.StatusOK, forecast)
+	handlers.writeJSON(context, http.StatusOK, forecast)
+}
+
+// GeneratePortfolioForecast handles portfolio forecast requests
+func (handlers *Handlers) GeneratePortfolioForecast(context *gin.Context) {
+	var req models.PortfolioForecastRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		handlers.writeBindingError(context, err, &req)
+		return
+	}
+
+	forecast, err := handlers.forecastingService.GeneratePortfolioForecast(context.Request.Context(), &req)
+	if err != nil {
+		handlers.handleServiceError(context, err)
+		return
+	}
+
+	handlers.writeJSON(context, http.StatusOK, forecast)
+}
+
+// GenerateScenarioForecast handles scenario and stress test forecast requests
+func (handlers *Handlers) GenerateScenarioForecast(context *gin.Context) {
+	var req models.ScenarioForecastRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		handlers.writeBindingError(context, err, &req)
+		return
+	}
+
+	forecast, err := handlers.forecastingService.GenerateScenarioForecast(context.Request.Context(), &req)
+	if err != nil {
+		handlers.handleServiceError(context, err)
+		return
+	}
+
+	handlers.writeJSON(context, http.StatusOK, forecast)
+}
+
+// GetStressScenarios returns the library of historical stress scenarios
+func (handlers *Handlers) GetStressScenarios(context *gin.Context) {
+	handlers.writeJSON(context, http.StatusOK, models.StressScenarioListResponse{Scenarios: handlers.forecastingService.StressScenarios()})
+}
+
+// GenerateHedgingAnalysis handles forward rate and hedging cost requests
+func (handlers *Handlers) GenerateHedgingAnalysis(context *gin.Context) {
+	var req models.HedgingRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		handlers.writeBindingError(context, err, &req)
+		return
+	}
+
+	analysis, err := handlers.forecastingService.GenerateHedgingAnalysis(context.Request.Context(), &req)
+	if err != nil {
+		handlers.handleServiceError(context, err)
+		return
+	}
+
+	handlers.writeJSON(context, http.StatusOK, analysis)
+}
+
+// CalculateValueAtRisk handles value at risk requests
+func (handlers *Handlers) CalculateValueAtRisk(context *gin.Context) {
+	var req models.ValueAtRiskRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		handlers.writeBindingError(context, err, &req)
+		return
+	}
+
+	risk, err := handlers.forecastingService.CalculateValueAtRisk(context.Request.Context(), &req)
+	if err != nil {
+		handlers.handleServiceError(context, err)
+		return
+	}
+
+	handlers.writeJSON(context, http.StatusOK, risk)
 }
 
 // AnalyzeTrend handles trend analysis requests
@@ -128,7 +238,8 @@ func (handlers *Handlers) AnalyzeTrend(context *gin.Context) {
 	periodsStr := context.DefaultQuery("periods", "30")
 	periods, err := strconv.Atoi(periodsStr)
 	if err != nil {
-		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid periods parameter", "periods must be a valid integer")
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid periods parameter", "periods must be a valid integer",
+			models.FieldError{Field: "periods", Message: "periods must be a valid integer"})
 		return
 	}
 
@@ -138,7 +249,119 @@ func (handlers *Handlers) AnalyzeTrend(context *gin.Context) {
 		return
 	}
 
-	context.JSON(http.StatusOK, analysis)
+	handlers.writeJSON(context, http.StatusOK, analysis)
+}
+
+// GetForecastAccuracy reports rolling forecast error statistics, optionally filtered by pair and type
+func (handlers *Handlers) GetForecastAccuracy(context *gin.Context) {
+	report := handlers.forecastingService.AccuracyReport(strings.ToUpper(context.Query("pair")), context.Query("type"))
+	handlers.writeJSON(context, http.StatusOK, report)
+}
+
+// GetCorrelation returns correlation and covariance matrices of every supported currency against a base
+func (handlers *Handlers) GetCorrelation(context *gin.Context) {
+	options := service.CorrelationOptions{
+		Base:   context.Query("base"),
+		Method: context.Query("method"),
+	}
+
+	var fieldErrors []models.FieldError
+	for _, param := range []struct {
+		name   string
+		target *int
+	}{{"window", &options.WindowDays}, {"windows", &options.Windows}, {"step", &options.StepDays}} {
+		if value := context.Query(param.name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: param.name, Message: param.name + " must be a valid integer"})
+				continue
+			}
+			*param.target = parsed
+		}
+	}
+	if lambda := context.Query("lambda"); lambda != "" {
+		value, err := strconv.ParseFloat(lambda, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "lambda", Message: "lambda must be a number"})
+		} else {
+			options.Lambda = value
+		}
+	}
+	if len(fieldErrors) > 0 {
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid query parameters", "one or more query parameters are invalid", fieldErrors...)
+		return
+	}
+
+	correlation, err := handlers.forecastingService.CorrelationMatrix(context.Request.Context(), options)
+	if err != nil {
+		handlers.handleServiceError(context, err)
+		return
+	}
+
+	handlers.writeJSON(context, http.StatusOK, correlation)
+}
+
+// GetForecast returns a previously generated forecast by its forecast_id
+func (handlers *Handlers) GetForecast(context *gin.Context) {
+	forecast, err := handlers.forecastingService.GetForecast(context.Request.Context(), context.Param("id"))
+	if err != nil {
+		handlers.handleServiceError(context, err)
+		return
+	}
+
+	handlers.writeJSON(context, http.StatusOK, forecast)
+}
+
+// ListForecasts returns previously generated forecasts, newest first, filtered by pair, type and time range
+func (handlers *Handlers) ListForecasts(context *gin.Context) {
+	filter := history.Filter{
+		Pair:         strings.ToUpper(context.Query("pair")),
+		ForecastType: context.Query("type"),
+		Cursor:       context.Query("cursor"),
+	}
+
+	var fieldErrors []models.FieldError
+	for _, bound := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value, err := parseTimeParam(context.Query(bound.name))
+		if err != nil {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: bound.name, Message: bound.name + " must be an RFC 3339 timestamp or a YYYY-MM-DD date"})
+			continue
+		}
+		*bound.target = value
+	}
+	if limit := context.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > history.MaxLimit {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "limit", Message: "limit must be an integer between 1 and " + strconv.Itoa(history.MaxLimit)})
+		}
+		filter.Limit = value
+	}
+	if len(fieldErrors) > 0 {
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid query parameters", "one or more query parameters are invalid", fieldErrors...)
+		return
+	}
+
+	forecasts, err := handlers.forecastingService.ListForecasts(context.Request.Context(), filter)
+	if err != nil {
+		handlers.handleServiceError(context, err)
+		return
+	}
+
+	handlers.writeJSON(context, http.StatusOK, forecasts)
+}
+
+// parseTimeParam parses an optional RFC 3339 timestamp or YYYY-MM-DD date (midnight UTC)
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02", value)
 }
 
 // ClearCache handles cache clearing requests
@@ -147,9 +370,20 @@ func (handlers *Handlers) ClearCache(context *gin.Context) {
 	context.JSON(http.StatusOK, gin.H{"message": "Cache cleared successfully"})
 }
 
-// GetSupportedCurrencies returns the list of supported currencies
+// GetSupportedCurrencies returns the ISO 4217 details of every supported currency
 func (handlers *Handlers) GetSupportedCurrencies(context *gin.Context) {
-	context.JSON(http.StatusOK, gin.H{"currencies": handlers.config.SupportedCurrencies})
+	handlers.writeJSON(context, http.StatusOK, models.CurrencyListResponse{Currencies: handlers.forecastingService.Currencies()})
+}
+
+// GetCurrency returns the ISO 4217 details of one currency, matching the code case-insensitively
+func (handlers *Handlers) GetCurrency(context *gin.Context) {
+	currency, err := handlers.forecastingService.Currency(context.Param("code"))
+	if err != nil {
+		handlers.handleServiceError(context, err)
+		return
+	}
+
+	handlers.writeJSON(context, http.StatusOK, currency)
 }
 
 // GetCurrentRates fetches current exchange rates from the currency service
@@ -169,34 +403,34 @@ func (handlers *Handlers) GetLatestForecast(context *gin.Context) {
 	baseCurrency := context.Param("base")
 	targetCurrency := context.Param("target")
 
-	// Parse query parameters with defaults
+	// Parse query parameters with defaults; omitted periods and type take the pair's configured defaults
 	amountStr := context.DefaultQuery("amount", "1000")
-	periodsStr := context.DefaultQuery("periods", "30")
-	forecastType := context.DefaultQuery("type", "linear")
+	periodsStr := context.Query("periods")
+	forecastType := context.Query("type")
+	forecastCalendar := context.DefaultQuery("calendar", "calendar")
+	frequency := context.DefaultQuery("frequency", "daily")
+	startDate := context.Query("start_date")
+	timezone := context.Query("timezone")
 
 	amount, err := strconv.ParseFloat(amountStr, 64)
-	if err != nil {
-		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid amount parameter", "amount must be a valid number")
-		return
-	}
-
-	periods, err := strconv.Atoi(periodsStr)
-	if err != nil {
-		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid periods parameter", "periods must be a valid integer")
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid amount parameter", "amount must be a valid number",
+			models.FieldError{Field: "amount", Message: "amount must be a valid number"})
 		return
 	}
 
-	// Validate forecast type
-	validTypes := []string{"linear", "exponential", "moving_average"}
-	isValidType := false
-	for _, validType := range validTypes {
-		if forecastType == validType {
-			isValidType = true
-			break
+	var periods int
+	if periodsStr != "" {
+		if periods, err = strconv.Atoi(periodsStr); err != nil {
+			handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid periods parameter", "periods must be a valid integer",
+				models.FieldError{Field: "periods", Message: "periods must be a valid integer"})
+			return
 		}
 	}
-	if !isValidType {
-		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid forecast type", "forecast type must be one of: linear, exponential, moving_average")
+
+	if forecastType != "" && !service.IsForecastTypeSupported(forecastType) {
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid forecast type", "forecast type must be one of: linear, exponential, moving_average",
+			models.FieldError{Field: "type", Message: "type must be one of: linear, exponential, moving_average"})
 		return
 	}
 
@@ -207,6 +441,10 @@ func (handlers *Handlers) GetLatestForecast(context *gin.Context) {
 		Amount:         amount,
 		Periods:        periods,
 		ForecastType:   forecastType,
+		Calendar:       forecastCalendar,
+		Frequency:      frequency,
+		StartDate:      startDate,
+		Timezone:       timezone,
 	}
 
 	// Generate forecast using the latest exchange rates
@@ -216,45 +454,87 @@ func (handlers *Handlers) GetLatestForecast(context *gin.Context) {
 		return
 	}
 
-	context.JSON(http.StatusOK, forecast)
+	handlers.writeJSON(context, http.StatusOK, forecast)
+}
+
+// writeJSON writes a response body, encoding amounts as strings when AMOUNTS_AS_STRINGS is set
+func (handlers *Handlers) writeJSON(context *gin.Context, statusCode int, value interface{}) {
+	if !handlers.forecastingService.AmountsAsStrings() {
+		context.JSON(statusCode, value)
+		return
+	}
+	body, err := money.MarshalJSONQuoted(value)
+	if err != nil {
+		handlers.writeErrorResponse(context, http.StatusInternalServerError, "failed to encode response", err.Error())
+		return
+	}
+	context.Data(statusCode, "application/json; charset=utf-8", body)
 }
 
 // writeErrorResponse writes an error response using Gin context
-func (handlers *Handlers) writeErrorResponse(context *gin.Context, statusCode int, errorMessage, errorDetails string) {
-	errorResponse := models.ErrorResponse{
-		Error:   errorMessage,
-		Message: errorDetails,
-		Code:    statusCode,
+func (handlers *Handlers) writeErrorResponse(context *gin.Context, statusCode int, errorMessage, errorDetails string, fieldErrors ...models.FieldError) {
+	errorCode := "invalid_request"
+	if statusCode >= http.StatusInternalServerError {
+		errorCode = service.CodeInternalError
 	}
+	handlers.writeProblem(context, statusCode, errorCode, errorMessage, errorDetails, fieldErrors)
+}
 
-	context.JSON(statusCode, errorResponse)
+// writeCodedErrorResponse writes an error response carrying a machine-readable error code
+func (handlers *Handlers) writeCodedErrorResponse(context *gin.Context, statusCode int, errorCode, errorMessage, errorDetails string) {
+	handlers.writeProblem(context, statusCode, errorCode, errorMessage, errorDetails, nil)
 }
 
-// handleServiceError handles service errors
+// statusClientClosedRequest is the nginx convention for a request the client abandoned before the response
+const statusClientClosedRequest = 499
+
+// handleServiceError maps service errors to HTTP status codes
 func (handlers *Handlers) handleServiceError(context *gin.Context, err error) {
-	handlers.logger.Errorf("Service error: %v", err)
-	handlers.writeErrorResponse(context, http.StatusInternalServerError, "service error", err.Error())
+	var statusCode int
+	switch {
+	case errors.Is(err, service.ErrCanceled):
+		statusCode = statusClientClosedRequest
+	case errors.Is(err, service.ErrValidation):
+		statusCode = http.StatusBadRequest
+	case errors.Is(err, service.ErrNotFound):
+		statusCode = http.StatusNotFound
+	case errors.Is(err, service.ErrRateLimited):
+		statusCode = http.StatusTooManyRequests
+	case errors.Is(err, service.ErrUpstreamTimeout):
+		statusCode = http.StatusGatewayTimeout
+	case errors.Is(err, service.ErrUpstreamUnavailable):
+		statusCode = http.StatusBadGateway
+	default:
+		statusCode = http.StatusInternalServerError
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		handlers.logger.WithContext(context.Request.Context()).Errorf("Service error: %v", err)
+	} else if statusCode == statusClientClosedRequest {
+		handlers.logger.WithContext(context.Request.Context()).Debugf("Request canceled by the client: %v", err)
+	} else {
+		handlers.logger.WithContext(context.Request.Context()).Warnf("Service error: %v", err)
+	}
+	handlers.writeCodedErrorResponse(context, statusCode, service.ErrorCode(err), "service error", err.Error())
 }
 
-// corsMiddleware adds CORS headers using Gin middleware
-func (handlers *Handlers) corsMiddleware() gin.HandlerFunc {
-	return func(context *gin.Context) {
-		context.Header("Access-Control-Allow-Origin", "*")
-		context.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		context.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		// Handle HTTP method using type switch
-		switch context.Request.Method {
-		case "OPTIONS":
-			context.AbortWithStatus(http.StatusOK)
-			return
-		case "GET", "POST", "PUT", "DELETE":
-			// Continue processing
-		default:
-			context.AbortWithStatus(http.StatusMethodNotAllowed)
-			return
-		}
+// corsConfig builds the CORS policy from the service configuration; without configured origins any origin is allowed
+func (handlers *Handlers) corsConfig() middleware.CORSConfig {
+	corsConfig := middleware.DefaultCORSConfig()
+	if handlers.config == nil {
+		return corsConfig
+	}
 
-		context.Next()
+	if len(handlers.config.CORSAllowedOrigins) > 0 {
+		corsConfig.AllowedOrigins = handlers.config.CORSAllowedOrigins
+	}
+	if len(handlers.config.CORSAllowedMethods) > 0 {
+		corsConfig.AllowedMethods = handlers.config.CORSAllowedMethods
+	}
+	if len(handlers.config.CORSAllowedHeaders) > 0 {
+		corsConfig.AllowedHeaders = handlers.config.CORSAllowedHeaders
 	}
+	corsConfig.AllowCredentials = handlers.config.CORSAllowCredentials
+	corsConfig.MaxAge = handlers.config.CORSMaxAge
+	return corsConfig
 }
@@ -3,9 +3,13 @@
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -13,6 +17,7 @@ import (
 	"github.com/dalfonso89/financial-forecasting-service/logger"
 	"github.com/dalfonso89/financial-forecasting-service/models"
 	"github.com/dalfonso89/financial-forecasting-service/service"
+	"github.com/dalfonso89/financial-forecasting-service/version"
 )
 
 // Test helper function to create handlers
@@ -52,8 +57,8 @@ func TestHandlers_HealthCheck(t *testing.T) {
 	if response.Status != "healthy" {
 		t.Errorf("Expected status 'healthy', got '%s'", response.Status)
 	}
-	if response.Version != "1.0.0" {
-		t.Errorf("Expected version '1.0.0', got '%s'", response.Version)
+	if response.Version != version.String() {
+		t.Errorf("Expected version '%s', got '%s'", version.String(), response.Version)
 	}
 }
 
@@ -70,18 +75,65 @@ func TestHandlers_GetSupportedCurrencies(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", w.Code)
 	}
 
-	var response map[string]interface{}
+	var response models.CurrencyListResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	if err != nil {
 		t.Errorf("Failed to unmarshal response: %v", err)
 	}
 
-	currencies, ok := response["currencies"].([]interface{})
-	if !ok {
-		t.Errorf("Expected currencies array in response")
+	if len(response.Currencies) != 4 {
+		t.Fatalf("Expected the 4 configured currencies, got %d", len(response.Currencies))
+	}
+	jpy := response.Currencies[3]
+	if jpy.Code != "JPY" || jpy.Numeric != "392" || jpy.MinorUnits != 0 || !jpy.Supported {
+		t.Errorf("Expected JPY with numeric 392, 0 minor units and supported, got %+v", jpy)
+	}
+}
+
+func TestHandlers_GetCurrency(t *testing.T) {
+	handlers := createTestHandlers()
+	router := handlers.SetupRoutes()
+
+	tests := []struct {
+		name           string
+		code           string
+		expectedStatus int
+		expectedCode   string
+		supported      bool
+	}{
+		{"supported", "EUR", http.StatusOK, "EUR", true},
+		{"lower case", "gbp", http.StatusOK, "GBP", true},
+		{"not supported", "KWD", http.StatusOK, "KWD", false},
+		{"not ISO 4217", "XYZ", http.StatusNotFound, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/api/v1/currencies/"+tt.code, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var currency models.Currency
+			if err := json.Unmarshal(w.Body.Bytes(), &currency); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if currency.Code != tt.expectedCode || currency.Supported != tt.supported {
+				t.Errorf("Expected %s with supported=%v, got %+v", tt.expectedCode, tt.supported, currency)
+			}
+		})
 	}
-	if len(currencies) == 0 {
-		t.Errorf("Expected non-empty currencies array")
+
+	// The static rates route must still win over :code
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/currencies/rates/USD", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 from the rates route, got %d", w.Code)
 	}
 }
 
@@ -93,6 +145,7 @@ func TestHandlers_GenerateForecast_InvalidJSON(t *testing.T) {
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("POST", "/forecast", bytes.NewBufferString("invalid json"))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusBadRequest {
@@ -140,6 +193,135 @@ func TestHandlers_GenerateMultiCurrencyForecast_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestHandlers_GeneratePortfolioForecast_MissingHoldings(t *testing.T) {
+	handlers := createTestHandlers()
+	router := gin.New()
+	router.POST("/forecast/portfolio", handlers.GeneratePortfolioForecast)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/forecast/portfolio", bytes.NewBufferString(`{"reporting_currency":"USD","holdings":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	var problem models.ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to unmarshal error response: %v", err)
+	}
+	if len(problem.Errors) == 0 || problem.Errors[0].Field != "holdings" {
+		t.Errorf("Expected a holdings field error, got %+v", problem)
+	}
+}
+
+func TestHandlers_CalculateValueAtRisk_MissingPositions(t *testing.T) {
+	handlers := createTestHandlers()
+	router := gin.New()
+	router.POST("/risk/var", handlers.CalculateValueAtRisk)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/risk/var", bytes.NewBufferString(`{"reporting_currency":"USD"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	var problem models.ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to unmarshal error response: %v", err)
+	}
+	if len(problem.Errors) == 0 || problem.Errors[0].Field != "positions" {
+		t.Errorf("Expected a positions field error, got %+v", problem)
+	}
+}
+
+func TestHandlers_GetCorrelation_InvalidParams(t *testing.T) {
+	handlers := createTestHandlers()
+	router := gin.New()
+	router.GET("/analytics/correlation", handlers.GetCorrelation)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/analytics/correlation?base=USD&window=ninety&lambda=high", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	var problem models.ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to unmarshal error response: %v", err)
+	}
+	if len(problem.Errors) != 2 || problem.Errors[0].Field != "window" || problem.Errors[1].Field != "lambda" {
+		t.Errorf("Expected window and lambda field errors, got %+v", problem.Errors)
+	}
+
+	// Non-finite values parse as floats but are not valid decay factors
+	for _, lambda := range []string{"NaN", "Inf"} {
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/analytics/correlation?base=USD&method=ewma&lambda="+lambda, nil)
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for lambda=%s, got %d", lambda, w.Code)
+		}
+		problem = models.ProblemDetails{}
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Failed to unmarshal error response: %v", err)
+		}
+		if len(problem.Errors) != 1 || problem.Errors[0].Field != "lambda" {
+			t.Errorf("Expected a lambda field error for lambda=%s, got %+v", lambda, problem.Errors)
+		}
+	}
+}
+
+func TestHandlers_GenerateScenarioForecast_InvalidShock(t *testing.T) {
+	handlers := createTestHandlers()
+	router := gin.New()
+	router.POST("/forecast/scenario", handlers.GenerateScenarioForecast)
+
+	w := httptest.NewRecorder()
+	body := `{"base_currency":"USD","currencies":["EUR"],"amount":1000,"shocks":[{"currency":"EUR"}]}`
+	req, _ := http.NewRequest("POST", "/forecast/scenario", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	var problem models.ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to unmarshal error response: %v", err)
+	}
+	if len(problem.Errors) == 0 || problem.Errors[0].Field != "shocks[0].type" {
+		t.Errorf("Expected a shocks[0].type field error, got %+v", problem.Errors)
+	}
+}
+
+func TestHandlers_GenerateHedgingAnalysis_NoCurve(t *testing.T) {
+	handlers := createTestHandlers()
+	router := gin.New()
+	router.POST("/forecast/hedging", handlers.GenerateHedgingAnalysis)
+
+	w := httptest.NewRecorder()
+	body := `{"base_currency":"USD","target_currency":"EUR","amount":1000}`
+	req, _ := http.NewRequest("POST", "/forecast/hedging", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+	var errorResponse models.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errorResponse); err != nil {
+		t.Fatalf("Failed to unmarshal error response: %v", err)
+	}
+	if errorResponse.ErrorCode != service.CodeCurveNotFound {
+		t.Errorf("Expected error code %s, got %s", service.CodeCurveNotFound, errorResponse.ErrorCode)
+	}
+}
+
 func TestHandlers_GenerateMultiCurrencyForecast_EmptyCurrencies(t *testing.T) {
 	handlers := createTestHandlers()
 	router := gin.New()
@@ -169,6 +351,7 @@ func TestHandlers_AnalyzeTrend_InvalidPeriods(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/forecast/trend/USD/EUR?periods=invalid", nil)
+	req.Header.Set("Accept", "application/json")
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusBadRequest {
@@ -196,8 +379,8 @@ func TestHandlers_AnalyzeTrend_DefaultPeriods(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	// This will fail due to currency service unavailability, but we can test the parameter parsing
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Expected status 500 due to service unavailability, got %d", w.Code)
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502 due to service unavailability, got %d", w.Code)
 	}
 }
 
@@ -225,61 +408,70 @@ func TestHandlers_GetCurrentRates(t *testing.T) {
 	}
 }
 
-func TestHandlers_CORS_Middleware(t *testing.T) {
+func TestHandlers_CORS_ConfiguredOrigins(t *testing.T) {
 	handlers := createTestHandlers()
-	router := gin.New()
-	router.Use(handlers.corsMiddleware())
-	router.GET("/test", func(c *gin.Context) {
-		c.JSON(200, gin.H{"message": "test"})
-	})
+	handlers.config.CORSAllowedOrigins = []string{"https://app.example.com"}
+	handlers.config.CORSAllowCredentials = true
+	router := handlers.SetupRoutes()
 
-	// Test OPTIONS request
+	// Preflight from an allowed origin
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("OPTIONS", "/test", nil)
+	req, _ := http.NewRequest("OPTIONS", "/api/v1/forecast", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200 for OPTIONS, got %d", w.Code)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 for preflight, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Expected allowed origin to be echoed, got %s", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Expected credentials to be allowed, got %s", got)
 	}
 
-	// Test GET request with CORS headers
+	// Preflight from a foreign origin
 	w = httptest.NewRecorder()
-	req, _ = http.NewRequest("GET", "/test", nil)
+	req, _ = http.NewRequest("OPTIONS", "/api/v1/forecast", nil)
+	req.Header.Set("Origin", "https://evil.example.org")
+	req.Header.Set("Access-Control-Request-Method", "POST")
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for disallowed origin, got %d", w.Code)
 	}
+}
 
-	// Check CORS headers
-	expectedHeaders := map[string]string{
-		"Access-Control-Allow-Origin":  "*",
-		"Access-Control-Allow-Methods": "GET, POST, PUT, DELETE, OPTIONS",
-		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+func TestHandlers_corsConfig_DefaultOrigins(t *testing.T) {
+	handlers := createTestHandlers()
+	handlers.config.CORSAllowedMethods = []string{"GET"}
+	handlers.config.CORSAllowedHeaders = []string{"X-Custom"}
+	handlers.config.CORSMaxAge = time.Minute
+
+	// Only the origins fall back to the default; the other configured fields apply
+	corsConfig := handlers.corsConfig()
+	if strings.Join(corsConfig.AllowedOrigins, ",") != "*" {
+		t.Errorf("Expected the default origins, got %v", corsConfig.AllowedOrigins)
 	}
-
-	for header, expectedValue := range expectedHeaders {
-		actualValue := w.Header().Get(header)
-		if actualValue != expectedValue {
-			t.Errorf("Expected header %s: %s, got: %s", header, expectedValue, actualValue)
-		}
+	if strings.Join(corsConfig.AllowedMethods, ",") != "GET" || strings.Join(corsConfig.AllowedHeaders, ",") != "X-Custom" {
+		t.Errorf("Expected the configured methods and headers, got %v and %v", corsConfig.AllowedMethods, corsConfig.AllowedHeaders)
+	}
+	if corsConfig.MaxAge != time.Minute {
+		t.Errorf("Expected max age 1m, got %v", corsConfig.MaxAge)
 	}
 }
 
-func TestHandlers_CORS_InvalidMethod(t *testing.T) {
+func TestHandlers_CORS_DoesNotRejectMethods(t *testing.T) {
 	handlers := createTestHandlers()
-	router := gin.New()
-	router.Use(handlers.corsMiddleware())
-	router.GET("/test", func(c *gin.Context) {
-		c.JSON(200, gin.H{"message": "test"})
-	})
+	router := handlers.SetupRoutes()
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("PATCH", "/test", nil)
+	req, _ := http.NewRequest("HEAD", "/health", nil)
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusMethodNotAllowed {
-		t.Errorf("Expected status 405 for invalid method, got %d", w.Code)
+	if w.Code == http.StatusMethodNotAllowed {
+		t.Errorf("Expected HEAD to reach the router, got %d", w.Code)
 	}
 }
 
@@ -292,6 +484,7 @@ func TestHandlers_WriteErrorResponse(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Accept", "application/json")
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusBadRequest {
@@ -317,6 +510,214 @@ func TestHandlers_WriteErrorResponse(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandleServiceError(t *testing.T) {
+	handlers := createTestHandlers()
+
+	tests := []struct {
+		name              string
+		err               error
+		expectedStatus    int
+		expectedErrorCode string
+	}{
+		{
+			name:              "validation error",
+			err:               &service.Error{Kind: service.ErrValidation, Code: service.CodeUnsupportedCurrency, Message: "base currency XXX is not supported"},
+			expectedStatus:    http.StatusBadRequest,
+			expectedErrorCode: service.CodeUnsupportedCurrency,
+		},
+		{
+			name:              "not found",
+			err:               &service.Error{Kind: service.ErrNotFound, Code: service.CodeCurrencyNotFound, Message: "not found"},
+			expectedStatus:    http.StatusNotFound,
+			expectedErrorCode: service.CodeCurrencyNotFound,
+		},
+		{
+			name:              "upstream unavailable",
+			err:               &service.Error{Kind: service.ErrUpstreamUnavailable, Code: service.CodeUpstreamUnavailable, Message: "down"},
+			expectedStatus:    http.StatusBadGateway,
+			expectedErrorCode: service.CodeUpstreamUnavailable,
+		},
+		{
+			name:              "upstream timeout",
+			err:               &service.Error{Kind: service.ErrUpstreamTimeout, Code: service.CodeUpstreamTimeout, Message: "slow"},
+			expectedStatus:    http.StatusGatewayTimeout,
+			expectedErrorCode: service.CodeUpstreamTimeout,
+		},
+		{
+			name:              "rate limited",
+			err:               &service.Error{Kind: service.ErrRateLimited, Code: service.CodeRateLimited, Message: "slow down"},
+			expectedStatus:    http.StatusTooManyRequests,
+			expectedErrorCode: service.CodeRateLimited,
+		},
+		{
+			name:              "client canceled",
+			err:               &service.Error{Kind: service.ErrCanceled, Code: service.CodeClientClosedRequest, Message: "request canceled"},
+			expectedStatus:    499,
+			expectedErrorCode: service.CodeClientClosedRequest,
+		},
+		{
+			name:              "untyped error",
+			err:               errors.New("boom"),
+			expectedStatus:    http.StatusInternalServerError,
+			expectedErrorCode: service.CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", func(c *gin.Context) {
+				handlers.handleServiceError(c, fmt.Errorf("wrapped: %w", tt.err))
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var errorResponse models.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &errorResponse); err != nil {
+				t.Fatalf("Failed to unmarshal error response: %v", err)
+			}
+			if errorResponse.ErrorCode != tt.expectedErrorCode {
+				t.Errorf("Expected error code %s, got %s", tt.expectedErrorCode, errorResponse.ErrorCode)
+			}
+		})
+	}
+}
+
+func TestHandlers_GenerateForecast_ProblemDetails(t *testing.T) {
+	handlers := createTestHandlers()
+	router := handlers.SetupRoutes()
+
+	body := `{"base_currency": "USD", "amount": -5}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/forecast", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "problem-test-id")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Expected problem+json content type, got %s", contentType)
+	}
+
+	var problem models.ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to unmarshal problem response: %v", err)
+	}
+
+	if problem.Status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 in body, got %d", problem.Status)
+	}
+	if problem.Type == "" || problem.Title == "" {
+		t.Errorf("Expected type and title to be set, got %+v", problem)
+	}
+	if problem.Instance != "problem-test-id" {
+		t.Errorf("Expected instance to be the request ID, got %s", problem.Instance)
+	}
+	if problem.RequestID != "problem-test-id" {
+		t.Errorf("Expected request_id to be echoed, got %s", problem.RequestID)
+	}
+
+	expectedViolations := map[string]string{
+		"target_currency": "target_currency is required",
+		"amount":          "amount must be > 0",
+	}
+	if len(problem.Errors) != len(expectedViolations) {
+		t.Fatalf("Expected %d field errors, got %+v", len(expectedViolations), problem.Errors)
+	}
+	for _, fieldErr := range problem.Errors {
+		if expectedViolations[fieldErr.Field] != fieldErr.Message {
+			t.Errorf("Unexpected field error %s: %s", fieldErr.Field, fieldErr.Message)
+		}
+	}
+}
+
+func TestHandlers_GenerateMultiCurrencyForecast_ProblemDetails(t *testing.T) {
+	handlers := createTestHandlers()
+	router := gin.New()
+	router.POST("/forecast/multi-currency", handlers.GenerateMultiCurrencyForecast)
+
+	body := `{"base_currency": "USD", "currencies": [], "amount": "lots"}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/forecast/multi-currency", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/problem+json")
+	router.ServeHTTP(w, req)
+
+	var problem models.ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to unmarshal problem response: %v", err)
+	}
+
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "amount" {
+		t.Errorf("Expected a single amount type violation, got %+v", problem.Errors)
+	}
+}
+
+func TestHandlers_ErrorResponse_RequestID(t *testing.T) {
+	handlers := createTestHandlers()
+	router := handlers.SetupRoutes()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/forecast", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	router.ServeHTTP(w, req)
+
+	var response models.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal error response: %v", err)
+	}
+
+	generatedID := w.Header().Get("X-Request-ID")
+	if generatedID == "" {
+		t.Fatal("Expected X-Request-ID header to be set")
+	}
+	if response.RequestID != generatedID {
+		t.Errorf("Expected request_id %s in error body, got %s", generatedID, response.RequestID)
+	}
+}
+
+func TestHandlers_Metrics(t *testing.T) {
+	handlers := createTestHandlers()
+	router := handlers.SetupRoutes()
+
+	// Generate some traffic first
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/currencies", nil)
+	router.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/metrics", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	body := w.Body.String()
+	expected := []string{
+		`http_request_duration_seconds_count{method="GET",route="/api/v1/currencies",status="200"}`,
+		"# TYPE http_requests_in_flight gauge",
+		"# TYPE forecast_computation_duration_seconds histogram",
+		"# TYPE forecast_cache_hit_ratio gauge",
+		"# TYPE currency_client_request_duration_seconds histogram",
+		"# TYPE currency_client_errors_total counter",
+	}
+	for _, item := range expected {
+		if !strings.Contains(body, item) {
+			t.Errorf("Expected metrics output to contain %q", item)
+		}
+	}
+}
+
 func TestHandlers_GetLatestForecast(t *testing.T) {
 	// Create test configuration
 	cfg := &config.Config{
@@ -349,13 +750,13 @@ func TestHandlers_GetLatestForecast(t *testing.T) {
 		{
 			name:           "valid forecast request with defaults (currency service unavailable)",
 			url:            "/api/v1/forecast/latest/USD/EUR",
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusBadGateway,
 			expectError:    true,
 		},
 		{
 			name:           "valid forecast request with custom parameters (currency service unavailable)",
 			url:            "/api/v1/forecast/latest/USD/EUR?amount=5000&periods=7&type=exponential",
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusBadGateway,
 			expectError:    true,
 		},
 		{
@@ -364,6 +765,18 @@ func TestHandlers_GetLatestForecast(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectError:    true,
 		},
+		{
+			name:           "NaN amount parameter",
+			url:            "/api/v1/forecast/latest/USD/EUR?amount=NaN",
+			expectedStatus: http.StatusBadRequest,
+			expectError:    true,
+		},
+		{
+			name:           "infinite amount parameter",
+			url:            "/api/v1/forecast/latest/USD/EUR?amount=Inf",
+			expectedStatus: http.StatusBadRequest,
+			expectError:    true,
+		},
 		{
 			name:           "invalid periods parameter",
 			url:            "/api/v1/forecast/latest/USD/EUR?periods=invalid",
@@ -379,7 +792,7 @@ func TestHandlers_GetLatestForecast(t *testing.T) {
 		{
 			name:           "unsupported currency",
 			url:            "/api/v1/forecast/latest/INVALID/EUR",
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusBadRequest,
 			expectError:    true,
 		},
 	}
@@ -391,6 +804,7 @@ func TestHandlers_GetLatestForecast(t *testing.T) {
 			if err != nil {
 				t.Fatalf("Failed to create request: %v", err)
 			}
+			req.Header.Set("Accept", "application/json")
 
 			// Create response recorder
 			w := httptest.NewRecorder()
@@ -431,3 +845,221 @@ func TestHandlers_GetLatestForecast(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlers_GetLatestForecast_ConfiguredDefaults(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85,"GBP":0.75}}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR", "GBP"},
+		DefaultForecastType:        "exponential",
+		DefaultForecastPeriods:     5,
+		ModelPairs:                 map[string]config.ModelConfig{"USD/GBP": {ForecastType: "moving_average", Periods: 3}},
+		CurrencyExchangeServiceURL: upstream.URL,
+	}
+	loggerInstance := logger.New("error")
+	handlers := NewHandlers(HandlerConfig{
+		Logger:             loggerInstance,
+		ForecastingService: service.NewForecastingService(cfg, loggerInstance),
+		Config:             cfg,
+	})
+	router := handlers.SetupRoutes()
+
+	tests := []struct {
+		url          string
+		forecastType string
+		periods      int
+	}{
+		{"/api/v1/forecast/latest/USD/EUR", "exponential", 5},
+		{"/api/v1/forecast/latest/USD/GBP", "moving_average", 3},
+		{"/api/v1/forecast/latest/USD/GBP?type=linear&periods=2", "linear", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			var forecast models.ForecastResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &forecast); err != nil {
+				t.Fatalf("Failed to unmarshal forecast response: %v", err)
+			}
+			if forecast.ForecastType != tt.forecastType || len(forecast.Forecasts) != tt.periods {
+				t.Errorf("Expected %s forecast with %d periods, got %s with %d", tt.forecastType, tt.periods, forecast.ForecastType, len(forecast.Forecasts))
+			}
+		})
+	}
+}
+
+func TestHandlers_AmountsAsStrings(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85,"GBP":0.75}}`))
+	}))
+	defer upstream.Close()
+
+	loggerInstance := logger.New("error")
+	serve := func(amountsAsStrings bool) string {
+		cfg := &config.Config{
+			SupportedCurrencies:        []string{"USD", "EUR", "GBP"},
+			DefaultForecastPeriods:     1,
+			AmountsAsStrings:           amountsAsStrings,
+			CurrencyExchangeServiceURL: upstream.URL,
+		}
+		handlers := NewHandlers(HandlerConfig{Logger: loggerInstance, ForecastingService: service.NewForecastingService(cfg, loggerInstance), Config: cfg})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/forecast/latest/USD/EUR?amount=1000", nil)
+		handlers.SetupRoutes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		return w.Body.String()
+	}
+
+	// Each service encodes by its own setting; neither changes how the other encodes
+	if body := serve(true); !strings.Contains(body, `"amount":"1000.00"`) {
+		t.Errorf("Expected the amount as a string, got %s", body)
+	}
+	if body := serve(false); !strings.Contains(body, `"amount":1000.00`) {
+		t.Errorf("Expected the amount as a number, got %s", body)
+	}
+}
+
+func TestHandlers_ForecastHistory(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85,"GBP":0.75}}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR", "GBP"},
+		DefaultForecastPeriods:     5,
+		CurrencyExchangeServiceURL: upstream.URL,
+	}
+	loggerInstance := logger.New("error")
+	handlers := NewHandlers(HandlerConfig{
+		Logger:             loggerInstance,
+		ForecastingService: service.NewForecastingService(cfg, loggerInstance),
+		Config:             cfg,
+	})
+	router := handlers.SetupRoutes()
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	var generated []models.ForecastResponse
+	for _, target := range []string{"EUR", "GBP"} {
+		w := serve("POST", "/api/v1/forecast", `{"base_currency":"USD","target_currency":"`+target+`","amount":100}`)
+		var forecast models.ForecastResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &forecast); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("Expected forecast, got %d: %s", w.Code, w.Body.String())
+		}
+		if forecast.ForecastID == "" {
+			t.Fatal("Expected generated forecast to carry a forecast_id")
+		}
+		generated = append(generated, forecast)
+		// IDs order by millisecond; keep the two forecasts apart
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	w := serve("GET", "/api/v1/forecasts/"+generated[0].ForecastID, "")
+	var stored models.ForecastResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &stored); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Expected stored forecast, got %d: %s", w.Code, w.Body.String())
+	}
+	if stored.ForecastID != generated[0].ForecastID || stored.TargetCurrency != "EUR" || len(stored.Forecasts) != 5 {
+		t.Errorf("Expected stored forecast to match the generated one, got %+v", stored)
+	}
+
+	w = serve("GET", "/api/v1/forecasts/unknown", "")
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), service.CodeForecastNotFound) {
+		t.Errorf("Expected 404 %s, got %d: %s", service.CodeForecastNotFound, w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectedIDs  []string
+	}{
+		{"all newest first", "", http.StatusOK, []string{generated[1].ForecastID, generated[0].ForecastID}},
+		{"by pair", "?pair=usd/eur", http.StatusOK, []string{generated[0].ForecastID}},
+		{"by type and date", "?type=linear&from=2000-01-01", http.StatusOK, []string{generated[1].ForecastID, generated[0].ForecastID}},
+		{"window before any forecast", "?to=2000-01-01T00:00:00Z", http.StatusOK, []string{}},
+		{"invalid pair", "?pair=USDEUR", http.StatusBadRequest, nil},
+		{"invalid type", "?type=quadratic", http.StatusBadRequest, nil},
+		{"invalid from", "?from=yesterday", http.StatusBadRequest, nil},
+		{"invalid limit", "?limit=0", http.StatusBadRequest, nil},
+		{"inverted window", "?from=2024-02-01&to=2024-01-01", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve("GET", "/api/v1/forecasts"+tt.query, "")
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if tt.expectedIDs == nil {
+				return
+			}
+
+			var list models.ForecastListResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+				t.Fatalf("Failed to unmarshal list: %v", err)
+			}
+			if len(list.Forecasts) != len(tt.expectedIDs) {
+				t.Fatalf("Expected %d forecasts, got %d", len(tt.expectedIDs), len(list.Forecasts))
+			}
+			for i, id := range tt.expectedIDs {
+				if list.Forecasts[i].ForecastID != id {
+					t.Errorf("Expected %s at position %d, got %s", id, i, list.Forecasts[i].ForecastID)
+				}
+			}
+		})
+	}
+
+	// Pages chain through next_cursor
+	w = serve("GET", "/api/v1/forecasts?limit=1", "")
+	var firstPage models.ForecastListResponse
+	json.Unmarshal(w.Body.Bytes(), &firstPage)
+	if firstPage.NextCursor == "" {
+		t.Fatalf("Expected a next_cursor, got %s", w.Body.String())
+	}
+	w = serve("GET", "/api/v1/forecasts?limit=1&cursor="+firstPage.NextCursor, "")
+	var secondPage models.ForecastListResponse
+	json.Unmarshal(w.Body.Bytes(), &secondPage)
+	if len(secondPage.Forecasts) != 1 || secondPage.Forecasts[0].ForecastID != generated[0].ForecastID || secondPage.NextCursor != "" {
+		t.Errorf("Expected the older forecast on the last page, got %s", w.Body.String())
+	}
+}
+
+func TestHandlers_GetForecastAccuracy(t *testing.T) {
+	handlers := createTestHandlers()
+	router := handlers.SetupRoutes()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/forecast/accuracy?pair=usd/eur&type=linear", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var report models.AccuracyReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if report.WindowDays != 30 || report.Stats == nil || len(report.Stats) != 0 {
+		t.Errorf("Expected an empty 30 day report, got %+v", report)
+	}
+}
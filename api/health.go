@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/service"
+	"github.com/dalfonso89/financial-forecasting-service/version"
+)
+
+// defaultReadinessCheckTimeout bounds each dependency check when the config does not set one
+const defaultReadinessCheckTimeout = 2 * time.Second
+
+// Readiness states reported by /readyz
+const (
+	readinessReady    = "ready"
+	readinessDegraded = "degraded"
+	readinessNotReady = "not_ready"
+)
+
+// Livez handles liveness probes; it only reports that the process is serving requests
+func (handlers *Handlers) Livez(context *gin.Context) {
+	buildInfo := version.Get()
+	context.JSON(http.StatusOK, models.HealthCheck{
+		Status:    "alive",
+		Timestamp: time.Now(),
+		Version:   buildInfo.Version,
+		Commit:    buildInfo.Commit,
+		Uptime:    time.Since(handlers.startTime).String(),
+	})
+}
+
+// Readyz handles readiness probes by running every dependency check concurrently with a per-check timeout
+func (handlers *Handlers) Readyz(context *gin.Context) {
+	checks := handlers.forecastingService.DependencyChecks()
+	timeout := handlers.readinessCheckTimeout()
+
+	results := make(map[string]models.DependencyStatus, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check service.DependencyCheck) {
+			defer wg.Done()
+			result := runDependencyCheck(context.Request.Context(), check, timeout)
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	for name, result := range results {
+		if result.Status != "up" {
+			handlers.logger.WithContext(context.Request.Context()).Warnf("Readiness check %s failed: %s", name, result.Error)
+		}
+	}
+
+	status := readinessStatus(results)
+
+	statusCode := http.StatusOK
+	if status == readinessNotReady {
+		statusCode = http.StatusServiceUnavailable
+	}
+	context.JSON(statusCode, models.ReadinessResponse{
+		Status:    status,
+		Timestamp: time.Now(),
+		Version:   version.String(),
+		Checks:    results,
+	})
+}
+
+// readinessCheckTimeout returns the configured per-check timeout
+func (handlers *Handlers) readinessCheckTimeout() time.Duration {
+	if handlers.config == nil || handlers.config.ReadinessCheckTimeout <= 0 {
+		return defaultReadinessCheckTimeout
+	}
+	return handlers.config.ReadinessCheckTimeout
+}
+
+// readinessStatus is not_ready when a critical check fails, degraded when only optional checks fail, else ready
+func readinessStatus(results map[string]models.DependencyStatus) string {
+	status := readinessReady
+	for _, result := range results {
+		if result.Status == "up" {
+			continue
+		}
+		if result.Critical {
+			return readinessNotReady
+		}
+		status = readinessDegraded
+	}
+	return status
+}
+
+// runDependencyCheck runs one check, abandoning it once the timeout elapses
+func runDependencyCheck(parent context.Context, check service.DependencyCheck, timeout time.Duration) models.DependencyStatus {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", timeout)
+	}
+
+	result := models.DependencyStatus{
+		Status:   "up",
+		Critical: check.Critical,
+		Latency:  time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = "down"
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dalfonso89/financial-forecasting-service/config"
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/service"
+	"github.com/dalfonso89/financial-forecasting-service/version"
+)
+
+// createProbeHandlers creates handlers whose currency service is the given URL
+func createProbeHandlers(upstreamURL string, timeout time.Duration) *Handlers {
+	gin.SetMode(gin.TestMode)
+	loggerInstance := logger.New("error")
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR"},
+		CurrencyExchangeServiceURL: upstreamURL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+		ReadinessCheckTimeout:      timeout,
+	}
+	return NewHandlers(HandlerConfig{
+		Logger:             loggerInstance,
+		ForecastingService: service.NewForecastingService(cfg, loggerInstance),
+		Config:             cfg,
+	})
+}
+
+func TestHandlers_Livez(t *testing.T) {
+	handlers := createProbeHandlers("http://127.0.0.1:0", time.Second)
+	router := handlers.SetupRoutes()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/livez", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 even with the upstream down, got %d", w.Code)
+	}
+
+	var response models.HealthCheck
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.Status != "alive" {
+		t.Errorf("Expected status 'alive', got '%s'", response.Status)
+	}
+	if response.Version != version.String() {
+		t.Errorf("Expected version '%s', got '%s'", version.String(), response.Version)
+	}
+}
+
+func TestHandlers_Readyz(t *testing.T) {
+	tests := []struct {
+		name           string
+		upstream       http.HandlerFunc
+		timeout        time.Duration
+		expectedCode   int
+		expectedStatus string
+		expectedError  bool
+	}{
+		{
+			name: "upstream healthy",
+			upstream: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			timeout:        time.Second,
+			expectedCode:   http.StatusOK,
+			expectedStatus: "ready",
+		},
+		{
+			name: "upstream unhealthy",
+			upstream: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			timeout:        time.Second,
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: "not_ready",
+			expectedError:  true,
+		},
+		{
+			name: "upstream slower than check timeout",
+			upstream: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(2 * time.Second):
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+			timeout:        50 * time.Millisecond,
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: "not_ready",
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := httptest.NewServer(tt.upstream)
+			defer upstream.Close()
+
+			router := createProbeHandlers(upstream.URL, tt.timeout).SetupRoutes()
+
+			start := time.Now()
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/readyz", nil)
+			router.ServeHTTP(w, req)
+
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Expected readiness to respect the check timeout, took %s", elapsed)
+			}
+			if w.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			var response models.ReadinessResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if response.Status != tt.expectedStatus {
+				t.Errorf("Expected status '%s', got '%s'", tt.expectedStatus, response.Status)
+			}
+
+			upstreamCheck, ok := response.Checks["currency_exchange_service"]
+			if !ok {
+				t.Fatalf("Expected currency_exchange_service check, got %+v", response.Checks)
+			}
+			if !upstreamCheck.Critical {
+				t.Error("Expected currency_exchange_service check to be critical")
+			}
+			if (upstreamCheck.Error != "") != tt.expectedError {
+				t.Errorf("Expected error %v, got %q", tt.expectedError, upstreamCheck.Error)
+			}
+			if response.Checks["forecast_cache"].Status != "up" {
+				t.Errorf("Expected forecast_cache check to be up, got %+v", response.Checks["forecast_cache"])
+			}
+		})
+	}
+}
+
+func TestReadinessStatus(t *testing.T) {
+	up := models.DependencyStatus{Status: "up"}
+	optionalDown := models.DependencyStatus{Status: "down"}
+	criticalDown := models.DependencyStatus{Status: "down", Critical: true}
+
+	tests := []struct {
+		name     string
+		results  map[string]models.DependencyStatus
+		expected string
+	}{
+		{"all up", map[string]models.DependencyStatus{"a": up, "b": up}, "ready"},
+		{"no checks", map[string]models.DependencyStatus{}, "ready"},
+		{"optional down", map[string]models.DependencyStatus{"a": up, "b": optionalDown}, "degraded"},
+		{"critical down", map[string]models.DependencyStatus{"a": criticalDown, "b": up}, "not_ready"},
+		{"critical and optional down", map[string]models.DependencyStatus{"a": criticalDown, "b": optionalDown}, "not_ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readinessStatus(tt.results); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRunDependencyCheck_Timeout(t *testing.T) {
+	result := runDependencyCheck(context.Background(), service.DependencyCheck{
+		Name:     "optional",
+		Critical: false,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}, 10*time.Millisecond)
+
+	if result.Status != "down" {
+		t.Errorf("Expected status 'down', got '%s'", result.Status)
+	}
+	if result.Critical {
+		t.Error("Expected non-critical result")
+	}
+	if result.Error == "" {
+		t.Error("Expected timeout error to be reported")
+	}
+}
@@ -0,0 +1,421 @@
+package api
+
+import (
+	"embed"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
+	"github.com/dalfonso89/financial-forecasting-service/version"
+)
+
+// redocVersion is the Redoc release the go:generate directive below vendors into docs/; keep the two in step
+const redocVersion = "2.1.5"
+
+//go:generate curl -fsSL -o docs/redoc.standalone.js https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js
+
+// docsFiles holds the documentation page and the vendored Redoc bundle it loads
+//
+//go:embed docs
+var docsFiles embed.FS
+
+// docsContentSecurityPolicy lets the docs page run only the bundle served by the service; Redoc injects its styles
+// inline and renders in a worker
+const docsContentSecurityPolicy = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; " +
+	"img-src 'self' data:; worker-src blob:"
+
+// OpenAPIDocument is the root of an OpenAPI 3.1 document
+type OpenAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       OpenAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                      `json:"components"`
+}
+
+// OpenAPIInfo describes the API
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIOperation describes a single route
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter describes a path or query parameter
+type OpenAPIParameter struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Required    bool          `json:"required"`
+	Description string        `json:"description,omitempty"`
+	Schema      OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody describes a JSON request body
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse describes a response
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType wraps a schema for a content type
+type OpenAPIMediaType struct {
+	Schema OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIComponents holds the reusable schemas
+type OpenAPIComponents struct {
+	Schemas map[string]OpenAPISchema `json:"schemas"`
+}
+
+// OpenAPISchema is a JSON Schema object
+type OpenAPISchema map[string]interface{}
+
+// routeSpec documents a route registered in SetupRoutes
+type routeSpec struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tag         string
+	Query       []OpenAPIParameter
+	Request     interface{}
+	Response    interface{}
+	Status      int
+}
+
+// routeSpecs lists every route served by SetupRoutes; keep it in sync with the router
+func routeSpecs() []routeSpec {
+	periodsParam := queryParam("periods", "Number of periods", OpenAPISchema{"type": "integer", "default": 30})
+
+	return []routeSpec{
+		{Method: http.MethodGet, Path: "/health", OperationID: "healthCheck", Summary: "Service health status", Tag: "health", Response: models.HealthCheck{}},
+		{Method: http.MethodGet, Path: "/livez", OperationID: "livenessProbe", Summary: "Liveness probe", Tag: "health", Response: models.HealthCheck{}},
+		{Method: http.MethodGet, Path: "/readyz", OperationID: "readinessProbe", Summary: "Readiness probe with dependency checks", Tag: "health", Response: models.ReadinessResponse{}},
+		{Method: http.MethodGet, Path: "/metrics", OperationID: "getMetrics", Summary: "Prometheus metrics", Tag: "health"},
+		{Method: http.MethodGet, Path: "/openapi.json", OperationID: "getOpenAPISpec", Summary: "OpenAPI specification", Tag: "docs"},
+		{Method: http.MethodGet, Path: "/docs", OperationID: "getAPIDocs", Summary: "Interactive API documentation", Tag: "docs"},
+		{Method: http.MethodGet, Path: "/docs/redoc.standalone.js", OperationID: "getAPIDocsScript", Summary: "Redoc bundle used by the documentation page", Tag: "docs"},
+		{Method: http.MethodPost, Path: "/api/v1/forecast", OperationID: "generateForecast", Summary: "Generate single currency forecast", Tag: "forecast",
+			Request: models.ForecastRequest{}, Response: models.ForecastResponse{}},
+		{Method: http.MethodPost, Path: "/api/v1/forecast/multi-currency", OperationID: "generateMultiCurrencyForecast", Summary: "Generate multi-currency forecast", Tag: "forecast",
+			Request: models.MultiCurrencyForecastRequest{}, Response: models.MultiCurrencyForecastResponse{}},
+		{Method: http.MethodPost, Path: "/api/v1/forecast/portfolio", OperationID: "generatePortfolioForecast", Summary: "Forecast the value of a multi-currency portfolio", Tag: "forecast",
+			Request: models.PortfolioForecastRequest{}, Response: models.PortfolioForecastResponse{}},
+		{Method: http.MethodPost, Path: "/api/v1/forecast/scenario", OperationID: "generateScenarioForecast", Summary: "Forecast currencies under shocks or a historical stress scenario", Tag: "forecast",
+			Request: models.ScenarioForecastRequest{}, Response: models.ScenarioForecastResponse{}},
+		{Method: http.MethodGet, Path: "/api/v1/forecast/scenarios", OperationID: "getStressScenarios", Summary: "Library of historical stress scenarios", Tag: "forecast", Response: models.StressScenarioListResponse{}},
+		{Method: http.MethodPost, Path: "/api/v1/forecast/hedging", OperationID: "generateHedgingAnalysis", Summary: "Forward rates and the cost of hedging against the forecast", Tag: "forecast",
+			Request: models.HedgingRequest{}, Response: models.HedgingResponse{}},
+		{Method: http.MethodGet, Path: "/api/v1/forecast/trend/:base/:target", OperationID: "analyzeTrend", Summary: "Analyze currency trend", Tag: "forecast",
+			Query: []OpenAPIParameter{periodsParam}, Response: models.TrendAnalysis{}},
+		{Method: http.MethodGet, Path: "/api/v1/forecast/latest/:base/:target", OperationID: "getLatestForecast", Summary: "Forecast based on latest exchange rates", Tag: "forecast",
+			Query: []OpenAPIParameter{
+				queryParam("amount", "Amount to forecast", OpenAPISchema{"type": "number", "default": 1000}),
+				queryParam("periods", "Number of periods; defaults to the pair's configured periods", OpenAPISchema{"type": "integer"}),
+				queryParam("type", "Forecast type; defaults to the pair's configured type", OpenAPISchema{"type": "string", "enum": []string{"linear", "exponential", "moving_average"}}),
+				queryParam("calendar", "Calendar the forecast periods step over", OpenAPISchema{"type": "string", "enum": []string{"calendar", "weekdays", "business"}, "default": "calendar"}),
+				queryParam("frequency", "Length of one forecast period", OpenAPISchema{"type": "string", "enum": []string{"hourly", "daily", "weekly", "monthly", "quarterly"}, "default": "daily"}),
+				queryParam("start_date", "Date (YYYY-MM-DD) or RFC 3339 timestamp the periods count from; defaults to now", OpenAPISchema{"type": "string"}),
+				queryParam("timezone", "IANA timezone of the period dates", OpenAPISchema{"type": "string", "default": "UTC"}),
+			},
+			Response: models.ForecastResponse{}},
+		{Method: http.MethodDelete, Path: "/api/v1/forecast/cache", OperationID: "clearCache", Summary: "Clear forecast cache", Tag: "forecast"},
+		{Method: http.MethodGet, Path: "/api/v1/forecast/accuracy", OperationID: "getForecastAccuracy", Summary: "Rolling forecast accuracy against realized rates", Tag: "forecast",
+			Query: []OpenAPIParameter{
+				queryParam("pair", "Currency pair such as USD/EUR", OpenAPISchema{"type": "string"}),
+				queryParam("type", "Forecast type", OpenAPISchema{"type": "string", "enum": []string{"linear", "exponential", "moving_average"}}),
+			},
+			Response: models.AccuracyReport{}},
+		{Method: http.MethodGet, Path: "/api/v1/forecasts", OperationID: "listForecasts", Summary: "List stored forecasts, newest first", Tag: "history",
+			Query: []OpenAPIParameter{
+				queryParam("pair", "Currency pair such as USD/EUR", OpenAPISchema{"type": "string"}),
+				queryParam("type", "Forecast type", OpenAPISchema{"type": "string", "enum": []string{"linear", "exponential", "moving_average"}}),
+				queryParam("from", "Earliest generated_at, inclusive (RFC 3339 or YYYY-MM-DD)", OpenAPISchema{"type": "string"}),
+				queryParam("to", "Latest generated_at, exclusive (RFC 3339 or YYYY-MM-DD)", OpenAPISchema{"type": "string"}),
+				queryParam("limit", "Page size", OpenAPISchema{"type": "integer", "default": 50, "maximum": 500}),
+				queryParam("cursor", "next_cursor from the previous page", OpenAPISchema{"type": "string"}),
+			},
+			Response: models.ForecastListResponse{}},
+		{Method: http.MethodGet, Path: "/api/v1/forecasts/:id", OperationID: "getForecast", Summary: "Get a stored forecast by ID", Tag: "history", Response: models.ForecastResponse{}},
+		{Method: http.MethodGet, Path: "/api/v1/currencies", OperationID: "getSupportedCurrencies", Summary: "Supported currencies with ISO 4217 details", Tag: "currencies", Response: models.CurrencyListResponse{}},
+		{Method: http.MethodGet, Path: "/api/v1/currencies/:code", OperationID: "getCurrency", Summary: "ISO 4217 details of a currency", Tag: "currencies", Response: models.Currency{}},
+		{Method: http.MethodGet, Path: "/api/v1/currencies/rates/:base", OperationID: "getCurrentRates", Summary: "Current exchange rates", Tag: "currencies"},
+		{Method: http.MethodPost, Path: "/api/v1/risk/var", OperationID: "calculateValueAtRisk", Summary: "Value at risk and expected shortfall of currency positions", Tag: "risk",
+			Request: models.ValueAtRiskRequest{}, Response: models.ValueAtRiskResponse{}},
+		{Method: http.MethodGet, Path: "/api/v1/analytics/correlation", OperationID: "getCorrelation", Summary: "Correlation and covariance of supported currencies against a base", Tag: "analytics",
+			Query: []OpenAPIParameter{
+				queryParam("base", "Currency the rates are quoted against", OpenAPISchema{"type": "string"}),
+				queryParam("window", "Days of rate history in each estimate", OpenAPISchema{"type": "integer", "default": 90, "minimum": 2, "maximum": 3650}),
+				queryParam("method", "Covariance estimator", OpenAPISchema{"type": "string", "enum": []string{"sample", "ewma"}, "default": "sample"}),
+				queryParam("lambda", "EWMA decay factor", OpenAPISchema{"type": "number", "default": 0.94}),
+				queryParam("windows", "Number of rolling windows", OpenAPISchema{"type": "integer", "default": 1, "maximum": 60}),
+				queryParam("step", "Days between the ends of successive rolling windows", OpenAPISchema{"type": "integer", "default": 7}),
+			},
+			Response: models.CorrelationResponse{}},
+	}
+}
+
+// queryParam creates an optional query parameter
+func queryParam(name, description string, schema OpenAPISchema) OpenAPIParameter {
+	return OpenAPIParameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// pathParamPattern matches gin path parameters such as ":base"
+var pathParamPattern = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// openAPIPath converts a gin route path to OpenAPI path template syntax
+func openAPIPath(ginPath string) string {
+	return pathParamPattern.ReplaceAllString(ginPath, "{$1}")
+}
+
+// BuildOpenAPIDocument generates the OpenAPI document from the route table and models
+func BuildOpenAPIDocument() OpenAPIDocument {
+	builder := newSchemaBuilder()
+	problemRef := builder.schemaFor(reflect.TypeOf(models.ProblemDetails{}))
+	legacyErrorRef := builder.schemaFor(reflect.TypeOf(models.ErrorResponse{}))
+
+	document := OpenAPIDocument{
+		OpenAPI: "3.1.0",
+		Info: OpenAPIInfo{
+			Title:       "Financial Forecasting Service",
+			Description: "Currency exchange rate forecasting and trend analysis",
+			Version:     version.String(),
+		},
+		Paths: make(map[string]map[string]OpenAPIOperation),
+	}
+
+	for _, route := range routeSpecs() {
+		operation := OpenAPIOperation{
+			OperationID: route.OperationID,
+			Summary:     route.Summary,
+			Tags:        []string{route.Tag},
+			Responses: map[string]OpenAPIResponse{
+				"default": {
+					Description: "Error",
+					Content: map[string]OpenAPIMediaType{
+						problemContentType: {Schema: problemRef},
+						legacyContentType:  {Schema: legacyErrorRef},
+					},
+				},
+			},
+		}
+
+		for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+			operation.Parameters = append(operation.Parameters, OpenAPIParameter{
+				Name: match[1], In: "path", Required: true, Schema: OpenAPISchema{"type": "string"},
+			})
+		}
+		operation.Parameters = append(operation.Parameters, route.Query...)
+
+		if route.Request != nil {
+			operation.RequestBody = &OpenAPIRequestBody{
+				Required: true,
+				Content: map[string]OpenAPIMediaType{
+					legacyContentType: {Schema: builder.schemaFor(reflect.TypeOf(route.Request))},
+				},
+			}
+		}
+
+		responseSchema := OpenAPISchema{"type": "object"}
+		if route.Response != nil {
+			responseSchema = builder.schemaFor(reflect.TypeOf(route.Response))
+		}
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		operation.Responses[strconv.Itoa(status)] = OpenAPIResponse{
+			Description: http.StatusText(status),
+			Content:     map[string]OpenAPIMediaType{legacyContentType: {Schema: responseSchema}},
+		}
+
+		path := openAPIPath(route.Path)
+		if document.Paths[path] == nil {
+			document.Paths[path] = make(map[string]OpenAPIOperation)
+		}
+		document.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	document.Components = OpenAPIComponents{Schemas: builder.schemas}
+	return document
+}
+
+// schemaBuilder derives JSON schemas from Go types and their json and binding tags
+type schemaBuilder struct {
+	schemas map[string]OpenAPISchema
+}
+
+// newSchemaBuilder creates an empty schema builder
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{schemas: make(map[string]OpenAPISchema)}
+}
+
+// schemaFor returns the schema for a type, registering named structs as components
+func (builder *schemaBuilder) schemaFor(t reflect.Type) OpenAPISchema {
+	if t.Kind() == reflect.Ptr {
+		return builder.schemaFor(t.Elem())
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return OpenAPISchema{"type": "string", "format": "date-time"}
+	}
+	if t == reflect.TypeOf(money.Decimal{}) {
+		// Encoded as a number, or as a string when AMOUNTS_AS_STRINGS is set
+		return OpenAPISchema{"oneOf": []OpenAPISchema{{"type": "number"}, {"type": "string", "format": "decimal"}}}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return OpenAPISchema{"type": "string"}
+	case reflect.Bool:
+		return OpenAPISchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return OpenAPISchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return OpenAPISchema{"type": "number", "format": "double"}
+	case reflect.Slice, reflect.Array:
+		return OpenAPISchema{"type": "array", "items": builder.schemaFor(t.Elem())}
+	case reflect.Map:
+		return OpenAPISchema{"type": "object", "additionalProperties": builder.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if _, exists := builder.schemas[t.Name()]; !exists {
+			// Reserve the name first so recursive types terminate
+			builder.schemas[t.Name()] = OpenAPISchema{}
+			builder.schemas[t.Name()] = builder.structSchema(t)
+		}
+		return OpenAPISchema{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return OpenAPISchema{}
+	}
+}
+
+// structSchema builds an object schema from exported struct fields
+func (builder *schemaBuilder) structSchema(t reflect.Type) OpenAPISchema {
+	properties := make(map[string]OpenAPISchema)
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := builder.schemaFor(field.Type)
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			if rule == "required" {
+				required = append(required, name)
+				continue
+			}
+			applyBindingRule(schema, field.Type, rule)
+		}
+		properties[name] = schema
+	}
+
+	schema := OpenAPISchema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// applyBindingRule maps a validator rule such as "gt=0" or "min=1" onto schema keywords
+func applyBindingRule(schema OpenAPISchema, t reflect.Type, rule string) {
+	name, param, found := strings.Cut(rule, "=")
+	if !found {
+		return
+	}
+	if name == "oneof" {
+		schema["enum"] = strings.Fields(param)
+		return
+	}
+
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		switch name {
+		case "min":
+			schema["minItems"] = value
+		case "max":
+			schema["maxItems"] = value
+		}
+	case reflect.String:
+		switch name {
+		case "min":
+			schema["minLength"] = value
+		case "max":
+			schema["maxLength"] = value
+		case "len":
+			schema["minLength"] = value
+			schema["maxLength"] = value
+		}
+	default:
+		switch name {
+		case "gt":
+			schema["exclusiveMinimum"] = value
+		case "gte", "min":
+			schema["minimum"] = value
+		case "lt":
+			schema["exclusiveMaximum"] = value
+		case "lte", "max":
+			schema["maximum"] = value
+		}
+	}
+}
+
+// OpenAPISpec serves the generated OpenAPI document
+func (handlers *Handlers) OpenAPISpec(context *gin.Context) {
+	context.JSON(http.StatusOK, BuildOpenAPIDocument())
+}
+
+// APIDocs serves an embedded Redoc page rendering the OpenAPI document
+func (handlers *Handlers) APIDocs(context *gin.Context) {
+	page, err := docsFiles.ReadFile("docs/index.html")
+	if err != nil {
+		handlers.writeErrorResponse(context, http.StatusInternalServerError, "documentation unavailable", err.Error())
+		return
+	}
+	context.Header("Content-Security-Policy", docsContentSecurityPolicy)
+	context.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
+
+// APIDocsScript serves the vendored Redoc bundle, cached for a day since its contents only change with redocVersion
+func (handlers *Handlers) APIDocsScript(context *gin.Context) {
+	bundle, err := docsFiles.ReadFile("docs/redoc.standalone.js")
+	if err != nil {
+		handlers.writeErrorResponse(context, http.StatusNotFound, "documentation bundle not vendored", "run `go generate ./api` to vendor Redoc "+redocVersion)
+		return
+	}
+	context.Header("Cache-Control", "public, max-age=86400")
+	context.Data(http.StatusOK, "application/javascript; charset=utf-8", bundle)
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dalfonso89/financial-forecasting-service/version"
+)
+
+var updateGolden = flag.Bool("update", false, "update the OpenAPI golden file")
+
+func TestOpenAPI_RoutesMatchRouter(t *testing.T) {
+	handlers := createTestHandlers()
+	router := handlers.SetupRoutes()
+	document := BuildOpenAPIDocument()
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		key := strings.ToLower(route.Method) + " " + openAPIPath(route.Path)
+		registered[key] = true
+
+		operations, exists := document.Paths[openAPIPath(route.Path)]
+		if !exists {
+			t.Errorf("Route %s %s is missing from the OpenAPI document", route.Method, route.Path)
+			continue
+		}
+		if _, exists := operations[strings.ToLower(route.Method)]; !exists {
+			t.Errorf("Operation %s %s is missing from the OpenAPI document", route.Method, route.Path)
+		}
+	}
+
+	for path, operations := range document.Paths {
+		for method := range operations {
+			if !registered[method+" "+path] {
+				t.Errorf("OpenAPI document describes %s %s which is not registered in SetupRoutes", method, path)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_MatchesGoldenFile(t *testing.T) {
+	generated, err := json.MarshalIndent(BuildOpenAPIDocument(), "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal OpenAPI document: %v", err)
+	}
+	generated = append(generated, '\n')
+
+	goldenPath := filepath.Join("testdata", "openapi.golden.json")
+	if *updateGolden {
+		if err := os.WriteFile(goldenPath, generated, 0o644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+
+	golden, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(golden, generated) {
+		t.Error("OpenAPI document drifted from testdata/openapi.golden.json; review the change and run `go test ./api -run OpenAPI -update`")
+	}
+}
+
+func TestOpenAPI_SchemaFromBindingTags(t *testing.T) {
+	document := BuildOpenAPIDocument()
+
+	schema, exists := document.Components.Schemas["ForecastRequest"]
+	if !exists {
+		t.Fatal("Expected ForecastRequest schema")
+	}
+
+	required, _ := schema["required"].([]string)
+	if strings.Join(required, ",") != "base_currency,target_currency,amount" {
+		t.Errorf("Unexpected required fields: %v", required)
+	}
+
+	properties := schema["properties"].(map[string]OpenAPISchema)
+	if properties["amount"]["exclusiveMinimum"] != float64(0) {
+		t.Errorf("Expected amount to have exclusiveMinimum 0, got %v", properties["amount"])
+	}
+
+	multiSchema := document.Components.Schemas["MultiCurrencyForecastRequest"]
+	multiProperties := multiSchema["properties"].(map[string]OpenAPISchema)
+	if multiProperties["currencies"]["minItems"] != float64(1) {
+		t.Errorf("Expected currencies to have minItems 1, got %v", multiProperties["currencies"])
+	}
+}
+
+func TestHandlers_OpenAPISpec(t *testing.T) {
+	handlers := createTestHandlers()
+	router := handlers.SetupRoutes()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/openapi.json", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	var document OpenAPIDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &document); err != nil {
+		t.Fatalf("Failed to unmarshal OpenAPI document: %v", err)
+	}
+	if document.OpenAPI != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %s", document.OpenAPI)
+	}
+	if _, exists := document.Paths["/api/v1/forecast/trend/{base}/{target}"]; !exists {
+		t.Error("Expected path parameters to use OpenAPI template syntax")
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/docs", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for docs page, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "/openapi.json") {
+		t.Error("Expected docs page to reference /openapi.json")
+	}
+	if !strings.Contains(w.Body.String(), `src="/docs/redoc.standalone.js"`) {
+		t.Error("Expected docs page to load the Redoc bundle served by the service")
+	}
+	if csp := w.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "script-src 'self';") || strings.Contains(csp, "https:") {
+		t.Errorf("Expected docs page to allow only same-origin scripts, got %q", csp)
+	}
+
+	// The bundle is served from the embedded files once vendored with go generate
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/docs/redoc.standalone.js", nil)
+	router.ServeHTTP(w, req)
+
+	expected := http.StatusNotFound
+	if _, err := docsFiles.ReadFile("docs/redoc.standalone.js"); err == nil {
+		expected = http.StatusOK
+	}
+	if w.Code != expected {
+		t.Errorf("Expected status %d for the Redoc bundle, got %d", expected, w.Code)
+	}
+}
+
+func TestBuildOpenAPIDocument_Version(t *testing.T) {
+	if document := BuildOpenAPIDocument(); document.Info.Version != version.String() {
+		t.Errorf("Expected the build version %s, got %s", version.String(), document.Info.Version)
+	}
+}
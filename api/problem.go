@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+)
+
+const (
+	// problemContentType is the RFC 7807 media type for error responses
+	problemContentType = "application/problem+json"
+	// legacyContentType selects the legacy ErrorResponse shape when requested explicitly
+	legacyContentType = "application/json"
+	// problemTypeBase is the base URI reference for problem types
+	problemTypeBase = "/problems/"
+)
+
+// writeProblem writes an error as application/problem+json, or as the legacy
+// ErrorResponse shape when the client explicitly asks for application/json
+func (handlers *Handlers) writeProblem(context *gin.Context, statusCode int, errorCode, title, detail string, fieldErrors []models.FieldError) {
+	if context.NegotiateFormat(problemContentType, legacyContentType) == legacyContentType {
+		context.JSON(statusCode, models.ErrorResponse{
+			Error:     title,
+			Message:   detail,
+			Code:      statusCode,
+			ErrorCode: errorCode,
+			RequestID: context.GetString("request_id"),
+		})
+		return
+	}
+
+	problem := models.ProblemDetails{
+		Type:      problemTypeBase + errorCode,
+		Title:     title,
+		Status:    statusCode,
+		Detail:    detail,
+		Instance:  context.GetString("request_id"),
+		ErrorCode: errorCode,
+		RequestID: context.GetString("request_id"),
+		Errors:    fieldErrors,
+	}
+
+	// gin keeps an explicitly set Content-Type when rendering JSON
+	context.Header("Content-Type", problemContentType)
+	context.JSON(statusCode, problem)
+}
+
+// writeBindingError translates a request binding failure into a problem response with field violations
+func (handlers *Handlers) writeBindingError(context *gin.Context, err error, target interface{}) {
+	var validationErrors validator.ValidationErrors
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &validationErrors):
+		fieldErrors := make([]models.FieldError, 0, len(validationErrors))
+		for _, fieldErr := range validationErrors {
+			field := jsonFieldName(reflect.TypeOf(target), fieldErr)
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   field,
+				Message: validationMessage(field, fieldErr),
+			})
+		}
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid request", "request validation failed", fieldErrors...)
+	case errors.As(err, &typeErr):
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid request", "request body has an invalid field type", models.FieldError{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("%s must be a %s", typeErr.Field, typeErr.Type.Kind()),
+		})
+	case errors.As(err, &syntaxErr):
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid request", "request body is not valid JSON")
+	case errors.Is(err, io.EOF):
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid request", "request body is empty")
+	default:
+		handlers.writeErrorResponse(context, http.StatusBadRequest, "invalid request", err.Error())
+	}
+}
+
+// jsonFieldName resolves the JSON path of a failed field from the struct's json tags
+func jsonFieldName(targetType reflect.Type, fieldErr validator.FieldError) string {
+	segments := strings.Split(fieldErr.StructNamespace(), ".")
+	names := make([]string, 0, len(segments))
+
+	currentType := targetType
+	for _, segment := range segments[1:] {
+		name, index := segment, ""
+		if i := strings.Index(segment, "["); i >= 0 {
+			name, index = segment[:i], segment[i:]
+		}
+
+		for currentType != nil && (currentType.Kind() == reflect.Ptr || currentType.Kind() == reflect.Slice ||
+			currentType.Kind() == reflect.Array || currentType.Kind() == reflect.Map) {
+			currentType = currentType.Elem()
+		}
+
+		jsonName := strings.ToLower(name)
+		if currentType != nil && currentType.Kind() == reflect.Struct {
+			if field, ok := currentType.FieldByName(name); ok {
+				if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+					jsonName = tag
+				}
+				currentType = field.Type
+			}
+		}
+		names = append(names, jsonName+index)
+	}
+
+	return strings.Join(names, ".")
+}
+
+// validationMessage renders a human-readable message for a validator failure
+func validationMessage(field string, fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "gt":
+		return fmt.Sprintf("%s must be > %s", field, param)
+	case "gte":
+		return fmt.Sprintf("%s must be >= %s", field, param)
+	case "lt":
+		return fmt.Sprintf("%s must be < %s", field, param)
+	case "lte":
+		return fmt.Sprintf("%s must be <= %s", field, param)
+	case "min", "max":
+		bound := "at least"
+		if fieldErr.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fieldErr.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s must contain %s %s items", field, bound, param)
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters", field, bound, param)
+		}
+		return fmt.Sprintf("%s must be %s %s", field, bound, param)
+	case "len":
+		return fmt.Sprintf("%s must have length %s", field, param)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(param, " ", ", "))
+	default:
+		return fmt.Sprintf("%s failed %s validation", field, fieldErr.Tag())
+	}
+}
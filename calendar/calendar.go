@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Calendar names accepted in forecast requests
+const (
+	// Daily steps every calendar day
+	Daily = "calendar"
+	// Weekdays skips Saturdays and Sundays
+	Weekdays = "weekdays"
+	// Business skips the weekends and holidays of both currencies in the pair
+	Business = "business"
+)
+
+// dateLayout formats the date keys of holiday tables
+const dateLayout = "2006-01-02"
+
+// Calendar decides which dates are business days
+type Calendar struct {
+	name    string
+	weekend map[time.Weekday]bool
+	rules   []holidayRule
+	members []*Calendar // Set for joint calendars, which close whenever any member does
+
+	mu       sync.Mutex
+	holidays map[int]map[string]string // Year to date to holiday name
+}
+
+// newCalendar creates a calendar from weekend days and holiday rules
+func newCalendar(name string, weekend []time.Weekday, rules []holidayRule) *Calendar {
+	calendar := &Calendar{name: name, weekend: make(map[time.Weekday]bool), rules: rules, holidays: make(map[int]map[string]string)}
+	for _, day := range weekend {
+		calendar.weekend[day] = true
+	}
+	return calendar
+}
+
+// WeekendsOnly returns a calendar that closes on Saturdays and Sundays only
+func WeekendsOnly() *Calendar {
+	return newCalendar("weekdays", []time.Weekday{time.Saturday, time.Sunday}, nil)
+}
+
+// ForCurrency returns the settlement calendar of a currency; currencies without a holiday table close on weekends only
+func ForCurrency(currency string) *Calendar {
+	currency = strings.ToUpper(currency)
+	if calendar, ok := currencyCalendars[currency]; ok {
+		return calendar
+	}
+	calendar := WeekendsOnly()
+	calendar.name = currency
+	return calendar
+}
+
+// ForPair returns the joint calendar of a currency pair
+func ForPair(base, target string) *Calendar {
+	return Joint(ForCurrency(base), ForCurrency(target))
+}
+
+// Joint returns a calendar whose business days are business days in every member
+func Joint(members ...*Calendar) *Calendar {
+	names := make([]string, len(members))
+	for i, member := range members {
+		names[i] = member.name
+	}
+	return &Calendar{name: strings.Join(names, "+"), members: members}
+}
+
+// Currencies lists the currencies with holiday tables
+func Currencies() []string {
+	currencies := make([]string, 0, len(currencyCalendars))
+	for currency := range currencyCalendars {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Name identifies the calendar, such as "USD" or "USD+EUR"
+func (c *Calendar) Name() string {
+	return c.name
+}
+
+// IsBusinessDay reports whether date is neither a weekend day nor a holiday
+func (c *Calendar) IsBusinessDay(date time.Time) bool {
+	if len(c.members) > 0 {
+		for _, member := range c.members {
+			if !member.IsBusinessDay(date) {
+				return false
+			}
+		}
+		return true
+	}
+	if c.weekend[date.Weekday()] {
+		return false
+	}
+	_, holiday := c.Holiday(date)
+	return !holiday
+}
+
+// Holiday returns the name of the holiday on date, if any
+func (c *Calendar) Holiday(date time.Time) (string, bool) {
+	if len(c.members) > 0 {
+		for _, member := range c.members {
+			if name, ok := member.Holiday(date); ok {
+				return name, true
+			}
+		}
+		return "", false
+	}
+	name, ok := c.holidaysIn(date.Year())[date.Format(dateLayout)]
+	return name, ok
+}
+
+// AddBusinessDays moves forward n business days from date; the start date itself is not counted
+func (c *Calendar) AddBusinessDays(date time.Time, n int) time.Time {
+	for n > 0 {
+		date = date.AddDate(0, 0, 1)
+		if c.IsBusinessDay(date) {
+			n--
+		}
+	}
+	return date
+}
+
+// ModifiedFollowing rolls date forward to the next business day, or back to the previous one when rolling forward would change the month
+func (c *Calendar) ModifiedFollowing(date time.Time) time.Time {
+	adjusted := date
+	for !c.IsBusinessDay(adjusted) {
+		adjusted = adjusted.AddDate(0, 0, 1)
+	}
+	if adjusted.Month() == date.Month() {
+		return adjusted
+	}
+	for adjusted = date; !c.IsBusinessDay(adjusted); {
+		adjusted = adjusted.AddDate(0, 0, -1)
+	}
+	return adjusted
+}
+
+// holidaysIn returns the holidays observed in a year, computing them on first use
+func (c *Calendar) holidaysIn(year int) map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if holidays, ok := c.holidays[year]; ok {
+		return holidays
+	}
+	holidays := observeHolidays(year, c.rules, c.weekend)
+	c.holidays[year] = holidays
+	return holidays
+}
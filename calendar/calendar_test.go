@@ -0,0 +1,167 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+// date builds a UTC date for tests
+func date(value string) time.Time {
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestLoadCalendars(t *testing.T) {
+	calendars, err := loadCalendars()
+	if err != nil {
+		t.Fatalf("Expected embedded holiday tables to parse, got %v", err)
+	}
+	for _, currency := range []string{"CHF", "EUR", "GBP", "JPY", "SEK", "USD"} {
+		if _, ok := calendars[currency]; !ok {
+			t.Errorf("Expected a holiday table for %s", currency)
+		}
+	}
+}
+
+func TestParseHolidayFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unknown field", `{"currency":"USD","weekend":["sunday"],"holidays":[{"name":"X","month":1,"day":1,"when":"now"}]}`},
+		{"bad currency", `{"currency":"US","weekend":["sunday"]}`},
+		{"no weekend", `{"currency":"USD","weekend":[]}`},
+		{"unknown weekend day", `{"currency":"USD","weekend":["caturday"]}`},
+		{"no rule form", `{"currency":"USD","weekend":["sunday"],"holidays":[{"name":"X"}]}`},
+		{"two rule forms", `{"currency":"USD","weekend":["sunday"],"holidays":[{"name":"X","month":1,"day":1,"easter_offset":1}]}`},
+		{"bad day", `{"currency":"USD","weekend":["sunday"],"holidays":[{"name":"X","month":4,"day":31}]}`},
+		{"bad nth", `{"currency":"USD","weekend":["sunday"],"holidays":[{"name":"X","month":1,"weekday":"monday","nth":6}]}`},
+		{"bad date", `{"currency":"USD","weekend":["sunday"],"holidays":[{"name":"X","date":"2025-13-01"}]}`},
+		{"bad policy", `{"currency":"USD","weekend":["sunday"],"holidays":[{"name":"X","month":1,"day":1,"observed":"always"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseHolidayFile([]byte(tt.data)); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestEasterSunday(t *testing.T) {
+	tests := map[int]string{
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2026: "2026-04-05",
+		2027: "2027-03-28",
+	}
+	for year, expected := range tests {
+		if got := easterSunday(year).Format(dateLayout); got != expected {
+			t.Errorf("Expected Easter %d on %s, got %s", year, expected, got)
+		}
+	}
+}
+
+func TestCalendar_Holidays(t *testing.T) {
+	tests := []struct {
+		currency string
+		date     string
+		holiday  bool
+	}{
+		{"USD", "2025-01-20", true},  // Martin Luther King Jr. Day
+		{"USD", "2025-05-26", true},  // Memorial Day, last Monday
+		{"USD", "2025-11-27", true},  // Thanksgiving
+		{"USD", "2022-12-26", true},  // Christmas on Sunday observed Monday
+		{"USD", "2026-07-03", false}, // Saturday holidays are not moved
+		{"EUR", "2025-04-18", true},  // Good Friday
+		{"EUR", "2025-04-21", true},  // Easter Monday
+		{"EUR", "2025-11-27", false},
+		{"GBP", "2021-12-27", true}, // Christmas on Saturday
+		{"GBP", "2021-12-28", true}, // Boxing Day on Sunday
+		{"GBP", "2022-12-27", true}, // Christmas on Sunday, Boxing Day already Monday
+		{"GBP", "2025-08-25", true}, // Summer Bank Holiday
+		{"CHF", "2025-05-29", true}, // Ascension
+		{"CHF", "2025-06-09", true}, // Whit Monday
+		{"SEK", "2025-06-20", true}, // Midsummer Eve
+		{"SEK", "2026-06-19", true},
+		{"JPY", "2025-05-06", true}, // Greenery Day on Sunday moves past Children's Day
+		{"JPY", "2026-09-22", true},
+		{"JPY", "2025-07-21", true}, // Marine Day
+		{"JPY", "2025-07-22", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency+" "+tt.date, func(t *testing.T) {
+			if _, holiday := ForCurrency(tt.currency).Holiday(date(tt.date)); holiday != tt.holiday {
+				t.Errorf("Expected holiday %v, got %v", tt.holiday, holiday)
+			}
+		})
+	}
+}
+
+func TestCalendar_IsBusinessDay(t *testing.T) {
+	usd := ForCurrency("usd")
+	if usd.IsBusinessDay(date("2025-03-08")) {
+		t.Error("Expected Saturday not to be a business day")
+	}
+	if usd.IsBusinessDay(date("2025-07-04")) {
+		t.Error("Expected Independence Day not to be a business day")
+	}
+	if !usd.IsBusinessDay(date("2025-07-07")) {
+		t.Error("Expected an ordinary Monday to be a business day")
+	}
+
+	unknown := ForCurrency("NZD")
+	if unknown.Name() != "NZD" || !unknown.IsBusinessDay(date("2025-12-25")) || unknown.IsBusinessDay(date("2025-12-27")) {
+		t.Error("Expected currencies without a table to close on weekends only")
+	}
+}
+
+func TestCalendar_AddBusinessDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar *Calendar
+		start    string
+		days     int
+		expected string
+	}{
+		{"weekdays over a weekend", WeekendsOnly(), "2025-12-19", 1, "2025-12-22"},
+		{"USD over Christmas", ForCurrency("USD"), "2025-12-24", 1, "2025-12-26"},
+		{"joint USD/EUR over Christmas", ForPair("USD", "EUR"), "2025-12-24", 1, "2025-12-29"},
+		{"joint USD/GBP over Easter", ForPair("USD", "GBP"), "2025-04-17", 2, "2025-04-23"},
+		{"zero days", ForCurrency("USD"), "2025-12-25", 0, "2025-12-25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.calendar.AddBusinessDays(date(tt.start), tt.days).Format(dateLayout); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_ModifiedFollowing(t *testing.T) {
+	tests := []struct {
+		date     string
+		expected string
+	}{
+		{"2025-07-07", "2025-07-07"}, // already a business day
+		{"2025-07-04", "2025-07-07"}, // Independence Day rolls to Monday
+		{"2025-05-31", "2025-05-30"}, // Saturday at month end rolls back
+		{"2025-08-31", "2025-08-29"}, // Sunday before Labor Day rolls back into August
+	}
+	usd := ForCurrency("USD")
+	for _, tt := range tests {
+		if got := usd.ModifiedFollowing(date(tt.date)).Format(dateLayout); got != tt.expected {
+			t.Errorf("Expected %s to roll to %s, got %s", tt.date, tt.expected, got)
+		}
+	}
+}
+
+func TestForPair_Name(t *testing.T) {
+	if name := ForPair("usd", "EUR").Name(); name != "USD+EUR" {
+		t.Errorf("Expected USD+EUR, got %s", name)
+	}
+}
@@ -0,0 +1,273 @@
+package calendar
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed holidays/*.json
+var holidayFiles embed.FS
+
+// currencyCalendars holds the calendars loaded from the embedded holiday tables
+var currencyCalendars = mustLoadCalendars()
+
+// Observance policies for holidays that fall on a weekend
+const (
+	// observeNone leaves weekend holidays where they fall
+	observeNone = ""
+	// observeSubstitute moves Saturday and Sunday holidays to the next free weekday
+	observeSubstitute = "substitute"
+	// observeSundaySubstitute moves only Sunday holidays to the next free weekday
+	observeSundaySubstitute = "sunday_substitute"
+)
+
+// holidayFile is the layout of an embedded holiday table
+type holidayFile struct {
+	Currency string        `json:"currency"`
+	Weekend  []string      `json:"weekend"`
+	Holidays []holidayRule `json:"holidays"`
+}
+
+// holidayRule describes how to find a holiday in a given year; exactly one form is set:
+// a fixed month and day, the nth weekday of a month (-1 for the last), the first weekday
+// on or after a day of a month, an offset from Easter Sunday, or a one-off date
+type holidayRule struct {
+	Name         string `json:"name"`
+	Month        int    `json:"month,omitempty"`
+	Day          int    `json:"day,omitempty"`
+	Weekday      string `json:"weekday,omitempty"`
+	Nth          int    `json:"nth,omitempty"`
+	OnOrAfter    int    `json:"on_or_after,omitempty"`
+	EasterOffset *int   `json:"easter_offset,omitempty"`
+	Date         string `json:"date,omitempty"`
+	Observed     string `json:"observed,omitempty"`
+}
+
+// weekdays maps lowercase weekday names to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// mustLoadCalendars parses every embedded holiday table; the tables ship with the binary, so a bad one is a build defect
+func mustLoadCalendars() map[string]*Calendar {
+	calendars, err := loadCalendars()
+	if err != nil {
+		panic(err)
+	}
+	return calendars
+}
+
+// loadCalendars parses every embedded holiday table
+func loadCalendars() (map[string]*Calendar, error) {
+	entries, err := holidayFiles.ReadDir("holidays")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holiday tables: %w", err)
+	}
+
+	calendars := make(map[string]*Calendar, len(entries))
+	for _, entry := range entries {
+		name := path.Join("holidays", entry.Name())
+		data, err := holidayFiles.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		calendar, err := parseHolidayFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday table %s: %w", name, err)
+		}
+		if _, exists := calendars[calendar.name]; exists {
+			return nil, fmt.Errorf("duplicate holiday table for %s in %s", calendar.name, name)
+		}
+		calendars[calendar.name] = calendar
+	}
+	return calendars, nil
+}
+
+// parseHolidayFile decodes and validates one holiday table
+func parseHolidayFile(data []byte) (*Calendar, error) {
+	var file holidayFile
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, err
+	}
+
+	currency := strings.ToUpper(file.Currency)
+	if len(currency) != 3 {
+		return nil, fmt.Errorf("currency must be a 3-letter code, got %q", file.Currency)
+	}
+	if len(file.Weekend) == 0 {
+		return nil, fmt.Errorf("%s: weekend must list at least one day", currency)
+	}
+	weekend := make([]time.Weekday, 0, len(file.Weekend))
+	for _, day := range file.Weekend {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown weekend day %q", currency, day)
+		}
+		weekend = append(weekend, weekday)
+	}
+	for i, rule := range file.Holidays {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("%s holiday %d (%s): %w", currency, i+1, rule.Name, err)
+		}
+	}
+	return newCalendar(currency, weekend, file.Holidays), nil
+}
+
+// validate checks that exactly one rule form is set and its fields are in range
+func (r holidayRule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch r.Observed {
+	case observeNone, observeSubstitute, observeSundaySubstitute:
+	default:
+		return fmt.Errorf("unknown observed policy %q", r.Observed)
+	}
+
+	forms := 0
+	if r.Date != "" {
+		forms++
+		if _, err := time.Parse(dateLayout, r.Date); err != nil {
+			return fmt.Errorf("date must be YYYY-MM-DD: %w", err)
+		}
+	}
+	if r.EasterOffset != nil {
+		forms++
+	}
+	if r.Month != 0 {
+		forms++
+		if r.Month < 1 || r.Month > 12 {
+			return fmt.Errorf("month must be between 1 and 12, got %d", r.Month)
+		}
+		if r.Weekday == "" {
+			if r.Day < 1 || r.Day > daysIn(time.Month(r.Month), 2024) {
+				return fmt.Errorf("day %d does not exist in month %d", r.Day, r.Month)
+			}
+		} else {
+			if _, ok := weekdays[r.Weekday]; !ok {
+				return fmt.Errorf("unknown weekday %q", r.Weekday)
+			}
+			switch {
+			case r.Nth != 0 && r.OnOrAfter != 0, r.Nth == 0 && r.OnOrAfter == 0:
+				return fmt.Errorf("a weekday rule needs exactly one of nth or on_or_after")
+			case r.Nth < -1 || r.Nth > 5:
+				return fmt.Errorf("nth must be between 1 and 5, or -1 for the last, got %d", r.Nth)
+			case r.OnOrAfter < 0 || r.OnOrAfter > daysIn(time.Month(r.Month), 2024):
+				return fmt.Errorf("on_or_after day %d does not exist in month %d", r.OnOrAfter, r.Month)
+			}
+		}
+	}
+	if forms != 1 {
+		return fmt.Errorf("exactly one of month, easter_offset or date must be set")
+	}
+	return nil
+}
+
+// dateIn returns the date of the holiday in year, if it occurs that year
+func (r holidayRule) dateIn(year int) (time.Time, bool) {
+	switch {
+	case r.Date != "":
+		date, _ := time.Parse(dateLayout, r.Date)
+		return date, date.Year() == year
+	case r.EasterOffset != nil:
+		return easterSunday(year).AddDate(0, 0, *r.EasterOffset), true
+	case r.Weekday == "":
+		if r.Day > daysIn(time.Month(r.Month), year) {
+			return time.Time{}, false
+		}
+		return time.Date(year, time.Month(r.Month), r.Day, 0, 0, 0, 0, time.UTC), true
+	case r.OnOrAfter != 0:
+		return nextWeekday(time.Date(year, time.Month(r.Month), r.OnOrAfter, 0, 0, 0, 0, time.UTC), weekdays[r.Weekday]), true
+	case r.Nth > 0:
+		first := nextWeekday(time.Date(year, time.Month(r.Month), 1, 0, 0, 0, 0, time.UTC), weekdays[r.Weekday])
+		date := first.AddDate(0, 0, 7*(r.Nth-1))
+		return date, date.Month() == time.Month(r.Month)
+	default:
+		last := time.Date(year, time.Month(r.Month)+1, 0, 0, 0, 0, 0, time.UTC)
+		offset := (int(last.Weekday()) - int(weekdays[r.Weekday]) + 7) % 7
+		return last.AddDate(0, 0, -offset), true
+	}
+}
+
+// observeHolidays returns the holidays of a year keyed by date, including weekend substitutes
+func observeHolidays(year int, rules []holidayRule, weekend map[time.Weekday]bool) map[string]string {
+	type occurrence struct {
+		date time.Time
+		rule holidayRule
+	}
+	var occurrences []occurrence
+	holidays := make(map[string]string)
+	for _, rule := range rules {
+		date, ok := rule.dateIn(year)
+		if !ok {
+			continue
+		}
+		occurrences = append(occurrences, occurrence{date, rule})
+		holidays[date.Format(dateLayout)] = rule.Name
+	}
+
+	// Substitutes are assigned in date order so consecutive weekend holidays take consecutive weekdays
+	sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].date.Before(occurrences[j].date) })
+	for _, occurrence := range occurrences {
+		if !needsSubstitute(occurrence.date, occurrence.rule.Observed) {
+			continue
+		}
+		substitute := occurrence.date.AddDate(0, 0, 1)
+		for weekend[substitute.Weekday()] || holidays[substitute.Format(dateLayout)] != "" {
+			substitute = substitute.AddDate(0, 0, 1)
+		}
+		holidays[substitute.Format(dateLayout)] = occurrence.rule.Name + " (observed)"
+	}
+	return holidays
+}
+
+// needsSubstitute reports whether a holiday on date moves to another day under the policy
+func needsSubstitute(date time.Time, policy string) bool {
+	switch policy {
+	case observeSubstitute:
+		return date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+	case observeSundaySubstitute:
+		return date.Weekday() == time.Sunday
+	default:
+		return false
+	}
+}
+
+// nextWeekday returns the first date on or after date that falls on weekday
+func nextWeekday(date time.Time, weekday time.Weekday) time.Time {
+	return date.AddDate(0, 0, (int(weekday)-int(date.Weekday())+7)%7)
+}
+
+// daysIn returns the number of days in a month
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// easterSunday computes Western Easter using the anonymous Gregorian algorithm
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -3,13 +3,19 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"time"
 
 	currencymodels "github.com/dalfonso89/currency-exchange-service/models"
 	"github.com/dalfonso89/financial-forecasting-service/config"
 	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
 )
 
 // CurrencyClient handles communication with the currency exchange service
@@ -19,6 +25,17 @@ type CurrencyClient struct {
 	logger     logger.Logger
 }
 
+// StatusError is returned when the currency exchange service responds with a non-200 status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("currency service returned status %d: %s", e.StatusCode, e.Body)
+}
+
 // NewCurrencyClient creates a new currency client
 func NewCurrencyClient(cfg *config.Config, logger logger.Logger) *CurrencyClient {
 	return &CurrencyClient{
@@ -33,73 +50,66 @@ func NewCurrencyClient(cfg *config.Config, logger logger.Logger) *CurrencyClient
 // GetRates fetches exchange rates from the currency exchange service
 func (c *CurrencyClient) GetRates(ctx context.Context, baseCurrency string) (*currencymodels.RatesResponse, error) {
 	url := fmt.Sprintf("%s/api/v1/rates/%s", c.baseURL, baseCurrency)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	c.logger.Debugf("Fetching rates from: %s", url)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch rates: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("currency service returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	var ratesResponse currencymodels.RatesResponse
-	if err := json.Unmarshal(body, &ratesResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	c.logger.Debugf("Successfully fetched rates for base currency: %s", baseCurrency)
-	return &ratesResponse, nil
+	return c.fetchRates(ctx, "get_rates", url, baseCurrency)
 }
 
 // GetRatesWithQuery fetches exchange rates using query parameters
 func (c *CurrencyClient) GetRatesWithQuery(ctx context.Context, baseCurrency string) (*currencymodels.RatesResponse, error) {
 	url := fmt.Sprintf("%s/api/v1/rates?base=%s", c.baseURL, baseCurrency)
+	return c.fetchRates(ctx, "get_rates_query", url, baseCurrency)
+}
+
+// fetchRates performs an instrumented rates request against the given URL
+func (c *CurrencyClient) fetchRates(ctx context.Context, operation, url, baseCurrency string) (ratesResponse *currencymodels.RatesResponse, err error) {
+	start := time.Now()
+	ctx, span := tracing.StartWithKind(ctx, "CurrencyClient."+operation, tracing.SpanKindClient)
+	span.SetAttribute("http.method", "GET")
+	span.SetAttribute("http.url", url)
+	span.SetAttribute("currency.base", baseCurrency)
+	defer func() {
+		metrics.UpstreamRequestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+		span.RecordError(err)
+		span.End()
+	}()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
+		metrics.UpstreamErrors.WithLabelValues(operation, "request").Inc()
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	tracing.Inject(ctx, req.Header)
 
-	c.logger.Debugf("Fetching rates from: %s", url)
+	requestLogger := c.logger.WithContext(ctx)
+	requestLogger.Debugf("Fetching rates from: %s", url)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		metrics.UpstreamErrors.WithLabelValues(operation, transportErrorReason(err)).Inc()
 		return nil, fmt.Errorf("failed to fetch rates: %w", err)
 	}
 	defer resp.Body.Close()
+	span.SetAttribute("http.status_code", resp.StatusCode)
 
 	if resp.StatusCode != http.StatusOK {
+		metrics.UpstreamErrors.WithLabelValues(operation, "status_"+strconv.Itoa(resp.StatusCode)).Inc()
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("currency service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		metrics.UpstreamErrors.WithLabelValues(operation, "read").Inc()
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var ratesResponse currencymodels.RatesResponse
-	if err := json.Unmarshal(body, &ratesResponse); err != nil {
+	ratesResponse = &currencymodels.RatesResponse{}
+	if err := json.Unmarshal(body, ratesResponse); err != nil {
+		metrics.UpstreamErrors.WithLabelValues(operation, "decode").Inc()
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	c.logger.Debugf("Successfully fetched rates for base currency: %s", baseCurrency)
-	return &ratesResponse, nil
+	requestLogger.Debugf("Successfully fetched rates for base currency: %s", baseCurrency)
+	return ratesResponse, nil
 }
 
 // HealthCheck checks if the currency exchange service is healthy
@@ -110,16 +120,36 @@ func (c *CurrencyClient) HealthCheck(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create health check request: %w", err)
 	}
+	tracing.Inject(ctx, req.Header)
+
+	start := time.Now()
+	defer func() {
+		metrics.UpstreamRequestDuration.WithLabelValues("health_check").Observe(time.Since(start).Seconds())
+	}()
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		metrics.UpstreamErrors.WithLabelValues("health_check", transportErrorReason(err)).Inc()
 		return fmt.Errorf("health check failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		metrics.UpstreamErrors.WithLabelValues("health_check", "status_"+strconv.Itoa(resp.StatusCode)).Inc()
 		return fmt.Errorf("currency service health check failed with status: %d", resp.StatusCode)
 	}
 
 	return nil
 }
+
+// transportErrorReason classifies a transport failure for the upstream error counter
+func transportErrorReason(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "timeout"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	return "transport"
+}
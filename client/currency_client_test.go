@@ -10,6 +10,7 @@ import (
 	currencymodels "github.com/dalfonso89/currency-exchange-service/models"
 	"github.com/dalfonso89/financial-forecasting-service/config"
 	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
 )
 
 func TestNewCurrencyClient(t *testing.T) {
@@ -276,3 +277,31 @@ func TestCurrencyClient_ContextCancellation(t *testing.T) {
 		t.Error("Expected rates to be nil on context cancellation")
 	}
 }
+
+func TestCurrencyClient_GetRates_RecordsMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		CurrencyExchangeServiceURL: server.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+	}
+	client := NewCurrencyClient(cfg, logger.New("debug"))
+
+	errorCounter := metrics.UpstreamErrors.WithLabelValues("get_rates", "status_503")
+	latency := metrics.UpstreamRequestDuration.WithLabelValues("get_rates")
+	errorsBefore, callsBefore := errorCounter.Value(), latency.Count()
+
+	if _, err := client.GetRates(context.Background(), "USD"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if errorCounter.Value() != errorsBefore+1 {
+		t.Errorf("Expected upstream error to be counted, got %v", errorCounter.Value()-errorsBefore)
+	}
+	if latency.Count() != callsBefore+1 {
+		t.Errorf("Expected upstream latency to be observed, got %d", latency.Count()-callsBefore)
+	}
+}
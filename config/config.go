@@ -1,6 +1,8 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -22,54 +24,273 @@ type Config struct {
 	ForecastCacheTTL       time.Duration
 	MaxConcurrentRequests  int
 	DefaultForecastPeriods int
+	DefaultForecastType    string
 	SupportedCurrencies    []string
+	ModelPairs             map[string]ModelConfig // Per-pair model defaults keyed by "BASE/TARGET"
+
+	// CORS configuration
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+
+	// Money configuration
+	RoundingMode     string // "half_up" or "half_even", applied when rounding amounts to the currency's minor unit
+	AmountsAsStrings bool   // Encode amounts in JSON as strings instead of numbers
+
+	// Forecast history configuration; history is kept only in memory when the path is empty
+	ForecastHistoryPath        string
+	ForecastHistoryMemoryLimit int // Most recent forecasts kept in memory; older ones are evicted, or read back from the file
+
+	// Rate history configuration; daily rate snapshots are kept in memory when the path is empty
+	RateHistoryPath      string
+	RateSnapshotInterval time.Duration
+
+	// Interest rate curves file used to price forward rates; hedging analysis is unavailable when empty
+	InterestRateCurvesPath string
+
+	// Forecast accuracy tracking configuration
+	AccuracyCheckInterval time.Duration
+	AccuracyWindow        time.Duration
+
+	// Readiness probe configuration
+	ReadinessCheckTimeout time.Duration
+
+	// Tracing configuration
+	TracingOTLPEndpoint string
+	TracingServiceName  string
+
+	// Authentication configuration; the keys are loaded and validated but no route checks them yet
+	APIKeys []string
+
+	// ConfigFile is the YAML or TOML file the settings were read from, if any
+	ConfigFile string
 }
 
-// Load loads configuration from environment variables
+// ModelConfig overrides the forecast defaults for one currency pair
+type ModelConfig struct {
+	ForecastType string `json:"type,omitempty"`
+	Periods      int    `json:"periods,omitempty"`
+}
+
+// Options selects the configuration sources for LoadWithOptions
+type Options struct {
+	// ConfigFile is a YAML or TOML file; when empty the CONFIG_FILE variable is used
+	ConfigFile string
+	// DotEnvFile defaults to ".env"; a missing file is ignored
+	DotEnvFile string
+	// Overrides holds variables set on the command line; they take precedence over every other source
+	Overrides map[string]string
+}
+
+// defaultSupportedCurrencies is used when SUPPORTED_CURRENCIES lists no currencies
+const defaultSupportedCurrencies = "USD,EUR,GBP,JPY,CAD,AUD,CHF,CNY,SEK,NZD"
+
+// Load loads configuration from the environment, .env and CONFIG_FILE and validates it
 func Load() (*Config, error) {
-	// Load .env file if it exists
-	_ = godotenv.Load()
+	return LoadWithOptions(Options{})
+}
+
+// LoadWithOptions loads configuration with precedence defaults < config file < .env < environment < overrides,
+// reporting every invalid variable at once
+func LoadWithOptions(opts Options) (*Config, error) {
+	if opts.DotEnvFile == "" {
+		opts.DotEnvFile = ".env"
+	}
+	dotEnv, err := godotenv.Read(opts.DotEnvFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", opts.DotEnvFile, err)
+	}
+
+	configFile := opts.ConfigFile
+	if configFile == "" {
+		configFile = firstNonEmpty(opts.Overrides["CONFIG_FILE"], os.Getenv("CONFIG_FILE"), dotEnv["CONFIG_FILE"])
+	}
+	var fileValues map[string]string
+	if configFile != "" {
+		if fileValues, err = readConfigFile(configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg, err := load(
+		source{name: "command line", lookup: mapLookup(opts.Overrides)},
+		source{name: "environment", lookup: os.LookupEnv},
+		source{name: opts.DotEnvFile, lookup: mapLookup(dotEnv)},
+		source{name: configFile, lookup: mapLookup(fileValues)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ConfigFile = configFile
+	return cfg, nil
+}
+
+// source is one configuration layer
+type source struct {
+	name   string
+	lookup func(string) (string, bool)
+}
+
+// load builds and validates a Config from sources listed in order of precedence
+func load(sources ...source) (*Config, error) {
+	env := &envReader{sources: sources, origins: make(map[string]string)}
+
+	cfg := &Config{
+		Port:     env.string("PORT", "8082"),
+		LogLevel: env.string("LOG_LEVEL", "info"),
+
+		CurrencyExchangeServiceURL: env.string("CURRENCY_EXCHANGE_SERVICE_URL", "http://localhost:8081"),
+		CurrencyExchangeTimeout:    env.seconds("CURRENCY_EXCHANGE_TIMEOUT_SECONDS", 30),
+
+		ForecastCacheTTL:       env.seconds("FORECAST_CACHE_TTL_SECONDS", 300), // 5 minutes
+		MaxConcurrentRequests:  env.int("MAX_CONCURRENT_REQUESTS", 10),
+		DefaultForecastPeriods: env.int("DEFAULT_FORECAST_PERIODS", 30),
+		DefaultForecastType:    env.string("DEFAULT_FORECAST_TYPE", "linear"),
+		SupportedCurrencies:    parseCurrencyList(env.string("SUPPORTED_CURRENCIES", defaultSupportedCurrencies)),
+		ModelPairs:             env.modelPairs("FORECAST_MODEL_PAIRS"),
+
+		CORSAllowedOrigins:   env.list("CORS_ALLOWED_ORIGINS", "*"),
+		CORSAllowedMethods:   env.list("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,HEAD,OPTIONS"),
+		CORSAllowedHeaders:   env.list("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-Request-ID"),
+		CORSAllowCredentials: env.bool("CORS_ALLOW_CREDENTIALS", false),
+		CORSMaxAge:           env.seconds("CORS_MAX_AGE_SECONDS", 600),
+
+		RoundingMode:     env.string("ROUNDING_MODE", "half_up"),
+		AmountsAsStrings: env.bool("AMOUNTS_AS_STRINGS", false),
+
+		ForecastHistoryPath:        env.string("FORECAST_HISTORY_PATH", ""),
+		ForecastHistoryMemoryLimit: env.int("FORECAST_HISTORY_MEMORY_LIMIT", 10000),
+
+		RateHistoryPath:      env.string("RATE_HISTORY_PATH", ""),
+		RateSnapshotInterval: env.seconds("RATE_SNAPSHOT_INTERVAL_SECONDS", 3600),
 
-	return &Config{
-		Port:     getEnv("PORT", "8082"),
-		LogLevel: getEnv("LOG_LEVEL", "info"),
+		InterestRateCurvesPath: env.string("INTEREST_RATE_CURVES_PATH", ""),
 
-		CurrencyExchangeServiceURL: getEnv("CURRENCY_EXCHANGE_SERVICE_URL", "http://localhost:8081"),
-		CurrencyExchangeTimeout:    time.Duration(mustAtoi(getEnv("CURRENCY_EXCHANGE_TIMEOUT_SECONDS", "30"))) * time.Second,
+		AccuracyCheckInterval: env.seconds("ACCURACY_CHECK_INTERVAL_SECONDS", 3600),
+		AccuracyWindow:        time.Duration(env.int("ACCURACY_WINDOW_DAYS", 30)) * 24 * time.Hour,
 
-		ForecastCacheTTL:       time.Duration(mustAtoi(getEnv("FORECAST_CACHE_TTL_SECONDS", "300"))) * time.Second, // 5 minutes
-		MaxConcurrentRequests:  mustAtoi(getEnv("MAX_CONCURRENT_REQUESTS", "10")),
-		DefaultForecastPeriods: mustAtoi(getEnv("DEFAULT_FORECAST_PERIODS", "30")),
-		SupportedCurrencies:    getSupportedCurrencies(),
-	}, nil
+		ReadinessCheckTimeout: env.seconds("READINESS_CHECK_TIMEOUT_SECONDS", 2),
+
+		TracingOTLPEndpoint: env.string("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		TracingServiceName:  env.string("OTEL_SERVICE_NAME", "financial-forecasting-service"),
+
+		APIKeys: env.list("API_KEYS", ""),
+	}
+
+	errs := append(env.errors, cfg.validate()...)
+	if len(errs) > 0 {
+		for i := range errs {
+			if errs[i].Source == "" {
+				errs[i].Source = env.origins[errs[i].Variable]
+			}
+		}
+		return nil, &ValidationError{Errors: errs}
+	}
+	return cfg, nil
+}
+
+// envReader reads typed variables from layered sources, collecting parse errors instead of stopping at the first
+type envReader struct {
+	sources []source
+	origins map[string]string // Variable name to the source that set it
+	errors  []FieldError
 }
 
-// getEnv gets an environment variable with a fallback value
-func getEnv(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// string gets a variable from the highest-precedence source that sets it; empty values use the fallback
+func (r *envReader) string(key, fallback string) string {
+	for _, src := range r.sources {
+		if value, ok := src.lookup(key); ok && value != "" {
+			r.origins[key] = src.name
+			return value
+		}
 	}
 	return fallback
 }
 
-func mustAtoi(s string) int {
-	i, err := strconv.Atoi(s)
+// int parses an integer variable
+func (r *envReader) int(key string, fallback int) int {
+	raw := r.string(key, strconv.Itoa(fallback))
+	value, err := strconv.Atoi(strings.TrimSpace(raw))
 	if err != nil {
-		return 60
+		r.errors = append(r.errors, FieldError{Variable: key, Value: raw, Message: "must be an integer"})
+		return fallback
 	}
-	return i
+	return value
 }
 
-// getSupportedCurrencies parses supported currencies from environment variable
-func getSupportedCurrencies() []string {
-	currenciesEnv := getEnv("SUPPORTED_CURRENCIES", "USD,EUR,GBP,JPY,CAD,AUD,CHF,CNY,SEK,NZD")
-	
+// seconds parses a whole number of seconds into a duration
+func (r *envReader) seconds(key string, fallback int) time.Duration {
+	return time.Duration(r.int(key, fallback)) * time.Second
+}
+
+// bool parses a boolean variable
+func (r *envReader) bool(key string, fallback bool) bool {
+	raw := r.string(key, strconv.FormatBool(fallback))
+	value, err := strconv.ParseBool(strings.TrimSpace(raw))
+	if err != nil {
+		r.errors = append(r.errors, FieldError{Variable: key, Value: raw, Message: "must be a boolean (true or false)"})
+		return fallback
+	}
+	return value
+}
+
+// list parses a comma-separated variable into a trimmed list
+func (r *envReader) list(key, fallback string) []string {
+	var result []string
+	for _, item := range strings.Split(r.string(key, fallback), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// modelPairs parses per-pair model defaults encoded as a JSON object, upper-casing pair keys
+func (r *envReader) modelPairs(key string) map[string]ModelConfig {
+	raw := r.string(key, "")
+	if raw == "" {
+		return nil
+	}
+	var pairs map[string]ModelConfig
+	if err := json.Unmarshal([]byte(raw), &pairs); err != nil {
+		r.errors = append(r.errors, FieldError{Variable: key, Value: raw, Message: `must be a JSON object such as {"USD/EUR":{"type":"linear","periods":30}}`})
+		return nil
+	}
+	normalized := make(map[string]ModelConfig, len(pairs))
+	for pair, model := range pairs {
+		normalized[strings.ToUpper(strings.TrimSpace(pair))] = model
+	}
+	return normalized
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// mapLookup adapts a map to a source lookup function
+func mapLookup(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+// parseCurrencyList parses a comma-separated currency list, upper-casing codes and falling back to the default set
+func parseCurrencyList(value string) []string {
 	// Split by comma and clean up whitespace
-	currencies := strings.Split(currenciesEnv, ",")
+	currencies := strings.Split(value, ",")
 	for i, currency := range currencies {
 		currencies[i] = strings.TrimSpace(strings.ToUpper(currency))
 	}
-	
+
 	// Filter out empty strings
 	var result []string
 	for _, currency := range currencies {
@@ -77,12 +298,11 @@ func getSupportedCurrencies() []string {
 			result = append(result, currency)
 		}
 	}
-	
+
 	// If no valid currencies found, return default set
 	if len(result) == 0 {
-		return []string{"USD", "EUR", "GBP", "JPY", "CAD", "AUD", "CHF", "CNY", "SEK", "NZD"}
+		return strings.Split(defaultSupportedCurrencies, ",")
 	}
-	
+
 	return result
 }
-
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// fileKeys maps dotted config file keys to the variables they set
+var fileKeys = map[string]string{
+	"server.port":      "PORT",
+	"server.log_level": "LOG_LEVEL",
+
+	"providers.currency_exchange.url":             "CURRENCY_EXCHANGE_SERVICE_URL",
+	"providers.currency_exchange.timeout_seconds": "CURRENCY_EXCHANGE_TIMEOUT_SECONDS",
+
+	"cache.ttl_seconds": "FORECAST_CACHE_TTL_SECONDS",
+
+	"forecast.max_concurrent_requests": "MAX_CONCURRENT_REQUESTS",
+	"forecast.supported_currencies":    "SUPPORTED_CURRENCIES",
+
+	"models.default_type":    "DEFAULT_FORECAST_TYPE",
+	"models.default_periods": "DEFAULT_FORECAST_PERIODS",
+	"models.pairs":           "FORECAST_MODEL_PAIRS",
+
+	"cors.allowed_origins":   "CORS_ALLOWED_ORIGINS",
+	"cors.allowed_methods":   "CORS_ALLOWED_METHODS",
+	"cors.allowed_headers":   "CORS_ALLOWED_HEADERS",
+	"cors.allow_credentials": "CORS_ALLOW_CREDENTIALS",
+	"cors.max_age_seconds":   "CORS_MAX_AGE_SECONDS",
+
+	"money.rounding_mode":      "ROUNDING_MODE",
+	"money.amounts_as_strings": "AMOUNTS_AS_STRINGS",
+
+	"history.path":                           "FORECAST_HISTORY_PATH",
+	"history.memory_limit":                   "FORECAST_HISTORY_MEMORY_LIMIT",
+	"history.rates_path":                     "RATE_HISTORY_PATH",
+	"history.rate_snapshot_interval_seconds": "RATE_SNAPSHOT_INTERVAL_SECONDS",
+
+	"hedging.interest_rate_curves_path": "INTEREST_RATE_CURVES_PATH",
+
+	"accuracy.check_interval_seconds": "ACCURACY_CHECK_INTERVAL_SECONDS",
+	"accuracy.window_days":            "ACCURACY_WINDOW_DAYS",
+
+	"readiness.check_timeout_seconds": "READINESS_CHECK_TIMEOUT_SECONDS",
+
+	"tracing.otlp_endpoint": "OTEL_EXPORTER_OTLP_ENDPOINT",
+	"tracing.service_name":  "OTEL_SERVICE_NAME",
+
+	"auth.api_keys": "API_KEYS",
+}
+
+// readConfigFile parses a YAML or TOML config file into the variables it sets
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	document := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &document)
+	case ".toml":
+		err = toml.Unmarshal(data, &document)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q: use .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	var errs []FieldError
+	flattenConfig("", document, values, &errs)
+	if len(errs) > 0 {
+		for i := range errs {
+			errs[i].Source = path
+		}
+		return nil, &ValidationError{Errors: errs}
+	}
+	return values, nil
+}
+
+// flattenConfig walks a decoded document, converting known keys to variable values and reporting unknown keys
+func flattenConfig(prefix string, section map[string]interface{}, values map[string]string, errs *[]FieldError) {
+	keys := make([]string, 0, len(section))
+	for key := range section {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		value := section[key]
+
+		if variable, ok := fileKeys[path]; ok {
+			encoded, err := fileValue(value)
+			if err != nil {
+				*errs = append(*errs, FieldError{Variable: path, Value: fmt.Sprint(value), Message: err.Error()})
+				continue
+			}
+			values[variable] = encoded
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenConfig(path, nested, values, errs)
+			continue
+		}
+		*errs = append(*errs, FieldError{Variable: path, Value: fmt.Sprint(value), Message: "unknown configuration key"})
+	}
+}
+
+// fileValue encodes a decoded file value in the variable syntax: lists are comma-separated, objects are JSON
+func fileValue(value interface{}) (string, error) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return "", fmt.Errorf("must be an object: %v", err)
+		}
+		return string(encoded), nil
+	case []interface{}:
+		items := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if _, nested := item.(map[string]interface{}); nested {
+				return "", fmt.Errorf("must be a list of values")
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ","), nil
+	case nil:
+		return "", nil
+	default:
+		return fmt.Sprint(typed), nil
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadWithOptions_ConfigFile(t *testing.T) {
+	for _, file := range []string{"testdata/config.yaml", "testdata/config.toml"} {
+		t.Run(filepath.Ext(file), func(t *testing.T) {
+			os.Clearenv()
+
+			config, err := LoadWithOptions(Options{ConfigFile: file, DotEnvFile: "testdata/missing.env"})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if config.Port != "9000" {
+				t.Errorf("Expected port 9000, got %s", config.Port)
+			}
+			if config.LogLevel != "warn" {
+				t.Errorf("Expected log level warn, got %s", config.LogLevel)
+			}
+			if config.CurrencyExchangeServiceURL != "http://rates.internal:8081" {
+				t.Errorf("Expected provider URL from file, got %s", config.CurrencyExchangeServiceURL)
+			}
+			if config.CurrencyExchangeTimeout != 10*time.Second {
+				t.Errorf("Expected provider timeout 10s, got %v", config.CurrencyExchangeTimeout)
+			}
+			if config.ForecastCacheTTL != 120*time.Second {
+				t.Errorf("Expected cache TTL 120s, got %v", config.ForecastCacheTTL)
+			}
+			if strings.Join(config.SupportedCurrencies, ",") != "USD,EUR,JPY,KWD" {
+				t.Errorf("Expected currencies from file, got %v", config.SupportedCurrencies)
+			}
+			if config.DefaultForecastType != "exponential" || config.DefaultForecastPeriods != 14 {
+				t.Errorf("Expected model defaults exponential/14, got %s/%d", config.DefaultForecastType, config.DefaultForecastPeriods)
+			}
+			if model := config.ModelPairs["USD/JPY"]; model.ForecastType != "moving_average" || model.Periods != 60 {
+				t.Errorf("Expected USD/JPY model moving_average/60, got %+v", model)
+			}
+			if len(config.APIKeys) != 1 || config.APIKeys[0] != "yaml-key-0123456789abcdef" {
+				t.Errorf("Expected API key from file, got %v", config.APIKeys)
+			}
+		})
+	}
+}
+
+func TestLoadWithOptions_Precedence(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+	os.Setenv("MAX_CONCURRENT_REQUESTS", "50")
+	os.Setenv("PORT", "9100")
+
+	config, err := LoadWithOptions(Options{
+		ConfigFile: "testdata/config.yaml",
+		DotEnvFile: "testdata/dotenv.env",
+		Overrides:  map[string]string{"PORT": "9200"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"default beats nothing", config.CORSMaxAge, 600 * time.Second},
+		{"file beats default", config.CurrencyExchangeTimeout, 10 * time.Second},
+		{".env beats file", config.ForecastCacheTTL, 90 * time.Second},
+		{".env beats file for log level", config.LogLevel, "error"},
+		{"environment beats .env", config.MaxConcurrentRequests, 50},
+		{"command line beats environment", config.Port, "9200"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, tt.got)
+		}
+	}
+}
+
+func TestLoadWithOptions_ConfigFileFromEnvironment(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+	os.Setenv("CONFIG_FILE", "testdata/config.toml")
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.Port != "9000" {
+		t.Errorf("Expected port from CONFIG_FILE, got %s", config.Port)
+	}
+}
+
+func TestLoadWithOptions_UnknownKeys(t *testing.T) {
+	os.Clearenv()
+
+	_, err := LoadWithOptions(Options{ConfigFile: "testdata/unknown.yaml", DotEnvFile: "testdata/missing.env"})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+
+	keys := make([]string, 0, len(validationErr.Errors))
+	for _, fieldErr := range validationErr.Errors {
+		keys = append(keys, fieldErr.Variable)
+		if fieldErr.Source != "testdata/unknown.yaml" {
+			t.Errorf("Expected error source to be the file, got %q", fieldErr.Source)
+		}
+	}
+	if strings.Join(keys, ",") != "cache.backend,server.prot" {
+		t.Errorf("Expected unknown keys cache.backend and server.prot, got %v", keys)
+	}
+}
+
+func TestLoadWithOptions_ErrorsNameSource(t *testing.T) {
+	os.Clearenv()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(file, []byte("cache:\n  ttl_seconds: soon\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadWithOptions(Options{ConfigFile: file, DotEnvFile: filepath.Join(dir, ".env")})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), `FORECAST_CACHE_TTL_SECONDS="soon" (from `+file+`)`) {
+		t.Errorf("Expected error to name the config file, got: %v", err)
+	}
+}
+
+func TestLoadWithOptions_UnsupportedExtension(t *testing.T) {
+	_, err := LoadWithOptions(Options{ConfigFile: "testdata/dotenv.env"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported config file extension") {
+		t.Errorf("Expected unsupported extension error, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
+)
+
+// reloadableSettings are the variables a running service picks up without a restart
+var reloadableSettings = map[string]bool{
+	"LOG_LEVEL":                  true,
+	"SUPPORTED_CURRENCIES":       true,
+	"FORECAST_CACHE_TTL_SECONDS": true,
+	"DEFAULT_FORECAST_PERIODS":   true,
+	"DEFAULT_FORECAST_TYPE":      true,
+	"FORECAST_MODEL_PAIRS":       true,
+	"ROUNDING_MODE":              true,
+	"AMOUNTS_AS_STRINGS":         true,
+	"INTEREST_RATE_CURVES_PATH":  true,
+}
+
+// ReloadResult lists the changes found when configuration is reloaded
+type ReloadResult struct {
+	Applied  []string // Reloadable variables that changed and were applied
+	Rejected []string // Variables that changed but need a restart; their old values are kept
+}
+
+// Merge returns a copy of c with the reloadable settings of next applied, reporting which changes were applied or rejected
+func (c *Config) Merge(next *Config) (*Config, ReloadResult) {
+	var result ReloadResult
+	current, updated := c.settings(false), next.settings(false)
+	for i := range current {
+		if current[i].Value == updated[i].Value {
+			continue
+		}
+		if reloadableSettings[current[i].Name] {
+			result.Applied = append(result.Applied, current[i].Name)
+		} else {
+			result.Rejected = append(result.Rejected, current[i].Name)
+		}
+	}
+	if c.ConfigFile != next.ConfigFile {
+		result.Rejected = append(result.Rejected, "CONFIG_FILE")
+	}
+
+	merged := *c
+	merged.LogLevel = next.LogLevel
+	merged.SupportedCurrencies = next.SupportedCurrencies
+	merged.ForecastCacheTTL = next.ForecastCacheTTL
+	merged.DefaultForecastPeriods = next.DefaultForecastPeriods
+	merged.DefaultForecastType = next.DefaultForecastType
+	merged.ModelPairs = next.ModelPairs
+	merged.RoundingMode = next.RoundingMode
+	merged.AmountsAsStrings = next.AmountsAsStrings
+	merged.InterestRateCurvesPath = next.InterestRateCurvesPath
+	return &merged, result
+}
+
+// Reload triggers
+const (
+	ReloadTriggerSignal     = "signal"
+	ReloadTriggerFileChange = "file_change"
+)
+
+// ReloadEvent describes one configuration reload attempt
+type ReloadEvent struct {
+	ReloadResult
+	Trigger string
+	Time    time.Time
+	Config  *Config // Active configuration after the attempt
+	Err     error   // Set when the new configuration could not be loaded; Config is then unchanged
+}
+
+// ReloaderConfig holds configuration for creating a Reloader
+type ReloaderConfig struct {
+	Initial *Config
+	// Load re-reads every configuration source, usually LoadWithOptions with the startup options
+	Load   func() (*Config, error)
+	Logger logger.Logger
+	// PollInterval is how often WatchFile checks the config file; defaults to 5 seconds
+	PollInterval time.Duration
+}
+
+// Reloader re-reads configuration on demand and atomically swaps in the reloadable settings
+type Reloader struct {
+	load         func() (*Config, error)
+	logger       logger.Logger
+	pollInterval time.Duration
+
+	current atomic.Pointer[Config]
+
+	// mu serializes reloads so subscribers see events in order
+	mu          sync.Mutex
+	subscribers []func(ReloadEvent)
+}
+
+// NewReloader creates a new configuration reloader
+func NewReloader(cfg ReloaderConfig) *Reloader {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	reloader := &Reloader{
+		load:         cfg.Load,
+		logger:       cfg.Logger,
+		pollInterval: cfg.PollInterval,
+	}
+	reloader.current.Store(cfg.Initial)
+	return reloader
+}
+
+// Current returns the active configuration
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// Subscribe registers fn to receive every reload event
+func (r *Reloader) Subscribe(fn func(ReloadEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Reload loads the configuration again and applies the reloadable changes; invalid configuration keeps the current settings
+func (r *Reloader) Reload(trigger string) ReloadEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event := ReloadEvent{Trigger: trigger, Time: time.Now(), Config: r.current.Load()}
+	eventLogger := r.logger.WithFields(logger.Fields{"event": "config_reload", "trigger": trigger})
+
+	next, err := r.load()
+	if err != nil {
+		event.Err = err
+		metrics.ConfigReloads.WithLabelValues("failure").Inc()
+		eventLogger.Errorf("Configuration reload failed, keeping current settings: %v", err)
+		r.notify(event)
+		return event
+	}
+
+	event.Config, event.ReloadResult = event.Config.Merge(next)
+	r.current.Store(event.Config)
+	metrics.ConfigReloads.WithLabelValues("success").Inc()
+	metrics.ConfigLastReloadSuccess.Set(float64(event.Time.Unix()))
+
+	for _, variable := range event.Rejected {
+		eventLogger.Warnf("Ignoring change to %s: it only takes effect after a restart", variable)
+	}
+	eventLogger.WithField("applied", event.Applied).Infof("Configuration reloaded with %d changes applied", len(event.Applied))
+	r.notify(event)
+	return event
+}
+
+// notify delivers an event to every subscriber; callers hold mu
+func (r *Reloader) notify(event ReloadEvent) {
+	for _, subscriber := range r.subscribers {
+		subscriber(event)
+	}
+}
+
+// WatchFile reloads whenever the config file changes until ctx is done; it returns at once when no file is in use
+func (r *Reloader) WatchFile(ctx context.Context) {
+	path := r.Current().ConfigFile
+	if path == "" {
+		return
+	}
+	lastInfo, _ := os.Stat(path)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil || !fileChanged(lastInfo, info) {
+				continue
+			}
+			lastInfo = info
+			r.Reload(ReloadTriggerFileChange)
+		}
+	}
+}
+
+// fileChanged reports whether a file was modified between two stats
+func fileChanged(before, after os.FileInfo) bool {
+	if before == nil {
+		return true
+	}
+	return !before.ModTime().Equal(after.ModTime()) || before.Size() != after.Size()
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+)
+
+func TestConfig_Merge(t *testing.T) {
+	base, err := load(testSource(map[string]string{}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	curvesPath := filepath.Join(t.TempDir(), "curves.yaml")
+	if err := os.WriteFile(curvesPath, []byte("USD: {rates: {1M: 5}}\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write curves file: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		values           map[string]string
+		expectedApplied  string
+		expectedRejected string
+	}{
+		{"no changes", map[string]string{}, "", ""},
+		{"reloadable settings", map[string]string{"LOG_LEVEL": "debug", "SUPPORTED_CURRENCIES": "USD,EUR", "FORECAST_CACHE_TTL_SECONDS": "60"}, "LOG_LEVEL,FORECAST_CACHE_TTL_SECONDS,SUPPORTED_CURRENCIES", ""},
+		{"port needs a restart", map[string]string{"PORT": "9000"}, "", "PORT"},
+		{"api keys need a restart", map[string]string{"API_KEYS": "key-0123456789abcdef"}, "", "API_KEYS"},
+		{"concurrency limit needs a restart", map[string]string{"MAX_CONCURRENT_REQUESTS": "20"}, "", "MAX_CONCURRENT_REQUESTS"},
+		{"mixed changes", map[string]string{"PORT": "9000", "DEFAULT_FORECAST_PERIODS": "20", "RATE_HISTORY_PATH": "/data/rates.jsonl"}, "DEFAULT_FORECAST_PERIODS", "PORT,RATE_HISTORY_PATH"},
+		{"model defaults", map[string]string{"DEFAULT_FORECAST_TYPE": "exponential", "FORECAST_MODEL_PAIRS": `{"USD/JPY":{"periods":60}}`}, "DEFAULT_FORECAST_TYPE,FORECAST_MODEL_PAIRS", ""},
+		{"amounts", map[string]string{"ROUNDING_MODE": "half_even", "AMOUNTS_AS_STRINGS": "true"}, "ROUNDING_MODE,AMOUNTS_AS_STRINGS", ""},
+		{"interest rate curves", map[string]string{"INTEREST_RATE_CURVES_PATH": curvesPath}, "INTEREST_RATE_CURVES_PATH", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := load(testSource(tt.values))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			merged, result := base.Merge(next)
+			if got := strings.Join(result.Applied, ","); got != tt.expectedApplied {
+				t.Errorf("Expected applied %q, got %q", tt.expectedApplied, got)
+			}
+			if got := strings.Join(result.Rejected, ","); got != tt.expectedRejected {
+				t.Errorf("Expected rejected %q, got %q", tt.expectedRejected, got)
+			}
+
+			// Rejected settings keep their old values; reloadable settings take the new ones
+			if merged.Port != base.Port {
+				t.Errorf("Expected port %s to be kept, got %s", base.Port, merged.Port)
+			}
+			if merged.RateHistoryPath != base.RateHistoryPath {
+				t.Errorf("Expected rate history path %q to be kept, got %q", base.RateHistoryPath, merged.RateHistoryPath)
+			}
+			// Every reloadable setting reported as applied must take its new value in the merged config
+			mergedSettings, nextSettings := merged.settings(false), next.settings(false)
+			for i, setting := range mergedSettings {
+				if reloadableSettings[setting.Name] && setting.Value != nextSettings[i].Value {
+					t.Errorf("Expected %s=%q from the new config, got %q", setting.Name, nextSettings[i].Value, setting.Value)
+				}
+			}
+			if base.LogLevel != "info" {
+				t.Errorf("Expected Merge to leave the receiver unchanged, got log level %s", base.LogLevel)
+			}
+		})
+	}
+}
+
+func TestReloader_Reload(t *testing.T) {
+	initial := &Config{Port: "8082", LogLevel: "info", SupportedCurrencies: []string{"USD", "EUR"}}
+	next := &Config{Port: "9000", LogLevel: "debug", SupportedCurrencies: []string{"USD", "EUR", "GBP"}}
+	loadErr := errors.New("invalid configuration")
+
+	var loadResult *Config
+	var loadFailure error
+	reloader := NewReloader(ReloaderConfig{
+		Initial: initial,
+		Load:    func() (*Config, error) { return loadResult, loadFailure },
+		Logger:  logger.New("error"),
+	})
+	var events []ReloadEvent
+	reloader.Subscribe(func(event ReloadEvent) { events = append(events, event) })
+
+	loadResult = next
+	event := reloader.Reload(ReloadTriggerSignal)
+	if event.Err != nil {
+		t.Fatalf("Expected no error, got %v", event.Err)
+	}
+	current := reloader.Current()
+	if current.LogLevel != "debug" || len(current.SupportedCurrencies) != 3 {
+		t.Errorf("Expected reloadable settings to be swapped in, got %+v", current)
+	}
+	if current.Port != "8082" {
+		t.Errorf("Expected port change to be rejected, got %s", current.Port)
+	}
+	if strings.Join(event.Rejected, ",") != "PORT" {
+		t.Errorf("Expected PORT to be rejected, got %v", event.Rejected)
+	}
+
+	loadResult, loadFailure = nil, loadErr
+	event = reloader.Reload(ReloadTriggerSignal)
+	if !errors.Is(event.Err, loadErr) {
+		t.Errorf("Expected load error, got %v", event.Err)
+	}
+	if reloader.Current() != current {
+		t.Error("Expected a failed reload to keep the current configuration")
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 reload events, got %d", len(events))
+	}
+	if events[0].Trigger != ReloadTriggerSignal || events[0].Config != current {
+		t.Errorf("Expected first event to carry the reloaded config, got %+v", events[0])
+	}
+}
+
+func TestReloader_WatchFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte("server:\n  log_level: info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	load := func() (*Config, error) {
+		return LoadWithOptions(Options{ConfigFile: file, DotEnvFile: filepath.Join(filepath.Dir(file), ".env")})
+	}
+	os.Clearenv()
+	initial, err := load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	reloader := NewReloader(ReloaderConfig{Initial: initial, Load: load, Logger: logger.New("error"), PollInterval: 10 * time.Millisecond})
+	reloaded := make(chan ReloadEvent, 1)
+	reloader.Subscribe(func(event ReloadEvent) { reloaded <- event })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.WatchFile(ctx)
+
+	// Give the watcher time to record the initial file state
+	time.Sleep(30 * time.Millisecond)
+	if err := os.WriteFile(file, []byte("server:\n  log_level: debug\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-reloaded:
+		if event.Trigger != ReloadTriggerFileChange {
+			t.Errorf("Expected trigger %s, got %s", ReloadTriggerFileChange, event.Trigger)
+		}
+		if reloader.Current().LogLevel != "debug" {
+			t.Errorf("Expected log level debug after file change, got %s", reloader.Current().LogLevel)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a reload after the config file changed")
+	}
+}
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+	"github.com/dalfonso89/financial-forecasting-service/curve"
+)
+
+// FieldError describes one invalid configuration variable
+type FieldError struct {
+	Variable string
+	Value    string
+	Message  string
+	Source   string // Configuration layer that set the value; empty for defaults
+}
+
+// String renders the error as VARIABLE="value" (from source): message
+func (e FieldError) String() string {
+	if e.Source != "" {
+		return fmt.Sprintf("%s=%q (from %s): %s", e.Variable, e.Value, e.Source, e.Message)
+	}
+	return fmt.Sprintf("%s=%q: %s", e.Variable, e.Value, e.Message)
+}
+
+// ValidationError lists every invalid configuration variable found by Load
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("invalid configuration (%d errors):", len(e.Errors)))
+	for _, fieldErr := range e.Errors {
+		lines = append(lines, "  "+fieldErr.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validLogLevels are the levels understood by logger.New
+var validLogLevels = map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
+
+// validForecastTypes are the forecast models implemented by the service
+var validForecastTypes = map[string]bool{"linear": true, "exponential": true, "moving_average": true}
+
+// validRoundingModes are the rounding modes understood by the money package
+var validRoundingModes = map[string]bool{"half_up": true, "half_even": true}
+
+// minAPIKeyLength rejects API keys too short to resist guessing
+const minAPIKeyLength = 16
+
+// validate checks semantic constraints on an already parsed Config
+func (c *Config) validate() []FieldError {
+	var errs []FieldError
+	add := func(variable, value, format string, args ...interface{}) {
+		errs = append(errs, FieldError{Variable: variable, Value: value, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		add("PORT", c.Port, "must be an integer between 1 and 65535")
+	}
+	if !validLogLevels[c.LogLevel] {
+		add("LOG_LEVEL", c.LogLevel, "must be one of debug, info, warn, error")
+	}
+
+	if err := validateHTTPURL(c.CurrencyExchangeServiceURL); err != nil {
+		add("CURRENCY_EXCHANGE_SERVICE_URL", c.CurrencyExchangeServiceURL, "%v", err)
+	}
+	checkSeconds := func(variable string, value time.Duration, min, max int) {
+		if seconds := int(value / time.Second); seconds < min || seconds > max {
+			add(variable, strconv.Itoa(seconds), "must be between %d and %d seconds", min, max)
+		}
+	}
+	checkSeconds("CURRENCY_EXCHANGE_TIMEOUT_SECONDS", c.CurrencyExchangeTimeout, 1, 300)
+	checkSeconds("FORECAST_CACHE_TTL_SECONDS", c.ForecastCacheTTL, 1, 86400)
+	checkSeconds("CORS_MAX_AGE_SECONDS", c.CORSMaxAge, 0, 86400)
+	checkSeconds("READINESS_CHECK_TIMEOUT_SECONDS", c.ReadinessCheckTimeout, 1, 60)
+	checkSeconds("ACCURACY_CHECK_INTERVAL_SECONDS", c.AccuracyCheckInterval, 60, 86400)
+	checkSeconds("RATE_SNAPSHOT_INTERVAL_SECONDS", c.RateSnapshotInterval, 60, 86400)
+	if days := int(c.AccuracyWindow / (24 * time.Hour)); days < 1 || days > 365 {
+		add("ACCURACY_WINDOW_DAYS", strconv.Itoa(days), "must be between 1 and 365 days")
+	}
+
+	if c.MaxConcurrentRequests < 1 || c.MaxConcurrentRequests > 10000 {
+		add("MAX_CONCURRENT_REQUESTS", strconv.Itoa(c.MaxConcurrentRequests), "must be between 1 and 10000")
+	}
+	if c.ForecastHistoryMemoryLimit < 1 || c.ForecastHistoryMemoryLimit > 1000000 {
+		add("FORECAST_HISTORY_MEMORY_LIMIT", strconv.Itoa(c.ForecastHistoryMemoryLimit), "must be between 1 and 1000000")
+	}
+	if c.DefaultForecastPeriods < 1 || c.DefaultForecastPeriods > 365 {
+		add("DEFAULT_FORECAST_PERIODS", strconv.Itoa(c.DefaultForecastPeriods), "must be between 1 and 365")
+	}
+
+	if !validForecastTypes[c.DefaultForecastType] {
+		add("DEFAULT_FORECAST_TYPE", c.DefaultForecastType, "must be one of linear, exponential, moving_average")
+	}
+	for pair, model := range c.ModelPairs {
+		currencies := strings.Split(pair, "/")
+		if len(currencies) != 2 || !currency.IsValid(currencies[0]) || !currency.IsValid(currencies[1]) {
+			add("FORECAST_MODEL_PAIRS", pair, "must be a BASE/TARGET pair of ISO 4217 codes")
+		}
+		if model.ForecastType != "" && !validForecastTypes[model.ForecastType] {
+			add("FORECAST_MODEL_PAIRS", pair, "type must be one of linear, exponential, moving_average")
+		}
+		if model.Periods < 0 || model.Periods > 365 {
+			add("FORECAST_MODEL_PAIRS", pair, "periods must be between 1 and 365")
+		}
+	}
+
+	if _, err := curve.Load(c.InterestRateCurvesPath); err != nil {
+		add("INTEREST_RATE_CURVES_PATH", c.InterestRateCurvesPath, "%v", err)
+	}
+
+	if !validRoundingModes[c.RoundingMode] {
+		add("ROUNDING_MODE", c.RoundingMode, "must be one of half_up, half_even")
+	}
+
+	seen := make(map[string]bool, len(c.SupportedCurrencies))
+	for _, code := range c.SupportedCurrencies {
+		switch {
+		case !currency.IsValid(code):
+			add("SUPPORTED_CURRENCIES", code, "is not an ISO 4217 currency code")
+		case seen[code]:
+			add("SUPPORTED_CURRENCIES", code, "is listed more than once")
+		}
+		seen[code] = true
+	}
+
+	for _, origin := range c.CORSAllowedOrigins {
+		if origin == "*" {
+			if c.CORSAllowCredentials {
+				add("CORS_ALLOW_CREDENTIALS", "true", "cannot be combined with CORS_ALLOWED_ORIGINS=*; list the trusted origins instead")
+			}
+			continue
+		}
+		if err := validateHTTPURL(strings.Replace(origin, "://*.", "://", 1)); err != nil {
+			add("CORS_ALLOWED_ORIGINS", origin, "must be * or an origin such as https://app.example.com: %v", err)
+		}
+	}
+	for _, method := range c.CORSAllowedMethods {
+		if !isHTTPToken(method) {
+			add("CORS_ALLOWED_METHODS", method, "is not a valid HTTP method")
+		}
+	}
+	for _, header := range c.CORSAllowedHeaders {
+		if !isHTTPToken(header) {
+			add("CORS_ALLOWED_HEADERS", header, "is not a valid header name")
+		}
+	}
+
+	if c.TracingOTLPEndpoint != "" {
+		if err := validateHTTPURL(c.TracingOTLPEndpoint); err != nil {
+			add("OTEL_EXPORTER_OTLP_ENDPOINT", c.TracingOTLPEndpoint, "%v", err)
+		}
+	}
+
+	for _, key := range c.APIKeys {
+		if len(key) < minAPIKeyLength {
+			add("API_KEYS", "REDACTED", "every key must be at least %d characters", minAPIKeyLength)
+			break
+		}
+	}
+
+	return errs
+}
+
+// validateHTTPURL checks that value is an absolute http or https URL
+func validateHTTPURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("must be a valid URL")
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("must use the http or https scheme")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("must include a host")
+	}
+	return nil
+}
+
+// isHTTPToken reports whether value is a non-empty RFC 7230 token
+func isHTTPToken(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		if char <= ' ' || char >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, char) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Setting is one resolved configuration variable
+type Setting struct {
+	Name  string
+	Value string
+}
+
+// Redacted lists the resolved configuration as variables with API keys and credentials in URLs removed
+func (c *Config) Redacted() []Setting {
+	return c.settings(true)
+}
+
+// settings lists the resolved configuration as variables, optionally redacting credentials
+func (c *Config) settings(redact bool) []Setting {
+	seconds := func(value time.Duration) string {
+		return strconv.Itoa(int(value / time.Second))
+	}
+	modelPairs := ""
+	if len(c.ModelPairs) > 0 {
+		// encoding/json sorts map keys, keeping the output stable
+		encoded, _ := json.Marshal(c.ModelPairs)
+		modelPairs = string(encoded)
+	}
+	apiKeys := strings.Join(c.APIKeys, ",")
+	redactedURL := func(value string) string { return value }
+	if redact {
+		if len(c.APIKeys) > 0 {
+			apiKeys = fmt.Sprintf("REDACTED (%d keys)", len(c.APIKeys))
+		}
+		redactedURL = redactURL
+	}
+	return []Setting{
+		{"PORT", c.Port},
+		{"LOG_LEVEL", c.LogLevel},
+		{"CURRENCY_EXCHANGE_SERVICE_URL", redactedURL(c.CurrencyExchangeServiceURL)},
+		{"CURRENCY_EXCHANGE_TIMEOUT_SECONDS", seconds(c.CurrencyExchangeTimeout)},
+		{"FORECAST_CACHE_TTL_SECONDS", seconds(c.ForecastCacheTTL)},
+		{"MAX_CONCURRENT_REQUESTS", strconv.Itoa(c.MaxConcurrentRequests)},
+		{"DEFAULT_FORECAST_PERIODS", strconv.Itoa(c.DefaultForecastPeriods)},
+		{"DEFAULT_FORECAST_TYPE", c.DefaultForecastType},
+		{"SUPPORTED_CURRENCIES", strings.Join(c.SupportedCurrencies, ",")},
+		{"FORECAST_MODEL_PAIRS", modelPairs},
+		{"CORS_ALLOWED_ORIGINS", strings.Join(c.CORSAllowedOrigins, ",")},
+		{"CORS_ALLOWED_METHODS", strings.Join(c.CORSAllowedMethods, ",")},
+		{"CORS_ALLOWED_HEADERS", strings.Join(c.CORSAllowedHeaders, ",")},
+		{"CORS_ALLOW_CREDENTIALS", strconv.FormatBool(c.CORSAllowCredentials)},
+		{"CORS_MAX_AGE_SECONDS", seconds(c.CORSMaxAge)},
+		{"ROUNDING_MODE", c.RoundingMode},
+		{"AMOUNTS_AS_STRINGS", strconv.FormatBool(c.AmountsAsStrings)},
+		{"FORECAST_HISTORY_PATH", c.ForecastHistoryPath},
+		{"FORECAST_HISTORY_MEMORY_LIMIT", strconv.Itoa(c.ForecastHistoryMemoryLimit)},
+		{"RATE_HISTORY_PATH", c.RateHistoryPath},
+		{"RATE_SNAPSHOT_INTERVAL_SECONDS", seconds(c.RateSnapshotInterval)},
+		{"INTEREST_RATE_CURVES_PATH", c.InterestRateCurvesPath},
+		{"ACCURACY_CHECK_INTERVAL_SECONDS", seconds(c.AccuracyCheckInterval)},
+		{"ACCURACY_WINDOW_DAYS", strconv.Itoa(int(c.AccuracyWindow / (24 * time.Hour)))},
+		{"READINESS_CHECK_TIMEOUT_SECONDS", seconds(c.ReadinessCheckTimeout)},
+		{"OTEL_EXPORTER_OTLP_ENDPOINT", redactedURL(c.TracingOTLPEndpoint)},
+		{"OTEL_SERVICE_NAME", c.TracingServiceName},
+		{"API_KEYS", apiKeys},
+	}
+}
+
+// redactURL masks any password or token embedded in a URL
+func redactURL(value string) string {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.User == nil {
+		return value
+	}
+	if _, hasPassword := parsed.User.Password(); hasPassword {
+		parsed.User = url.UserPassword(parsed.User.Username(), "REDACTED")
+	} else {
+		// A bare userinfo component is usually a token
+		parsed.User = url.User("REDACTED")
+	}
+	return parsed.String()
+}
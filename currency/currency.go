@@ -0,0 +1,97 @@
+package currency
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:embed iso4217.json
+var registryData []byte
+
+// registry indexes the embedded ISO 4217 table by alphabetic code
+var registry = mustLoadRegistry(registryData)
+
+// Currency describes an active ISO 4217 currency
+type Currency struct {
+	Code       string   `json:"code"`        // Alphabetic code, such as "USD"
+	Numeric    string   `json:"numeric"`     // Three-digit numeric code, such as "840"
+	Name       string   `json:"name"`        // ISO 4217 entity name
+	MinorUnits int      `json:"minor_units"` // Digits after the decimal separator
+	Symbol     string   `json:"symbol"`      // Common local symbol; several currencies share symbols such as "$"
+	Countries  []string `json:"countries"`   // ISO 3166-1 alpha-2 codes of the countries using it
+}
+
+// mustLoadRegistry parses the embedded table; it ships with the binary, so a bad one is a build defect
+func mustLoadRegistry(data []byte) map[string]Currency {
+	currencies, err := loadRegistry(data)
+	if err != nil {
+		panic(err)
+	}
+	return currencies
+}
+
+// loadRegistry parses and validates an ISO 4217 table
+func loadRegistry(data []byte) (map[string]Currency, error) {
+	var currencies []Currency
+	if err := json.Unmarshal(data, &currencies); err != nil {
+		return nil, fmt.Errorf("invalid ISO 4217 table: %w", err)
+	}
+
+	byCode := make(map[string]Currency, len(currencies))
+	numerics := make(map[string]string, len(currencies))
+	for _, currency := range currencies {
+		switch {
+		case len(currency.Code) != 3 || strings.ToUpper(currency.Code) != currency.Code:
+			return nil, fmt.Errorf("invalid ISO 4217 code %q", currency.Code)
+		case len(currency.Numeric) != 3:
+			return nil, fmt.Errorf("%s: numeric code must have 3 digits, got %q", currency.Code, currency.Numeric)
+		case currency.MinorUnits < 0 || currency.MinorUnits > 4:
+			return nil, fmt.Errorf("%s: minor units must be between 0 and 4, got %d", currency.Code, currency.MinorUnits)
+		case currency.Name == "" || len(currency.Countries) == 0:
+			return nil, fmt.Errorf("%s: name and countries are required", currency.Code)
+		}
+		if _, exists := byCode[currency.Code]; exists {
+			return nil, fmt.Errorf("duplicate ISO 4217 code %s", currency.Code)
+		}
+		if other, exists := numerics[currency.Numeric]; exists {
+			return nil, fmt.Errorf("%s and %s share numeric code %s", other, currency.Code, currency.Numeric)
+		}
+		byCode[currency.Code] = currency
+		numerics[currency.Numeric] = currency.Code
+	}
+	return byCode, nil
+}
+
+// Normalize trims and upper-cases a currency code so lookups are case-insensitive
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Lookup returns the currency with an alphabetic code, ignoring case and surrounding space
+func Lookup(code string) (Currency, bool) {
+	currency, ok := registry[Normalize(code)]
+	if ok {
+		currency.Countries = append([]string(nil), currency.Countries...)
+	}
+	return currency, ok
+}
+
+// IsValid reports whether code is an active ISO 4217 alphabetic code, ignoring case
+func IsValid(code string) bool {
+	_, ok := registry[Normalize(code)]
+	return ok
+}
+
+// All returns every currency in the registry, sorted by code
+func All() []Currency {
+	currencies := make([]Currency, 0, len(registry))
+	for code := range registry {
+		currency, _ := Lookup(code)
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Code < currencies[j].Code })
+	return currencies
+}
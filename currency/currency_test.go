@@ -0,0 +1,92 @@
+package currency
+
+import "testing"
+
+func TestLoadRegistry(t *testing.T) {
+	currencies, err := loadRegistry(registryData)
+	if err != nil {
+		t.Fatalf("Expected the embedded ISO 4217 table to parse, got %v", err)
+	}
+	if len(currencies) < 150 {
+		t.Errorf("Expected at least 150 active currencies, got %d", len(currencies))
+	}
+}
+
+func TestLoadRegistry_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"malformed", `{`},
+		{"lower-case code", `[{"code":"usd","numeric":"840","name":"US Dollar","minor_units":2,"countries":["US"]}]`},
+		{"short numeric", `[{"code":"USD","numeric":"84","name":"US Dollar","minor_units":2,"countries":["US"]}]`},
+		{"minor units out of range", `[{"code":"USD","numeric":"840","name":"US Dollar","minor_units":9,"countries":["US"]}]`},
+		{"no countries", `[{"code":"USD","numeric":"840","name":"US Dollar","minor_units":2}]`},
+		{"duplicate code", `[{"code":"USD","numeric":"840","name":"A","minor_units":2,"countries":["US"]},{"code":"USD","numeric":"841","name":"B","minor_units":2,"countries":["US"]}]`},
+		{"duplicate numeric", `[{"code":"USD","numeric":"840","name":"A","minor_units":2,"countries":["US"]},{"code":"USN","numeric":"840","name":"B","minor_units":2,"countries":["US"]}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadRegistry([]byte(tt.data)); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code       string
+		name       string
+		numeric    string
+		minorUnits int
+		country    string
+	}{
+		{"USD", "US Dollar", "840", 2, "US"},
+		{"jpy", "Yen", "392", 0, "JP"},
+		{" kwd ", "Kuwaiti Dinar", "414", 3, "KW"},
+		{"EUR", "Euro", "978", 2, "DE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			currency, ok := Lookup(tt.code)
+			if !ok {
+				t.Fatalf("Expected %q to be found", tt.code)
+			}
+			if currency.Name != tt.name || currency.Numeric != tt.numeric || currency.MinorUnits != tt.minorUnits {
+				t.Errorf("Expected %s %s with %d minor units, got %+v", tt.name, tt.numeric, tt.minorUnits, currency)
+			}
+			found := false
+			for _, country := range currency.Countries {
+				found = found || country == tt.country
+			}
+			if !found {
+				t.Errorf("Expected %s to be used in %s, got %v", currency.Code, tt.country, currency.Countries)
+			}
+		})
+	}
+
+	if _, ok := Lookup("XYZ"); ok {
+		t.Error("Expected XYZ not to be found")
+	}
+}
+
+func TestLookup_ReturnsCopy(t *testing.T) {
+	currency, _ := Lookup("USD")
+	currency.Countries[0] = "XX"
+	if again, _ := Lookup("USD"); again.Countries[0] == "XX" {
+		t.Error("Expected callers not to be able to modify the registry")
+	}
+}
+
+func TestAll_Sorted(t *testing.T) {
+	currencies := All()
+	for i := 1; i < len(currencies); i++ {
+		if currencies[i-1].Code >= currencies[i].Code {
+			t.Fatalf("Expected codes sorted, got %s before %s", currencies[i-1].Code, currencies[i].Code)
+		}
+	}
+	if !IsValid("chf") || IsValid("ABC") {
+		t.Error("Expected IsValid to be case-insensitive and reject unknown codes")
+	}
+}
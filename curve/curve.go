@@ -0,0 +1,167 @@
+// Package curve loads per-currency interest rate curves and prices forward exchange rates by covered interest parity
+package curve
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+)
+
+// defaultDayCount is the money market day count basis used when a curve does not set one
+const defaultDayCount = 360
+
+// Point is an annual interest rate for a term
+type Point struct {
+	Tenor string  // Such as ON, 1W, 3M or 1Y
+	Days  float64 // Approximate length of the tenor in days
+	Rate  float64 // Annual interest rate in percent, compounded annually
+}
+
+// Curve is one currency's interest rates by term
+type Curve struct {
+	Currency string
+	DayCount int     // Days in a year for accruing interest, 360 or 365
+	Points   []Point // Ordered by Days
+}
+
+// Set holds the curve of each currency, keyed by ISO 4217 code
+type Set map[string]*Curve
+
+// curveFile is one currency's entry in a curves file
+type curveFile struct {
+	DayCount int                `yaml:"day_count"`
+	Rates    map[string]float64 `yaml:"rates"`
+}
+
+// Load reads a YAML or JSON curves file; an empty path gives an empty set
+func Load(path string) (Set, error) {
+	if path == "" {
+		return Set{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read interest rate curves: %w", err)
+	}
+	set, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interest rate curves in %s: %w", path, err)
+	}
+	return set, nil
+}
+
+// Parse decodes curves keyed by currency code, each with an optional day_count and annual percent rates by tenor
+func Parse(data []byte) (Set, error) {
+	var entries map[string]curveFile
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	set := make(Set, len(entries))
+	for code, entry := range entries {
+		code = currency.Normalize(code)
+		if !currency.IsValid(code) {
+			return nil, fmt.Errorf("%s is not an ISO 4217 currency code", code)
+		}
+		if _, exists := set[code]; exists {
+			return nil, fmt.Errorf("%s is listed more than once", code)
+		}
+		curve := &Curve{Currency: code, DayCount: entry.DayCount}
+		if curve.DayCount == 0 {
+			curve.DayCount = defaultDayCount
+		}
+		if curve.DayCount != 360 && curve.DayCount != 365 {
+			return nil, fmt.Errorf("%s: day_count must be 360 or 365", code)
+		}
+		if len(entry.Rates) == 0 {
+			return nil, fmt.Errorf("%s: at least one rate is required", code)
+		}
+		for tenor, rate := range entry.Rates {
+			days, err := TenorDays(tenor)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", code, err)
+			}
+			if math.IsNaN(rate) || rate <= -100 || rate >= 100 {
+				return nil, fmt.Errorf("%s %s: rate must be a percentage between -100 and 100", code, tenor)
+			}
+			curve.Points = append(curve.Points, Point{Tenor: strings.ToUpper(tenor), Days: days, Rate: rate})
+		}
+		sort.Slice(curve.Points, func(i, j int) bool { return curve.Points[i].Days < curve.Points[j].Days })
+		for i := 1; i < len(curve.Points); i++ {
+			if curve.Points[i].Days == curve.Points[i-1].Days {
+				return nil, fmt.Errorf("%s: tenors %s and %s have the same length", code, curve.Points[i-1].Tenor, curve.Points[i].Tenor)
+			}
+		}
+		set[code] = curve
+	}
+	return set, nil
+}
+
+// TenorDays returns the approximate length in days of a tenor such as ON, 2W, 6M or 1Y; months are a twelfth of a year
+func TenorDays(tenor string) (float64, error) {
+	tenor = strings.ToUpper(strings.TrimSpace(tenor))
+	if tenor == "ON" {
+		return 1, nil
+	}
+	if len(tenor) < 2 {
+		return 0, fmt.Errorf("invalid tenor %q", tenor)
+	}
+	count, err := strconv.Atoi(tenor[:len(tenor)-1])
+	if err != nil || count < 1 {
+		return 0, fmt.Errorf("invalid tenor %q", tenor)
+	}
+	switch tenor[len(tenor)-1] {
+	case 'D':
+		return float64(count), nil
+	case 'W':
+		return float64(count) * 7, nil
+	case 'M':
+		return float64(count) * 365 / 12, nil
+	case 'Y':
+		return float64(count) * 365, nil
+	}
+	return 0, fmt.Errorf("invalid tenor %q", tenor)
+}
+
+// Rate returns the annual percent rate for a term in days, interpolating linearly between tenors and holding the
+// shortest and longest rates flat beyond them
+func (c *Curve) Rate(days float64) float64 {
+	points := c.Points
+	if days <= points[0].Days {
+		return points[0].Rate
+	}
+	for i := 1; i < len(points); i++ {
+		if days <= points[i].Days {
+			weight := (days - points[i-1].Days) / (points[i].Days - points[i-1].Days)
+			return points[i-1].Rate + weight*(points[i].Rate-points[i-1].Rate)
+		}
+	}
+	return points[len(points)-1].Rate
+}
+
+// Growth returns what one unit grows to over a term in days, compounding the annual rate; rates above -100% always
+// grow to a positive amount, though it may underflow to zero over very long terms
+func (c *Curve) Growth(days float64) float64 {
+	return math.Pow(1+c.Rate(days)/100, days/float64(c.DayCount))
+}
+
+// Forward returns the forward rate for a term in days from a spot rate in units of quote per unit of base, by covered
+// interest parity; it fails when the curves cannot price a finite, positive forward over the term
+func Forward(spot float64, base, quote *Curve, days float64) (float64, error) {
+	baseGrowth, quoteGrowth := base.Growth(days), quote.Growth(days)
+	if !(baseGrowth > 0) || !(quoteGrowth > 0) || math.IsInf(baseGrowth, 0) || math.IsInf(quoteGrowth, 0) {
+		return 0, errors.New("interest rates compound out of range")
+	}
+	forward := spot * quoteGrowth / baseGrowth
+	if !(forward > 0) || math.IsInf(forward, 0) {
+		return 0, errors.New("forward rate out of range")
+	}
+	return forward, nil
+}
@@ -0,0 +1,136 @@
+package curve
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testCurves = `
+usd:
+  rates: {ON: 5.3, 1M: 5.3, 3M: 5.4, 1Y: 5.0}
+GBP:
+  day_count: 365
+  rates: {1M: 5.2}
+`
+
+func TestParse(t *testing.T) {
+	set, err := Parse([]byte(testCurves))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	usd := set["USD"]
+	if usd == nil || usd.DayCount != 360 || len(usd.Points) != 4 || usd.Points[0].Tenor != "ON" {
+		t.Fatalf("Expected a USD curve on ACT/360 ordered from ON, got %+v", usd)
+	}
+	if set["GBP"].DayCount != 365 {
+		t.Errorf("Expected GBP on ACT/365, got %d", set["GBP"].DayCount)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"currency", "XYZ: {rates: {1M: 1}}"},
+		{"day count", "USD: {day_count: 252, rates: {1M: 1}}"},
+		{"no rates", "USD: {day_count: 360}"},
+		{"tenor", "USD: {rates: {1Q: 1}}"},
+		{"rate", "USD: {rates: {1M: 150}}"},
+		{"same length", "USD: {rates: {1W: 1, 7D: 2}}"},
+		{"syntax", "USD: [1, 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data)); err == nil {
+				t.Errorf("Expected an error for %s", tt.data)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if set, err := Load(""); err != nil || len(set) != 0 {
+		t.Errorf("Expected an empty set without a path, got %v and %v", set, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "curves.json")
+	os.WriteFile(path, []byte(`{"EUR": {"rates": {"3M": 3.9}}}`), 0o644)
+	set, err := Load(path)
+	if err != nil || set["EUR"].Rate(90) != 3.9 {
+		t.Errorf("Expected a JSON EUR curve, got %v and %v", set, err)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestCurve_Rate(t *testing.T) {
+	set, _ := Parse([]byte(testCurves))
+	usd := set["USD"]
+
+	tests := []struct {
+		days, rate float64
+	}{
+		{0.5, 5.3},        // Before the first tenor
+		{365.0 / 12, 5.3}, // On a tenor
+		{365.0 / 6, 5.35}, // Halfway from 1M to 3M
+		{365, 5.0},        // On the last tenor
+		{730, 5.0},        // Beyond the last tenor
+	}
+	for _, tt := range tests {
+		if rate := usd.Rate(tt.days); math.Abs(rate-tt.rate) > 1e-9 {
+			t.Errorf("Expected %v%% at %v days, got %v", tt.rate, tt.days, rate)
+		}
+	}
+}
+
+func TestForward(t *testing.T) {
+	base := &Curve{DayCount: 360, Points: []Point{{Days: 1, Rate: 5}}}
+	quote := &Curve{DayCount: 360, Points: []Point{{Days: 1, Rate: 3}}}
+
+	// 0.9 × 1.03^(90/360) / 1.05^(90/360)
+	expected := 0.9 * math.Pow(1.03, 0.25) / math.Pow(1.05, 0.25)
+	forward, err := Forward(0.9, base, quote, 90)
+	if err != nil || math.Abs(forward-expected) > 1e-12 {
+		t.Errorf("Expected forward %v, got %v and %v", expected, forward, err)
+	}
+	// The currency with the higher rate trades at a forward discount
+	if forward >= 0.9 {
+		t.Errorf("Expected a forward below spot, got %v", forward)
+	}
+}
+
+func TestForward_OutOfRange(t *testing.T) {
+	collapsing := &Curve{DayCount: 360, Points: []Point{{Days: 1, Rate: -99.99}}}
+	flat := &Curve{DayCount: 360, Points: []Point{{Days: 1, Rate: 0}}}
+
+	// A deeply negative rate stays positive over a year but underflows to zero over a century
+	if forward, err := Forward(1, collapsing, flat, 365); err != nil || !(forward > 0) {
+		t.Errorf("Expected a positive forward over a year, got %v and %v", forward, err)
+	}
+	if forward, err := Forward(1, collapsing, flat, 36500); err == nil {
+		t.Errorf("Expected an error over a century, got %v", forward)
+	}
+	if forward, err := Forward(1, flat, collapsing, 36500); err == nil {
+		t.Errorf("Expected an error for a collapsing quote currency, got %v", forward)
+	}
+}
+
+func TestTenorDays(t *testing.T) {
+	tests := map[string]float64{"ON": 1, "3d": 3, "2W": 14, "6M": 182.5, "2Y": 730}
+	for tenor, expected := range tests {
+		if days, err := TenorDays(tenor); err != nil || days != expected {
+			t.Errorf("Expected %s to be %v days, got %v and %v", tenor, expected, days, err)
+		}
+	}
+	for _, tenor := range []string{"", "M", "0M", "1Q", "-1W"} {
+		if _, err := TenorDays(tenor); err == nil {
+			t.Errorf("Expected an error for tenor %q", tenor)
+		}
+	}
+}
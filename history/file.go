@@ -0,0 +1,216 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+)
+
+// errClosed is returned by a FileStore after Close
+var errClosed = errors.New("forecast history store is closed")
+
+// FileStore keeps forecast history in an append-only JSON lines file; every record stays retrievable through an
+// in-memory index of file offsets, and the most recent forecasts, up to its memory limit, are also cached decoded
+type FileStore struct {
+	path  string
+	cache *index
+
+	// mu serializes appends so each record is written as one line, and guards the offset index
+	mu      sync.RWMutex
+	file    *os.File
+	size    int64
+	records map[string]recordSpan // Location of every record in the file by forecast ID
+	ids     []string              // Every forecast ID in the file, sorted
+}
+
+// recordSpan locates one record in the history file
+type recordSpan struct {
+	offset int64
+	length int
+}
+
+// OpenFileStore opens or creates the history file at path, indexing every record and caching the memoryLimit most recent
+func OpenFileStore(path string, memoryLimit int) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+
+	store := &FileStore{
+		path:    path,
+		cache:   newLimitedIndex(memoryLimit),
+		file:    file,
+		records: make(map[string]recordSpan),
+	}
+	if err := store.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// load indexes every complete record and positions the file for appending;
+// a partial last line left by a crash mid-write is truncated
+func (s *FileStore) load() error {
+	reader := bufio.NewReader(s.file)
+	var offset int64
+	for line := 1; ; line++ {
+		record, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(record) > 0 {
+				if err := s.file.Truncate(offset); err != nil {
+					return fmt.Errorf("failed to truncate partial history record: %w", err)
+				}
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read history file: %w", err)
+		}
+		span := recordSpan{offset: offset, length: len(record)}
+		offset += int64(len(record))
+
+		if record = bytes.TrimSpace(record); len(record) == 0 {
+			continue
+		}
+		var forecast models.ForecastResponse
+		if err := json.Unmarshal(record, &forecast); err != nil {
+			return fmt.Errorf("invalid history record at %s:%d: %w", s.path, line, err)
+		}
+		s.track(forecast.ForecastID, span)
+		s.cache.add(forecast)
+	}
+
+	if _, err := s.file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek history file: %w", err)
+	}
+	s.size = offset
+	return nil
+}
+
+// track records where a forecast is stored; a later record with the same ID replaces the earlier one
+func (s *FileStore) track(id string, span recordSpan) {
+	if _, exists := s.records[id]; !exists {
+		s.ids = insertID(s.ids, id)
+	}
+	s.records[id] = span
+}
+
+// read decodes the record at span; callers hold mu
+func (s *FileStore) read(span recordSpan) (models.ForecastResponse, error) {
+	var forecast models.ForecastResponse
+	if s.file == nil {
+		return forecast, errClosed
+	}
+	record := make([]byte, span.length)
+	if _, err := s.file.ReadAt(record, span.offset); err != nil {
+		return forecast, fmt.Errorf("failed to read history record: %w", err)
+	}
+	if err := json.Unmarshal(record, &forecast); err != nil {
+		return forecast, fmt.Errorf("invalid history record at offset %d of %s: %w", span.offset, s.path, err)
+	}
+	return forecast, nil
+}
+
+// Save implements Store; the record is synced to disk before Save returns
+func (s *FileStore) Save(ctx context.Context, forecast *models.ForecastResponse) error {
+	record, err := json.Marshal(forecast)
+	if err != nil {
+		return fmt.Errorf("failed to encode forecast: %w", err)
+	}
+	record = append(record, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return errClosed
+	}
+	if _, err := s.file.Write(record); err != nil {
+		return fmt.Errorf("failed to write history record: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync history file: %w", err)
+	}
+
+	s.track(forecast.ForecastID, recordSpan{offset: s.size, length: len(record)})
+	s.size += int64(len(record))
+	s.cache.add(*forecast)
+	return nil
+}
+
+// Get implements Store, reading forecasts that are no longer cached from the file
+func (s *FileStore) Get(ctx context.Context, id string) (*models.ForecastResponse, error) {
+	if forecast, err := s.cache.get(id); err == nil {
+		return forecast, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	span, exists := s.records[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	forecast, err := s.read(span)
+	if err != nil {
+		return nil, err
+	}
+	return &forecast, nil
+}
+
+// List implements Store, reading forecasts that are no longer cached from the file
+func (s *FileStore) List(ctx context.Context, filter Filter) (Page, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return listIDs(s.ids, filter, func(id string) (models.ForecastResponse, error) {
+		if forecast, err := s.cache.get(id); err == nil {
+			return *forecast, nil
+		}
+		return s.read(s.records[id])
+	})
+}
+
+// Ping implements Store by checking that the history file is still open and present
+func (s *FileStore) Ping(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return errClosed
+	}
+	if _, err := os.Stat(s.path); err != nil {
+		return fmt.Errorf("history file unavailable: %w", err)
+	}
+	return nil
+}
+
+// Close implements Store
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// Open returns a FileStore for path, or a MemoryStore when path is empty; either keeps up to memoryLimit forecasts in memory,
+// and a FileStore can still retrieve older ones from its file
+func Open(path string, memoryLimit int) (Store, error) {
+	if path == "" {
+		return NewMemoryStoreWithLimit(memoryLimit), nil
+	}
+	return OpenFileStore(path, memoryLimit)
+}
@@ -0,0 +1,222 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/uuid"
+)
+
+func TestFileStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "forecasts.jsonl")
+
+	store, err := OpenFileStore(path, DefaultMemoryLimit)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	saved := seedForecasts(t, store,
+		[3]string{"USD", "EUR", "linear"},
+		[3]string{"USD", "GBP", "exponential"},
+	)
+	if err := store.Close(); err != nil {
+		t.Fatalf("Expected no error closing, got %v", err)
+	}
+
+	reopened, err := OpenFileStore(path, DefaultMemoryLimit)
+	if err != nil {
+		t.Fatalf("Expected no error reopening, got %v", err)
+	}
+	defer reopened.Close()
+
+	forecast, err := reopened.Get(context.Background(), saved[1].ForecastID)
+	if err != nil {
+		t.Fatalf("Expected stored forecast after reopen, got %v", err)
+	}
+	if forecast.TargetCurrency != "GBP" || !forecast.GeneratedAt.Equal(saved[1].GeneratedAt) || len(forecast.Forecasts) != 1 {
+		t.Errorf("Expected forecast to round-trip, got %+v", forecast)
+	}
+
+	// New records append after the existing ones
+	seedForecasts(t, reopened, [3]string{"USD", "JPY", "linear"})
+	page, _ := reopened.List(context.Background(), Filter{})
+	if len(page.Forecasts) != 3 {
+		t.Errorf("Expected 3 forecasts, got %d", len(page.Forecasts))
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Errorf("Expected 3 records in the file, got %d", lines)
+	}
+}
+
+func TestFileStore_TruncatesPartialRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forecasts.jsonl")
+	store, err := OpenFileStore(path, DefaultMemoryLimit)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	saved := seedForecasts(t, store, [3]string{"USD", "EUR", "linear"})
+	store.Close()
+
+	// Simulate a crash part way through writing a record
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	file.WriteString(`{"forecast_id":"0190`)
+	file.Close()
+
+	store, err = OpenFileStore(path, DefaultMemoryLimit)
+	if err != nil {
+		t.Fatalf("Expected partial record to be dropped, got %v", err)
+	}
+	defer store.Close()
+	seedForecasts(t, store, [3]string{"USD", "GBP", "linear"})
+
+	if _, err := store.Get(context.Background(), saved[0].ForecastID); err != nil {
+		t.Errorf("Expected the complete record to survive, got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.Contains(lines[1], `"GBP"`) {
+		t.Errorf("Expected the new record to replace the partial one, got:\n%s", data)
+	}
+}
+
+func TestFileStore_CachesMostRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forecasts.jsonl")
+	store, err := OpenFileStore(path, DefaultMemoryLimit)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	seedForecasts(t, store,
+		[3]string{"USD", "EUR", "linear"},
+		[3]string{"USD", "GBP", "linear"},
+		[3]string{"USD", "JPY", "linear"},
+	)
+	store.Close()
+
+	store, err = OpenFileStore(path, 2)
+	if err != nil {
+		t.Fatalf("Expected no error reopening, got %v", err)
+	}
+	defer store.Close()
+
+	if len(store.cache.ids) != 2 {
+		t.Errorf("Expected 2 forecasts cached, got %d", len(store.cache.ids))
+	}
+	page, _ := store.List(context.Background(), Filter{})
+	if len(page.Forecasts) != 3 {
+		t.Errorf("Expected every stored forecast to be listed, got %d", len(page.Forecasts))
+	}
+}
+
+func TestFileStore_RetrievesEvictedForecasts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forecasts.jsonl")
+	store, err := OpenFileStore(path, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+	saved := seedForecasts(t, store,
+		[3]string{"USD", "EUR", "linear"},
+		[3]string{"USD", "GBP", "exponential"},
+		[3]string{"USD", "JPY", "linear"},
+		[3]string{"USD", "CHF", "linear"},
+	)
+
+	if _, err := store.cache.get(saved[0].ForecastID); err != ErrNotFound {
+		t.Fatalf("Expected the oldest forecast to be evicted from the cache, got %v", err)
+	}
+	forecast, err := store.Get(context.Background(), saved[0].ForecastID)
+	if err != nil {
+		t.Fatalf("Expected the oldest forecast to be read from the file, got %v", err)
+	}
+	if forecast.TargetCurrency != "EUR" || !forecast.GeneratedAt.Equal(saved[0].GeneratedAt) || len(forecast.Forecasts) != 1 {
+		t.Errorf("Expected evicted forecast to round-trip, got %+v", forecast)
+	}
+	if _, err := store.Get(context.Background(), "missing"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for an unknown ID, got %v", err)
+	}
+
+	// A range entirely older than the cached forecasts is served from the file
+	page, err := store.List(context.Background(), Filter{From: saved[0].GeneratedAt, To: saved[2].GeneratedAt})
+	if err != nil {
+		t.Fatalf("Expected no error listing, got %v", err)
+	}
+	if len(page.Forecasts) != 2 || page.Forecasts[0].ForecastID != saved[1].ForecastID || page.Forecasts[1].ForecastID != saved[0].ForecastID {
+		t.Errorf("Expected the 2 evicted forecasts newest first, got %+v", page.Forecasts)
+	}
+
+	// Pagination continues from the cache into the file
+	page, _ = store.List(context.Background(), Filter{Limit: 3})
+	if len(page.Forecasts) != 3 || page.NextCursor == "" {
+		t.Fatalf("Expected a full first page with a cursor, got %d forecasts", len(page.Forecasts))
+	}
+	page, _ = store.List(context.Background(), Filter{Limit: 3, Cursor: page.NextCursor})
+	if len(page.Forecasts) != 1 || page.Forecasts[0].ForecastID != saved[0].ForecastID {
+		t.Errorf("Expected the oldest forecast on the second page, got %+v", page.Forecasts)
+	}
+
+	// Saving after records were read keeps appending at the end of the file
+	generatedAt := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	if err := store.Save(context.Background(), &models.ForecastResponse{ForecastID: uuid.NewV7(generatedAt), GeneratedAt: generatedAt}); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+	if forecast, err := store.Get(context.Background(), saved[1].ForecastID); err != nil || forecast.ForecastType != "exponential" {
+		t.Errorf("Expected evicted forecast to stay readable after a save, got %+v, %v", forecast, err)
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 5 {
+		t.Errorf("Expected 5 records in the file, got %d", lines)
+	}
+}
+
+func TestFileStore_CorruptRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forecasts.jsonl")
+	if err := os.WriteFile(path, []byte("{\"forecast_id\":\"a\"}\nnot json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := OpenFileStore(path, DefaultMemoryLimit)
+	if err == nil || !strings.Contains(err.Error(), path+":2") {
+		t.Errorf("Expected error naming line 2, got %v", err)
+	}
+}
+
+func TestFileStore_Closed(t *testing.T) {
+	store, err := OpenFileStore(filepath.Join(t.TempDir(), "forecasts.jsonl"), DefaultMemoryLimit)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Ping(context.Background()); err != nil {
+		t.Errorf("Expected open store to be healthy, got %v", err)
+	}
+	store.Close()
+
+	if err := store.Ping(context.Background()); err == nil {
+		t.Error("Expected closed store to fail Ping")
+	}
+	if err := store.Save(context.Background(), &models.ForecastResponse{ForecastID: "closed"}); err == nil {
+		t.Error("Expected closed store to reject Save")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	store, err := Open("", 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if memory, ok := store.(*MemoryStore); !ok || memory.index.limit != 5 {
+		t.Errorf("Expected MemoryStore limited to 5 forecasts for an empty path, got %T", store)
+	}
+
+	store, err = Open(filepath.Join(t.TempDir(), "forecasts.jsonl"), 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+	if _, ok := store.(*FileStore); !ok {
+		t.Errorf("Expected FileStore for a path, got %T", store)
+	}
+}
@@ -0,0 +1,221 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+)
+
+// ErrNotFound is returned when no stored forecast has the requested ID
+var ErrNotFound = errors.New("forecast not found")
+
+// Pagination limits for List
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Store persists generated forecasts so they can be retrieved later
+type Store interface {
+	// Save stores a forecast; the caller assigns its ForecastID
+	Save(ctx context.Context, forecast *models.ForecastResponse) error
+	// Get returns the forecast with the given ID or ErrNotFound
+	Get(ctx context.Context, id string) (*models.ForecastResponse, error)
+	// List returns one page of forecasts matching filter, newest first
+	List(ctx context.Context, filter Filter) (Page, error)
+	// Ping reports whether the store can accept writes
+	Ping(ctx context.Context) error
+	// Close releases the store's resources
+	Close() error
+}
+
+// Filter selects forecasts for List; zero fields match everything
+type Filter struct {
+	Pair         string    // BASE/TARGET
+	ForecastType string    // linear, exponential or moving_average
+	From         time.Time // Inclusive lower bound on GeneratedAt
+	To           time.Time // Exclusive upper bound on GeneratedAt
+	Limit        int       // Page size; defaults to DefaultLimit and is capped at MaxLimit
+	Cursor       string    // NextCursor of the previous page
+}
+
+// Page is one page of List results
+type Page struct {
+	Forecasts  []models.ForecastResponse
+	NextCursor string // Empty on the last page
+}
+
+// DefaultMemoryLimit is the number of forecasts a MemoryStore from NewMemoryStore keeps
+const DefaultMemoryLimit = 10000
+
+// index keeps forecasts in memory, ordered by ID; UUIDv7 IDs sort by creation time
+type index struct {
+	mu        sync.RWMutex
+	forecasts map[string]models.ForecastResponse
+	ids       []string
+	limit     int // Oldest forecasts are evicted beyond this many; zero keeps every forecast
+}
+
+// newIndex creates an empty index that keeps every forecast
+func newIndex() *index {
+	return newLimitedIndex(0)
+}
+
+// newLimitedIndex creates an empty index that keeps the limit most recent forecasts
+func newLimitedIndex(limit int) *index {
+	return &index{forecasts: make(map[string]models.ForecastResponse), limit: limit}
+}
+
+// add inserts or replaces a forecast, evicting the oldest beyond the limit
+func (idx *index) add(forecast models.ForecastResponse) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	id := forecast.ForecastID
+	if _, exists := idx.forecasts[id]; !exists {
+		idx.ids = insertID(idx.ids, id)
+	}
+	idx.forecasts[id] = forecast
+
+	// Reslicing drops the evicted IDs without copying; append reallocates to the live IDs as the slice grows
+	if idx.limit > 0 && len(idx.ids) > idx.limit {
+		evicted := len(idx.ids) - idx.limit
+		for _, id := range idx.ids[:evicted] {
+			delete(idx.forecasts, id)
+		}
+		idx.ids = idx.ids[evicted:]
+	}
+}
+
+// get returns a copy of the forecast with the given ID
+func (idx *index) get(id string) (*models.ForecastResponse, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	forecast, exists := idx.forecasts[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return &forecast, nil
+}
+
+// list walks the index newest first, starting after the cursor
+func (idx *index) list(filter Filter) Page {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// Lookups cannot fail: every indexed ID has a forecast
+	page, _ := listIDs(idx.ids, filter, func(id string) (models.ForecastResponse, error) {
+		return idx.forecasts[id], nil
+	})
+	return page
+}
+
+// listIDs walks sorted forecast IDs newest first, starting after the cursor, and loads each with lookup
+func listIDs(ids []string, filter Filter, lookup func(id string) (models.ForecastResponse, error)) (Page, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	start := len(ids) - 1
+	if filter.Cursor != "" {
+		// Resume at the newest ID older than the cursor
+		start = sort.SearchStrings(ids, filter.Cursor) - 1
+	}
+
+	page := Page{Forecasts: []models.ForecastResponse{}}
+	for i := start; i >= 0; i-- {
+		forecast, err := lookup(ids[i])
+		if err != nil {
+			return Page{}, err
+		}
+		if !filter.matches(forecast) {
+			continue
+		}
+		if len(page.Forecasts) == limit {
+			page.NextCursor = page.Forecasts[limit-1].ForecastID
+			break
+		}
+		page.Forecasts = append(page.Forecasts, forecast)
+	}
+	return page, nil
+}
+
+// insertID adds id to sorted IDs; new IDs usually sort last, so they are appended
+func insertID(ids []string, id string) []string {
+	if last := len(ids) - 1; last < 0 || ids[last] < id {
+		return append(ids, id)
+	}
+	position := sort.SearchStrings(ids, id)
+	ids = append(ids, "")
+	copy(ids[position+1:], ids[position:])
+	ids[position] = id
+	return ids
+}
+
+// matches reports whether a forecast passes the filter
+func (f Filter) matches(forecast models.ForecastResponse) bool {
+	if f.Pair != "" && !strings.EqualFold(f.Pair, forecast.BaseCurrency+"/"+forecast.TargetCurrency) {
+		return false
+	}
+	if f.ForecastType != "" && f.ForecastType != forecast.ForecastType {
+		return false
+	}
+	if !f.From.IsZero() && forecast.GeneratedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !forecast.GeneratedAt.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// MemoryStore keeps the most recent forecasts in memory, evicting the oldest beyond its limit; it is lost on restart
+type MemoryStore struct {
+	index *index
+}
+
+// NewMemoryStore creates an empty in-memory store that keeps DefaultMemoryLimit forecasts
+func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithLimit(DefaultMemoryLimit)
+}
+
+// NewMemoryStoreWithLimit creates an empty in-memory store that keeps the limit most recent forecasts
+func NewMemoryStoreWithLimit(limit int) *MemoryStore {
+	return &MemoryStore{index: newLimitedIndex(limit)}
+}
+
+// Save implements Store
+func (s *MemoryStore) Save(ctx context.Context, forecast *models.ForecastResponse) error {
+	s.index.add(*forecast)
+	return nil
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(ctx context.Context, id string) (*models.ForecastResponse, error) {
+	return s.index.get(id)
+}
+
+// List implements Store
+func (s *MemoryStore) List(ctx context.Context, filter Filter) (Page, error) {
+	return s.index.list(filter), nil
+}
+
+// Ping implements Store
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close implements Store
+func (s *MemoryStore) Close() error {
+	return nil
+}
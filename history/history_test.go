@@ -0,0 +1,189 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/uuid"
+)
+
+// seedForecasts saves forecasts one hour apart, oldest first, and returns them
+func seedForecasts(t *testing.T, store Store, specs ...[3]string) []models.ForecastResponse {
+	t.Helper()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	var saved []models.ForecastResponse
+	for i, spec := range specs {
+		generatedAt := start.Add(time.Duration(i) * time.Hour)
+		forecast := models.ForecastResponse{
+			ForecastID:     uuid.NewV7(generatedAt),
+			BaseCurrency:   spec[0],
+			TargetCurrency: spec[1],
+			ForecastType:   spec[2],
+			GeneratedAt:    generatedAt,
+			Forecasts:      []models.ForecastPeriod{{Period: 1, Rate: 0.9}},
+		}
+		if err := store.Save(context.Background(), &forecast); err != nil {
+			t.Fatalf("Expected no error saving forecast, got %v", err)
+		}
+		saved = append(saved, forecast)
+	}
+	return saved
+}
+
+func TestMemoryStore_Get(t *testing.T) {
+	store := NewMemoryStore()
+	saved := seedForecasts(t, store, [3]string{"USD", "EUR", "linear"})
+
+	forecast, err := store.Get(context.Background(), saved[0].ForecastID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if forecast.ForecastID != saved[0].ForecastID || forecast.TargetCurrency != "EUR" {
+		t.Errorf("Expected stored forecast, got %+v", forecast)
+	}
+
+	if _, err := store.Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStore_EvictsOldest(t *testing.T) {
+	store := NewMemoryStoreWithLimit(2)
+	saved := seedForecasts(t, store,
+		[3]string{"USD", "EUR", "linear"},
+		[3]string{"USD", "GBP", "linear"},
+		[3]string{"USD", "JPY", "linear"},
+	)
+
+	if _, err := store.Get(context.Background(), saved[0].ForecastID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the oldest forecast to be evicted, got %v", err)
+	}
+	page, _ := store.List(context.Background(), Filter{})
+	if len(page.Forecasts) != 2 || page.Forecasts[0].ForecastID != saved[2].ForecastID || page.Forecasts[1].ForecastID != saved[1].ForecastID {
+		t.Errorf("Expected the 2 newest forecasts, got %+v", page.Forecasts)
+	}
+
+	// Saving a stored forecast again does not evict another
+	if err := store.Save(context.Background(), &saved[1]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.Get(context.Background(), saved[2].ForecastID); err != nil {
+		t.Errorf("Expected the newest forecast to be kept, got %v", err)
+	}
+}
+
+func TestMemoryStore_List(t *testing.T) {
+	store := NewMemoryStore()
+	saved := seedForecasts(t, store,
+		[3]string{"USD", "EUR", "linear"},
+		[3]string{"USD", "GBP", "linear"},
+		[3]string{"USD", "EUR", "exponential"},
+		[3]string{"USD", "EUR", "linear"},
+	)
+	at := func(i int) time.Time { return saved[i].GeneratedAt }
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []int // Indexes into saved, in expected order
+	}{
+		{"everything newest first", Filter{}, []int{3, 2, 1, 0}},
+		{"by pair", Filter{Pair: "usd/eur"}, []int{3, 2, 0}},
+		{"by type", Filter{ForecastType: "linear"}, []int{3, 1, 0}},
+		{"by pair and type", Filter{Pair: "USD/EUR", ForecastType: "linear"}, []int{3, 0}},
+		{"from is inclusive", Filter{From: at(2)}, []int{3, 2}},
+		{"to is exclusive", Filter{To: at(2)}, []int{1, 0}},
+		{"time window", Filter{From: at(1), To: at(3)}, []int{2, 1}},
+		{"no matches", Filter{Pair: "EUR/JPY"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := store.List(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(page.Forecasts) != len(tt.expected) {
+				t.Fatalf("Expected %d forecasts, got %d", len(tt.expected), len(page.Forecasts))
+			}
+			for i, index := range tt.expected {
+				if page.Forecasts[i].ForecastID != saved[index].ForecastID {
+					t.Errorf("Expected forecast %d at position %d, got %s", index, i, page.Forecasts[i].ForecastID)
+				}
+			}
+			if page.NextCursor != "" {
+				t.Errorf("Expected no next cursor on a single page, got %s", page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_ListPagination(t *testing.T) {
+	store := NewMemoryStore()
+	saved := seedForecasts(t, store,
+		[3]string{"USD", "EUR", "linear"},
+		[3]string{"USD", "GBP", "linear"},
+		[3]string{"USD", "EUR", "linear"},
+		[3]string{"USD", "EUR", "linear"},
+		[3]string{"USD", "EUR", "linear"},
+	)
+
+	var seen []string
+	filter := Filter{Pair: "USD/EUR", Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("Expected pagination to finish")
+		}
+		page, err := store.List(context.Background(), filter)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, forecast := range page.Forecasts {
+			seen = append(seen, forecast.ForecastID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	expected := []string{saved[4].ForecastID, saved[3].ForecastID, saved[2].ForecastID, saved[0].ForecastID}
+	if len(seen) != len(expected) {
+		t.Fatalf("Expected %d forecasts across pages, got %d", len(expected), len(seen))
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Errorf("Expected %s at position %d, got %s", expected[i], i, seen[i])
+		}
+	}
+}
+
+func TestFilter_Limit(t *testing.T) {
+	store := NewMemoryStore()
+	specs := make([][3]string, MaxLimit+1)
+	for i := range specs {
+		specs[i] = [3]string{"USD", "EUR", "linear"}
+	}
+	seedForecasts(t, store, specs...)
+
+	tests := []struct {
+		limit    int
+		expected int
+	}{
+		{0, DefaultLimit},
+		{10, 10},
+		{MaxLimit + 100, MaxLimit},
+	}
+	for _, tt := range tests {
+		page, _ := store.List(context.Background(), Filter{Limit: tt.limit})
+		if len(page.Forecasts) != tt.expected {
+			t.Errorf("Limit %d: expected %d forecasts, got %d", tt.limit, tt.expected, len(page.Forecasts))
+		}
+		if page.NextCursor == "" {
+			t.Errorf("Limit %d: expected a next cursor", tt.limit)
+		}
+	}
+}
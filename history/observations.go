@@ -0,0 +1,208 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Observation is one stored forecast period scored against the rate realized on its date
+type Observation struct {
+	ForecastID   string  `json:"forecast_id"`
+	Period       int     `json:"period"`
+	Pair         string  `json:"pair"` // BASE/TARGET
+	ForecastType string  `json:"forecast_type"`
+	Frequency    string  `json:"frequency"`
+	Date         string  `json:"date"` // YYYY-MM-DD whose closing rate the period was scored against
+	Forecast     float64 `json:"forecast"`
+	Actual       float64 `json:"actual"`
+}
+
+// ObservationLog persists accuracy observations so scores survive a restart
+type ObservationLog interface {
+	// Append stores observations
+	Append(ctx context.Context, observations []Observation) error
+	// Since returns the observations dated on or after a YYYY-MM-DD date, in the order they were appended
+	Since(ctx context.Context, date string) ([]Observation, error)
+	// Close releases the log's resources
+	Close() error
+}
+
+// discardObservationLog stores nothing, for services whose forecast history is kept only in memory
+type discardObservationLog struct{}
+
+// DiscardObservationLog returns a log that stores nothing
+func DiscardObservationLog() ObservationLog {
+	return discardObservationLog{}
+}
+
+// Append implements ObservationLog
+func (discardObservationLog) Append(ctx context.Context, observations []Observation) error {
+	return nil
+}
+
+// Since implements ObservationLog
+func (discardObservationLog) Since(ctx context.Context, date string) ([]Observation, error) {
+	return nil, nil
+}
+
+// Close implements ObservationLog
+func (discardObservationLog) Close() error {
+	return nil
+}
+
+// FileObservationLog keeps observations in an append-only JSON lines file, read back on demand
+type FileObservationLog struct {
+	path string
+
+	// mu serializes appends so each record is written as one line
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileObservationLog opens or creates the observation file at path, checking its records
+func OpenFileObservationLog(path string) (*FileObservationLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create observation directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open observation file: %w", err)
+	}
+
+	log := &FileObservationLog{path: path, file: file}
+	if _, err := log.read(true); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return log, nil
+}
+
+// read decodes every complete record from the start of the file and leaves it positioned for appending; when repair is
+// set, a partial last line left by a crash mid-write is truncated
+func (l *FileObservationLog) read(repair bool) ([]Observation, error) {
+	if _, err := l.file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek observation file: %w", err)
+	}
+	reader := bufio.NewReader(l.file)
+	var observations []Observation
+	var offset int64
+	for line := 1; ; line++ {
+		record, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(record) > 0 && repair {
+				if err := l.file.Truncate(offset); err != nil {
+					return nil, fmt.Errorf("failed to truncate partial observation record: %w", err)
+				}
+			}
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read observation file: %w", err)
+		}
+		offset += int64(len(record))
+
+		if record = bytes.TrimSpace(record); len(record) == 0 {
+			continue
+		}
+		var observation Observation
+		if err := json.Unmarshal(record, &observation); err != nil {
+			return nil, fmt.Errorf("invalid observation record at %s:%d: %w", l.path, line, err)
+		}
+		observations = append(observations, observation)
+	}
+
+	if _, err := l.file.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek observation file: %w", err)
+	}
+	return observations, nil
+}
+
+// Append implements ObservationLog; the records are synced to disk before Append returns
+func (l *FileObservationLog) Append(ctx context.Context, observations []Observation) error {
+	if len(observations) == 0 {
+		return nil
+	}
+	var records bytes.Buffer
+	for _, observation := range observations {
+		record, err := json.Marshal(observation)
+		if err != nil {
+			return fmt.Errorf("failed to encode observation: %w", err)
+		}
+		records.Write(record)
+		records.WriteByte('\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return errClosed
+	}
+	if _, err := l.file.Write(records.Bytes()); err != nil {
+		return fmt.Errorf("failed to write observation records: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync observation file: %w", err)
+	}
+	return nil
+}
+
+// Since implements ObservationLog
+func (l *FileObservationLog) Since(ctx context.Context, date string) ([]Observation, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil, errClosed
+	}
+	observations, err := l.read(false)
+	if err != nil {
+		return nil, err
+	}
+	return since(observations, date), nil
+}
+
+// Close implements ObservationLog
+func (l *FileObservationLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// since returns the observations dated on or after date
+func since(observations []Observation, date string) []Observation {
+	var result []Observation
+	for _, observation := range observations {
+		if observation.Date >= date {
+			result = append(result, observation)
+		}
+	}
+	return result
+}
+
+// ObservationPath returns the observation file kept next to a forecast history file, such as forecasts.accuracy.jsonl
+// for forecasts.jsonl
+func ObservationPath(historyPath string) string {
+	return strings.TrimSuffix(historyPath, filepath.Ext(historyPath)) + ".accuracy.jsonl"
+}
+
+// OpenObservationLog returns a FileObservationLog next to the forecast history file at historyPath, or a log that
+// stores nothing when historyPath is empty, as scores cannot outlive the forecasts they refer to
+func OpenObservationLog(historyPath string) (ObservationLog, error) {
+	if historyPath == "" {
+		return DiscardObservationLog(), nil
+	}
+	return OpenFileObservationLog(ObservationPath(historyPath))
+}
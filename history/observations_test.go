@@ -0,0 +1,71 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileObservationLog_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "forecasts.accuracy.jsonl")
+	ctx := context.Background()
+
+	log, err := OpenFileObservationLog(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := log.Append(ctx, []Observation{
+		{ForecastID: "a", Period: 1, Pair: "USD/EUR", Date: "2025-03-01", Forecast: 0.88, Actual: 0.8},
+		{ForecastID: "b", Period: 2, Pair: "USD/GBP", Date: "2025-03-05", Forecast: 0.7, Actual: 0.75},
+	}); err != nil {
+		t.Fatalf("Expected no error appending, got %v", err)
+	}
+	log.Close()
+
+	// A crash mid-write leaves a partial last line, which is dropped on open
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	file.WriteString(`{"forecast_id":"c","per`)
+	file.Close()
+
+	reopened, err := OpenFileObservationLog(path)
+	if err != nil {
+		t.Fatalf("Expected no error reopening, got %v", err)
+	}
+	defer reopened.Close()
+	reopened.Append(ctx, []Observation{{ForecastID: "d", Period: 1, Pair: "USD/EUR", Date: "2025-03-06"}})
+
+	observations, err := reopened.Since(ctx, "2025-03-02")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(observations) != 2 || observations[0].ForecastID != "b" || observations[0].Actual != 0.75 || observations[1].ForecastID != "d" {
+		t.Errorf("Expected observations b and d, got %+v", observations)
+	}
+}
+
+func TestFileObservationLog_Closed(t *testing.T) {
+	log, err := OpenFileObservationLog(filepath.Join(t.TempDir(), "forecasts.accuracy.jsonl"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	log.Close()
+	if err := log.Append(context.Background(), []Observation{{ForecastID: "a"}}); err == nil {
+		t.Error("Expected an error appending to a closed log")
+	}
+}
+
+func TestObservationPath(t *testing.T) {
+	tests := []struct {
+		historyPath string
+		expected    string
+	}{
+		{"/data/forecasts.jsonl", "/data/forecasts.accuracy.jsonl"},
+		{"/data/forecasts", "/data/forecasts.accuracy.jsonl"},
+	}
+	for _, tt := range tests {
+		if path := ObservationPath(tt.historyPath); path != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, path)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"io"
 
 	"github.com/sirupsen/logrus"
@@ -18,8 +19,14 @@ type Logger interface {
 	Errorf(format string, args ...interface{})
 	Fatal(args ...interface{})
 	Fatalf(format string, args ...interface{})
+	WithField(key string, value interface{}) Logger
+	WithFields(fields Fields) Logger
+	WithContext(ctx context.Context) Logger
 }
 
+// Fields is a set of structured key/value pairs attached to log lines
+type Fields map[string]interface{}
+
 // LogrusLogger implements the Logger interface using logrus
 type LogrusLogger struct {
 	*logrus.Logger
@@ -30,18 +37,7 @@ func New(level string) Logger {
 	logger := logrus.New()
 
 	// Set log level
-	switch level {
-	case "debug":
-		logger.SetLevel(logrus.DebugLevel)
-	case "info":
-		logger.SetLevel(logrus.InfoLevel)
-	case "warn":
-		logger.SetLevel(logrus.WarnLevel)
-	case "error":
-		logger.SetLevel(logrus.ErrorLevel)
-	default:
-		logger.SetLevel(logrus.InfoLevel)
-	}
+	logger.SetLevel(parseLevel(level))
 
 	// Set JSON formatter for structured logging
 	logger.SetFormatter(&logrus.JSONFormatter{
@@ -55,3 +51,80 @@ func New(level string) Logger {
 func (l *LogrusLogger) SetOutput(output io.Writer) {
 	l.Logger.SetOutput(output)
 }
+
+// SetLogLevel changes the level by name; loggers derived with WithField follow the change
+func (l *LogrusLogger) SetLogLevel(level string) {
+	l.Logger.SetLevel(parseLevel(level))
+}
+
+// parseLevel maps a level name to a logrus level, defaulting to info
+func parseLevel(level string) logrus.Level {
+	switch level {
+	case "debug":
+		return logrus.DebugLevel
+	case "warn":
+		return logrus.WarnLevel
+	case "error":
+		return logrus.ErrorLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+// WithField returns a logger that adds key=value to every line
+func (l *LogrusLogger) WithField(key string, value interface{}) Logger {
+	return &entryLogger{Entry: l.Logger.WithField(key, value)}
+}
+
+// WithFields returns a logger that adds fields to every line
+func (l *LogrusLogger) WithFields(fields Fields) Logger {
+	return &entryLogger{Entry: l.Logger.WithFields(logrus.Fields(fields))}
+}
+
+// WithContext returns a logger that adds the fields carried by ctx to every line
+func (l *LogrusLogger) WithContext(ctx context.Context) Logger {
+	return l.WithFields(FieldsFromContext(ctx))
+}
+
+// entryLogger implements the Logger interface on top of a logrus entry with preset fields
+type entryLogger struct {
+	*logrus.Entry
+}
+
+// WithField returns a logger that adds key=value to every line
+func (e *entryLogger) WithField(key string, value interface{}) Logger {
+	return &entryLogger{Entry: e.Entry.WithField(key, value)}
+}
+
+// WithFields returns a logger that adds fields to every line
+func (e *entryLogger) WithFields(fields Fields) Logger {
+	return &entryLogger{Entry: e.Entry.WithFields(logrus.Fields(fields))}
+}
+
+// WithContext returns a logger that adds the fields carried by ctx to every line
+func (e *entryLogger) WithContext(ctx context.Context) Logger {
+	return e.WithFields(FieldsFromContext(ctx))
+}
+
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying fields merged over any fields already in ctx
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	merged := make(Fields)
+	for key, value := range FieldsFromContext(ctx) {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext returns the log fields carried by ctx
+func FieldsFromContext(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(Fields)
+	return fields
+}
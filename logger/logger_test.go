@@ -2,6 +2,7 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -194,3 +195,74 @@ func TestLogrusLogger_MultipleMessages(t *testing.T) {
 		}
 	}
 }
+
+func TestLogrusLogger_WithFields(t *testing.T) {
+	logger := New("debug")
+	logrusLogger := logger.(*LogrusLogger)
+
+	var buf bytes.Buffer
+	logrusLogger.SetOutput(&buf)
+
+	logger.WithField("request_id", "abc").WithFields(Fields{"route": "/api/v1/forecast"}).Info("with fields")
+	logger.Info("without fields")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], `"request_id":"abc"`) || !strings.Contains(lines[0], `"route":"/api/v1/forecast"`) {
+		t.Errorf("Expected fields in first entry, got: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Errorf("Expected base logger to stay unchanged, got: %s", lines[1])
+	}
+}
+
+func TestLogrusLogger_WithContext(t *testing.T) {
+	logger := New("debug")
+	logrusLogger := logger.(*LogrusLogger)
+
+	var buf bytes.Buffer
+	logrusLogger.SetOutput(&buf)
+
+	ctx := ContextWithFields(context.Background(), Fields{"request_id": "abc", "route": "/old"})
+	ctx = ContextWithFields(ctx, Fields{"route": "/new"})
+	logger.WithContext(ctx).Info("context message")
+
+	output := buf.String()
+	for _, expected := range []string{`"request_id":"abc"`, `"route":"/new"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %s, got: %s", expected, output)
+		}
+	}
+
+	buf.Reset()
+	logger.WithContext(context.Background()).Info("empty context")
+	if strings.Contains(buf.String(), "request_id") {
+		t.Errorf("Expected no context fields, got: %s", buf.String())
+	}
+}
+
+func TestLogrusLogger_SetLogLevel(t *testing.T) {
+	logger := New("info")
+	logrusLogger := logger.(*LogrusLogger)
+
+	var buf bytes.Buffer
+	logrusLogger.SetOutput(&buf)
+	derived := logger.WithField("request_id", "abc")
+
+	derived.Debug("hidden")
+	logrusLogger.SetLogLevel("debug")
+	derived.Debug("shown")
+
+	output := buf.String()
+	if strings.Contains(output, "hidden") {
+		t.Errorf("Expected debug message to be filtered before the level change, got: %s", output)
+	}
+	if !strings.Contains(output, "shown") {
+		t.Errorf("Expected derived logger to follow the level change, got: %s", output)
+	}
+	if logrusLogger.GetLevel() != logrus.DebugLevel {
+		t.Errorf("Expected level debug, got %v", logrusLogger.GetLevel())
+	}
+}
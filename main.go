@@ -2,22 +2,50 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Embed the timezone database so request timezones resolve on hosts without one
 
 	"github.com/dalfonso89/financial-forecasting-service/api"
 	"github.com/dalfonso89/financial-forecasting-service/config"
+	"github.com/dalfonso89/financial-forecasting-service/curve"
+	"github.com/dalfonso89/financial-forecasting-service/history"
 	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
 	"github.com/dalfonso89/financial-forecasting-service/service"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
 )
 
 func main() {
+	checkConfig := flag.Bool("check-config", false, "validate the configuration, print it with secrets redacted and exit")
+	configFile := flag.String("config", "", "YAML or TOML config file (overrides CONFIG_FILE)")
+	port := flag.String("port", "", "port to listen on (overrides PORT)")
+	logLevel := flag.String("log-level", "", "logging level (overrides LOG_LEVEL)")
+	overrides := overrideFlag{}
+	flag.Var(overrides, "set", "set a configuration variable as KEY=VALUE; may be repeated")
+	flag.Parse()
+
+	if *port != "" {
+		overrides["PORT"] = *port
+	}
+	if *logLevel != "" {
+		overrides["LOG_LEVEL"] = *logLevel
+	}
+
 	// Load configuration
-	cfg, err := config.Load()
+	loadOptions := config.Options{ConfigFile: *configFile, Overrides: overrides}
+	cfg, err := config.LoadWithOptions(loadOptions)
+	if *checkConfig {
+		os.Exit(runConfigCheck(cfg, err))
+	}
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
@@ -27,8 +55,74 @@ func main() {
 	logrusLogger := loggerInstance.(*logger.LogrusLogger)
 	logrusLogger.SetOutput(os.Stdout)
 
+	// Initialize tracing; spans are only exported when a collector is configured
+	if cfg.TracingOTLPEndpoint != "" {
+		tracing.SetDefault(tracing.NewTracer(tracing.NewOTLPExporter(tracing.OTLPExporterConfig{
+			Endpoint:    cfg.TracingOTLPEndpoint,
+			ServiceName: cfg.TracingServiceName,
+		})))
+		loggerInstance.Infof("Exporting traces to %s", cfg.TracingOTLPEndpoint)
+	}
+
+	// Open the forecast history store
+	historyStore, err := history.Open(cfg.ForecastHistoryPath, cfg.ForecastHistoryMemoryLimit)
+	if err != nil {
+		loggerInstance.Fatalf("Failed to open forecast history: %v", err)
+	}
+
+	// Open the accuracy observation log kept next to the forecast history
+	accuracyLog, err := history.OpenObservationLog(cfg.ForecastHistoryPath)
+	if err != nil {
+		loggerInstance.Fatalf("Failed to open forecast accuracy log: %v", err)
+	}
+
+	// Open the rate history store
+	rateHistoryStore, err := ratehistory.Open(cfg.RateHistoryPath)
+	if err != nil {
+		loggerInstance.Fatalf("Failed to open rate history: %v", err)
+	}
+
+	// Load the interest rate curves forward rates are priced from
+	curves, err := curve.Load(cfg.InterestRateCurvesPath)
+	if err != nil {
+		loggerInstance.Fatalf("Failed to load interest rate curves: %v", err)
+	}
+
 	// Initialize services
-	forecastingService := service.NewForecastingService(cfg, loggerInstance)
+	forecastingService := service.NewForecastingServiceWithHistory(cfg, loggerInstance, historyStore)
+	forecastingService.SetRateHistory(rateHistoryStore)
+	forecastingService.SetInterestRateCurves(curves)
+	if err := forecastingService.SetAccuracyLog(context.Background(), accuracyLog); err != nil {
+		loggerInstance.Fatalf("Failed to restore forecast accuracy: %v", err)
+	}
+
+	// Reload the config file on SIGHUP or when it changes, swapping in the settings that are safe to change live
+	reloader := config.NewReloader(config.ReloaderConfig{
+		Initial: cfg,
+		Load:    func() (*config.Config, error) { return config.LoadWithOptions(loadOptions) },
+		Logger:  loggerInstance,
+	})
+	reloader.Subscribe(func(event config.ReloadEvent) {
+		if event.Err != nil {
+			return
+		}
+		logrusLogger.SetLogLevel(event.Config.LogLevel)
+		forecastingService.UpdateConfig(event.Config)
+		if curves, err := curve.Load(event.Config.InterestRateCurvesPath); err != nil {
+			loggerInstance.Errorf("Keeping the current interest rate curves: %v", err)
+		} else {
+			forecastingService.SetInterestRateCurves(curves)
+		}
+	})
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go reloader.WatchFile(backgroundCtx)
+
+	// Score stored forecasts against realized rates in the background
+	go forecastingService.RunAccuracyTracking(backgroundCtx, cfg.AccuracyCheckInterval)
+
+	// Record daily rate snapshots for volatility and correlation estimates
+	go forecastingService.RunRateSnapshots(backgroundCtx, cfg.RateSnapshotInterval)
 
 	// Initialize HTTP handlers
 	handlerConfig := api.HandlerConfig{
@@ -58,17 +152,26 @@ func main() {
 		}
 	}()
 
-	// Wait for interrupt signal or server error
+	// Wait for interrupt signal or server error, reloading configuration on SIGHUP
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
 
-	select {
-	case sig := <-quit:
-		loggerInstance.Infof("Received signal: %v", sig)
-	case err := <-serverErr:
-		loggerInstance.Errorf("Server error: %v", err)
-		os.Exit(1)
+wait:
+	for {
+		select {
+		case <-hangup:
+			reloader.Reload(config.ReloadTriggerSignal)
+		case sig := <-quit:
+			loggerInstance.Infof("Received signal: %v", sig)
+			break wait
+		case err := <-serverErr:
+			loggerInstance.Errorf("Server error: %v", err)
+			os.Exit(1)
+		}
 	}
+	stopBackground()
 
 	loggerInstance.Info("Shutting down server...")
 
@@ -86,5 +189,57 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := tracing.Default().Shutdown(shutdownCtx); err != nil {
+		loggerInstance.Warnf("Tracing shutdown error: %v", err)
+	}
+
+	if err := historyStore.Close(); err != nil {
+		loggerInstance.Warnf("Forecast history close error: %v", err)
+	}
+
+	if err := accuracyLog.Close(); err != nil {
+		loggerInstance.Warnf("Forecast accuracy log close error: %v", err)
+	}
+
+	if err := rateHistoryStore.Close(); err != nil {
+		loggerInstance.Warnf("Rate history close error: %v", err)
+	}
+
 	loggerInstance.Info("Server stopped gracefully")
 }
+
+// runConfigCheck prints the resolved configuration or every validation error and returns the exit code
+func runConfigCheck(cfg *config.Config, err error) int {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, setting := range cfg.Redacted() {
+		fmt.Printf("%s=%s\n", setting.Name, setting.Value)
+	}
+	fmt.Println("configuration OK")
+	return 0
+}
+
+// overrideFlag collects repeated KEY=VALUE flags into configuration overrides
+type overrideFlag map[string]string
+
+// String implements flag.Value
+func (f overrideFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for key, value := range f {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value
+func (f overrideFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	f[strings.TrimSpace(key)] = val
+	return nil
+}
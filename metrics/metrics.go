@@ -0,0 +1,94 @@
+package metrics
+
+import "net/http"
+
+// Default is the registry served on /metrics
+var Default = NewRegistry()
+
+var (
+	// HTTPRequestDuration observes HTTP request latency by method, route and status
+	HTTPRequestDuration = NewHistogramVec("http_request_duration_seconds",
+		"HTTP request latency in seconds by method, route and status.", DefaultBuckets, "method", "route", "status")
+
+	// HTTPRequestsInFlight tracks requests currently being served
+	HTTPRequestsInFlight = NewGauge("http_requests_in_flight",
+		"Number of HTTP requests currently being served.")
+
+	// ForecastDuration observes forecast model computation latency by forecast type
+	ForecastDuration = NewHistogramVec("forecast_computation_duration_seconds",
+		"Forecast computation latency in seconds by forecast type.",
+		[]float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1}, "forecast_type")
+
+	// ForecastCacheHits counts forecasts served from the cache
+	ForecastCacheHits = NewCounter("forecast_cache_hits_total",
+		"Number of forecasts served from the cache.")
+
+	// ForecastCacheMisses counts forecasts that had to be computed
+	ForecastCacheMisses = NewCounter("forecast_cache_misses_total",
+		"Number of forecast cache lookups that missed.")
+
+	// ForecastCacheHitRatio reports hits / (hits + misses) since start
+	ForecastCacheHitRatio = NewGaugeFunc("forecast_cache_hit_ratio",
+		"Ratio of forecast cache hits to lookups since start.", cacheHitRatio)
+
+	// UpstreamRequestDuration observes currency exchange service latency by operation
+	UpstreamRequestDuration = NewHistogramVec("currency_client_request_duration_seconds",
+		"Currency exchange service request latency in seconds by operation.", DefaultBuckets, "operation")
+
+	// UpstreamErrors counts failed currency exchange service calls by operation and reason
+	UpstreamErrors = NewCounterVec("currency_client_errors_total",
+		"Failed currency exchange service requests by operation and reason.", "operation", "reason")
+
+	// ForecastHistoryWriteErrors counts forecasts that could not be recorded in the history store
+	ForecastHistoryWriteErrors = NewCounter("forecast_history_write_errors_total",
+		"Number of forecasts that could not be recorded in the history store.")
+
+	// RateHistoryWriteErrors counts rate snapshots that could not be recorded in the rate history store
+	RateHistoryWriteErrors = NewCounter("rate_history_write_errors_total",
+		"Number of rate snapshots that could not be recorded in the rate history store.")
+
+	// ForecastAccuracyObservations counts forecast periods scored against realized rates
+	ForecastAccuracyObservations = NewCounter("forecast_accuracy_observations_total",
+		"Number of forecast periods compared with the realized rate.")
+
+	// ConfigReloads counts configuration reload attempts by result
+	ConfigReloads = NewCounterVec("config_reloads_total",
+		"Configuration reload attempts by result.", "result")
+
+	// ConfigLastReloadSuccess records when the configuration was last reloaded successfully
+	ConfigLastReloadSuccess = NewGauge("config_last_reload_success_timestamp_seconds",
+		"Unix time of the last successful configuration reload.")
+)
+
+func init() {
+	Default.MustRegister(
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		ForecastDuration,
+		ForecastCacheHits,
+		ForecastCacheMisses,
+		ForecastCacheHitRatio,
+		UpstreamRequestDuration,
+		UpstreamErrors,
+		ForecastHistoryWriteErrors,
+		RateHistoryWriteErrors,
+		ForecastAccuracyObservations,
+		ConfigReloads,
+		ConfigLastReloadSuccess,
+	)
+}
+
+// Handler serves the default registry
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// cacheHitRatio computes the forecast cache hit ratio
+func cacheHitRatio() float64 {
+	hits := ForecastCacheHits.Value()
+	lookups := hits + ForecastCacheMisses.Value()
+	if lookups == 0 {
+		return 0
+	}
+	return hits / lookups
+}
@@ -0,0 +1,385 @@
+// Package metrics implements a small Prometheus-compatible metrics registry
+// that renders the text exposition format without external dependencies.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// contentType is the Prometheus text exposition format media type
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are latency buckets in seconds suitable for HTTP and upstream calls
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Collector is a metric family that can render itself in the text format
+type Collector interface {
+	Write(w io.Writer) error
+}
+
+// Registry holds registered collectors
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// MustRegister adds collectors to the registry
+func (r *Registry) MustRegister(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// Write renders every registered collector in registration order
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, collector := range r.collectors {
+		if err := collector.Write(buffered); err != nil {
+			return err
+		}
+	}
+	return buffered.Flush()
+}
+
+// Handler serves the registry in the Prometheus text format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_ = r.Write(w)
+	})
+}
+
+// atomicFloat is a float64 updated with compare-and-swap
+type atomicFloat struct {
+	bits uint64
+}
+
+func (f *atomicFloat) add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&f.bits)
+		updated := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&f.bits, old, updated) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) set(value float64) {
+	atomic.StoreUint64(&f.bits, math.Float64bits(value))
+}
+
+func (f *atomicFloat) load() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&f.bits))
+}
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	name  string
+	help  string
+	value atomicFloat
+}
+
+// NewCounter creates an unlabeled counter
+func NewCounter(name, help string) *Counter {
+	return &Counter{name: name, help: help}
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc() {
+	c.value.add(1)
+}
+
+// Add increments the counter by a non-negative delta
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+	c.value.add(delta)
+}
+
+// Value returns the current counter value
+func (c *Counter) Value() float64 {
+	return c.value.load()
+}
+
+// Write implements Collector
+func (c *Counter) Write(w io.Writer) error {
+	writeHeader(w, c.name, c.help, "counter")
+	_, err := fmt.Fprintf(w, "%s %s\n", c.name, formatFloat(c.Value()))
+	return err
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	name  string
+	help  string
+	value atomicFloat
+}
+
+// NewGauge creates an unlabeled gauge
+func NewGauge(name, help string) *Gauge {
+	return &Gauge{name: name, help: help}
+}
+
+// Inc increments the gauge by one
+func (g *Gauge) Inc() {
+	g.value.add(1)
+}
+
+// Dec decrements the gauge by one
+func (g *Gauge) Dec() {
+	g.value.add(-1)
+}
+
+// Set sets the gauge to a value
+func (g *Gauge) Set(value float64) {
+	g.value.set(value)
+}
+
+// Value returns the current gauge value
+func (g *Gauge) Value() float64 {
+	return g.value.load()
+}
+
+// Write implements Collector
+func (g *Gauge) Write(w io.Writer) error {
+	writeHeader(w, g.name, g.help, "gauge")
+	_, err := fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.Value()))
+	return err
+}
+
+// GaugeFunc is a gauge whose value is computed at scrape time
+type GaugeFunc struct {
+	name string
+	help string
+	fn   func() float64
+}
+
+// NewGaugeFunc creates a gauge backed by a function
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	return &GaugeFunc{name: name, help: help, fn: fn}
+}
+
+// Write implements Collector
+func (g *GaugeFunc) Write(w io.Writer) error {
+	writeHeader(w, g.name, g.help, "gauge")
+	_, err := fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.fn()))
+	return err
+}
+
+// labeledSeries keeps the children of a vector keyed by their label values
+type labeledSeries[T any] struct {
+	labelNames []string
+	mu         sync.RWMutex
+	children   map[string]*T
+	values     map[string][]string
+	newChild   func() *T
+}
+
+func newLabeledSeries[T any](labelNames []string, newChild func() *T) labeledSeries[T] {
+	return labeledSeries[T]{
+		labelNames: labelNames,
+		children:   make(map[string]*T),
+		values:     make(map[string][]string),
+		newChild:   newChild,
+	}
+}
+
+// get returns the child for the label values, creating it on first use
+func (s *labeledSeries[T]) get(labelValues []string) *T {
+	if len(labelValues) != len(s.labelNames) {
+		panic(fmt.Sprintf("metrics: expected %d label values, got %d", len(s.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+
+	s.mu.RLock()
+	child, exists := s.children[key]
+	s.mu.RUnlock()
+	if exists {
+		return child
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if child, exists = s.children[key]; !exists {
+		child = s.newChild()
+		s.children[key] = child
+		s.values[key] = append([]string(nil), labelValues...)
+	}
+	return child
+}
+
+// each visits children sorted by label values for deterministic output
+func (s *labeledSeries[T]) each(visit func(labels string, child *T) error) error {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.children))
+	for key := range s.children {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s.mu.RLock()
+		child, values := s.children[key], s.values[key]
+		s.mu.RUnlock()
+		if err := visit(formatLabels(s.labelNames, values), child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CounterVec is a counter partitioned by labels
+type CounterVec struct {
+	name   string
+	help   string
+	series labeledSeries[Counter]
+}
+
+// NewCounterVec creates a labeled counter
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		name:   name,
+		help:   help,
+		series: newLabeledSeries(labelNames, func() *Counter { return &Counter{} }),
+	}
+}
+
+// WithLabelValues returns the counter for the given label values
+func (v *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	return v.series.get(labelValues)
+}
+
+// Write implements Collector
+func (v *CounterVec) Write(w io.Writer) error {
+	writeHeader(w, v.name, v.help, "counter")
+	return v.series.each(func(labels string, counter *Counter) error {
+		_, err := fmt.Fprintf(w, "%s%s %s\n", v.name, labels, formatFloat(counter.Value()))
+		return err
+	})
+}
+
+// Histogram samples observations into cumulative buckets
+type Histogram struct {
+	upperBounds []float64
+	counts      []uint64
+	count       uint64
+	sum         atomicFloat
+}
+
+// Observe records a single observation
+func (h *Histogram) Observe(value float64) {
+	for i, bound := range h.upperBounds {
+		if value <= bound {
+			atomic.AddUint64(&h.counts[i], 1)
+		}
+	}
+	atomic.AddUint64(&h.count, 1)
+	h.sum.add(value)
+}
+
+// Count returns the number of observations
+func (h *Histogram) Count() uint64 {
+	return atomic.LoadUint64(&h.count)
+}
+
+// HistogramVec is a histogram partitioned by labels
+type HistogramVec struct {
+	name   string
+	help   string
+	series labeledSeries[Histogram]
+}
+
+// NewHistogramVec creates a labeled histogram with the given bucket upper bounds
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	upperBounds := append([]float64(nil), buckets...)
+	sort.Float64s(upperBounds)
+
+	return &HistogramVec{
+		name: name,
+		help: help,
+		series: newLabeledSeries(labelNames, func() *Histogram {
+			return &Histogram{upperBounds: upperBounds, counts: make([]uint64, len(upperBounds))}
+		}),
+	}
+}
+
+// WithLabelValues returns the histogram for the given label values
+func (v *HistogramVec) WithLabelValues(labelValues ...string) *Histogram {
+	return v.series.get(labelValues)
+}
+
+// Write implements Collector
+func (v *HistogramVec) Write(w io.Writer) error {
+	writeHeader(w, v.name, v.help, "histogram")
+	return v.series.each(func(labels string, histogram *Histogram) error {
+		for i, bound := range histogram.upperBounds {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, withLabel(labels, "le", formatFloat(bound)), atomic.LoadUint64(&histogram.counts[i]))
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, withLabel(labels, "le", "+Inf"), histogram.Count())
+		fmt.Fprintf(w, "%s_sum%s %s\n", v.name, labels, formatFloat(histogram.sum.load()))
+		_, err := fmt.Fprintf(w, "%s_count%s %d\n", v.name, labels, histogram.Count())
+		return err
+	})
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric family
+func writeHeader(w io.Writer, name, help, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+// formatLabels renders a label set such as {route="/health",status="200"}
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + labelValueEscaper.Replace(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// withLabel appends one more label to a rendered label set
+func withLabel(labels, name, value string) string {
+	pair := name + `="` + labelValueEscaper.Replace(value) + `"`
+	if labels == "" {
+		return "{" + pair + "}"
+	}
+	return labels[:len(labels)-1] + "," + pair + "}"
+}
+
+// labelValueEscaper escapes label values as required by the text format
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatFloat renders a sample value
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_TextFormat(t *testing.T) {
+	registry := NewRegistry()
+	counter := NewCounter("test_events_total", "Events seen.")
+	gauge := NewGauge("test_in_flight", "In-flight work.")
+	errorsByReason := NewCounterVec("test_errors_total", "Errors by reason.", "reason")
+	latency := NewHistogramVec("test_latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+	registry.MustRegister(counter, gauge, errorsByReason, latency)
+
+	counter.Add(2)
+	gauge.Inc()
+	gauge.Inc()
+	gauge.Dec()
+	errorsByReason.WithLabelValues("timeout").Inc()
+	errorsByReason.WithLabelValues(`say "hi"`).Inc()
+	latency.WithLabelValues("/health").Observe(0.05)
+	latency.WithLabelValues("/health").Observe(0.5)
+	latency.WithLabelValues("/health").Observe(5)
+
+	var buffer bytes.Buffer
+	if err := registry.Write(&buffer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	output := buffer.String()
+
+	expectedLines := []string{
+		"# HELP test_events_total Events seen.",
+		"# TYPE test_events_total counter",
+		"test_events_total 2",
+		"# TYPE test_in_flight gauge",
+		"test_in_flight 1",
+		`test_errors_total{reason="timeout"} 1`,
+		`test_errors_total{reason="say \"hi\""} 1`,
+		"# TYPE test_latency_seconds histogram",
+		`test_latency_seconds_bucket{route="/health",le="0.1"} 1`,
+		`test_latency_seconds_bucket{route="/health",le="1"} 2`,
+		`test_latency_seconds_bucket{route="/health",le="+Inf"} 3`,
+		`test_latency_seconds_sum{route="/health"} 5.55`,
+		`test_latency_seconds_count{route="/health"} 3`,
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister(NewGaugeFunc("test_ratio", "Ratio.", func() float64 { return 0.25 }))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	registry.Handler().ServeHTTP(w, req)
+
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Expected Prometheus content type, got %s", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "test_ratio 0.25\n") {
+		t.Errorf("Expected gauge func value, got %s", w.Body.String())
+	}
+}
+
+func TestCacheHitRatio(t *testing.T) {
+	hits, misses := ForecastCacheHits.Value(), ForecastCacheMisses.Value()
+	ForecastCacheHits.Add(3)
+	ForecastCacheMisses.Inc()
+
+	expected := (hits + 3) / (hits + misses + 4)
+	if ratio := cacheHitRatio(); ratio != expected {
+		t.Errorf("Expected ratio %v, got %v", expected, ratio)
+	}
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig holds the cross-origin resource sharing policy
+type CORSConfig struct {
+	// AllowedOrigins lists exact origins ("https://app.example.com"),
+	// wildcard subdomains ("https://*.example.com") or "*" for any origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// DefaultCORSConfig returns a permissive policy without credentials
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization", "X-Request-ID"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// CORS creates a Gin middleware enforcing the given CORS policy.
+// Requests without an Origin header are passed through untouched.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	defaults := DefaultCORSConfig()
+	if len(cfg.AllowedMethods) == 0 {
+		cfg.AllowedMethods = defaults.AllowedMethods
+	}
+	if len(cfg.AllowedHeaders) == 0 {
+		cfg.AllowedHeaders = defaults.AllowedHeaders
+	}
+
+	// A wildcard never admits credentialed requests, so with credentials only listed origins are echoed
+	allowAnyOrigin := false
+	allowedOrigins := make([]string, 0, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		switch {
+		case origin != "*":
+			allowedOrigins = append(allowedOrigins, origin)
+		case !cfg.AllowCredentials:
+			allowAnyOrigin = true
+			allowedOrigins = append(allowedOrigins, origin)
+		}
+	}
+
+	allowedMethods := strings.Join(normalizeTokens(cfg.AllowedMethods, strings.ToUpper), ", ")
+	allowedHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !isOriginAllowed(origin, allowedOrigins) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if allowAnyOrigin {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposedHeaders != "" {
+				c.Header("Access-Control-Expose-Headers", exposedHeaders)
+			}
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		requestedMethod := strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))
+		if !containsToken(cfg.AllowedMethods, requestedMethod) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		for _, header := range strings.Split(c.GetHeader("Access-Control-Request-Headers"), ",") {
+			if header = strings.TrimSpace(header); header != "" && !containsToken(cfg.AllowedHeaders, header) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		}
+
+		c.Header("Access-Control-Allow-Methods", allowedMethods)
+		c.Header("Access-Control-Allow-Headers", allowedHeaders)
+		if cfg.MaxAge > 0 {
+			c.Header("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// isOriginAllowed checks an origin against exact and wildcard subdomain patterns
+func isOriginAllowed(origin string, allowedOrigins []string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range allowedOrigins {
+		allowed = strings.ToLower(allowed)
+		switch {
+		case allowed == "*", allowed == origin:
+			return true
+		case strings.Contains(allowed, "://*."):
+			wildcard := strings.Index(allowed, "*")
+			prefix, suffix := allowed[:wildcard], allowed[wildcard+1:]
+			if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsToken performs a case-insensitive lookup of a token in a list
+func containsToken(tokens []string, token string) bool {
+	for _, candidate := range tokens {
+		if strings.EqualFold(strings.TrimSpace(candidate), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTokens trims and transforms every token in a list
+func normalizeTokens(tokens []string, transform func(string) string) []string {
+	result := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, transform(strings.TrimSpace(token)))
+	}
+	return result
+}
@@ -6,12 +6,14 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/uuid"
 )
 
 // RequestLogger creates a Gin middleware for request logging
 func RequestLogger(logger logger.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logger.Infof("HTTP Request - %s %s %d %v %s %s %s",
+		// param.Request is read after the handler chain, so it carries the request-scoped log fields
+		logger.WithContext(param.Request.Context()).Infof("HTTP Request - %s %s %d %v %s %s %s",
 			param.Method,
 			param.Path,
 			param.StatusCode,
@@ -35,30 +37,45 @@ func SecurityHeaders() gin.HandlerFunc {
 	}
 }
 
-// RequestID adds a unique request ID to each request
+// maxRequestIDLength bounds inbound X-Request-ID values so they stay safe to log and echo
+const maxRequestIDLength = 128
+
+// RequestID adds a unique request ID to each request, keeping a valid inbound X-Request-ID
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
+		if !isValidRequestID(requestID) {
 			requestID = generateRequestID()
 		}
 		c.Header("X-Request-ID", requestID)
 		c.Set("request_id", requestID)
+
+		fields := logger.Fields{"request_id": requestID}
+		if route := c.FullPath(); route != "" {
+			fields["route"] = route
+		}
+		c.Request = c.Request.WithContext(logger.ContextWithFields(c.Request.Context(), fields))
 		c.Next()
 	}
 }
 
-// generateRequestID generates a simple request ID
-func generateRequestID() string {
-	return time.Now().Format("20060102150405") + "-" + randomString(8)
+// isValidRequestID reports whether an inbound request ID is non-empty, bounded and limited to [A-Za-z0-9._:-]
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		switch char := requestID[i]; {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+		case char == '-', char == '_', char == '.', char == ':':
+		default:
+			return false
+		}
+	}
+	return true
 }
 
-// randomString generates a random string of specified length
-func randomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = charset[time.Now().UnixNano()%int64(len(charset))]
-	}
-	return string(b)
+// generateRequestID generates a time-ordered UUIDv7 request ID from crypto/rand
+func generateRequestID() string {
+	return uuid.NewV7(time.Now())
 }
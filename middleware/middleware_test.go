@@ -3,11 +3,15 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
 	"github.com/gin-gonic/gin"
 )
 
@@ -113,41 +117,73 @@ func TestGenerateRequestID(t *testing.T) {
 		t.Error("Expected request ID to be generated")
 	}
 
-	// Check format: should be timestamp-8chars
-	parts := strings.Split(requestID, "-")
-	if len(parts) != 2 {
-		t.Errorf("Expected request ID format 'timestamp-random', got: %s", requestID)
+	// Check format: should be a canonical UUIDv7
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if !uuidPattern.MatchString(requestID) {
+		t.Errorf("Expected UUIDv7 request ID, got: %s", requestID)
 	}
+}
 
-	if len(parts[1]) != 8 {
-		t.Errorf("Expected random part to be 8 characters, got: %d", len(parts[1]))
+func TestGenerateRequestID_Unique(t *testing.T) {
+	const count = 1000
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]bool, count)
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			requestID := generateRequestID()
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[requestID] {
+				t.Errorf("Expected unique request IDs, got duplicate %s", requestID)
+			}
+			seen[requestID] = true
+		}()
 	}
+	wg.Wait()
 }
 
-func TestRandomString(t *testing.T) {
-	tests := []int{1, 5, 10, 20}
+func TestRequestID_InvalidHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		preserved bool
+	}{
+		{"uuid", "0190b3e5-7f1a-7c3e-9d2b-3a4f5e6d7c8b", true},
+		{"allowed punctuation", "svc.a:req_1-2", true},
+		{"max length", strings.Repeat("a", maxRequestIDLength), true},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+		{"whitespace", "bad id", false},
+		{"log injection", "id\"}{\"admin\":true", false},
+		{"non-ascii", "idé", false},
+	}
 
-	for _, length := range tests {
-		result := randomString(length)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(204)
+	})
 
-		if len(result) != length {
-			t.Errorf("Expected length %d, got %d", length, len(result))
-		}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("X-Request-ID", tt.requestID)
+			router.ServeHTTP(w, req)
 
-		// Check that it only contains valid characters
-		validChars := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-		for _, char := range result {
-			if !strings.ContainsRune(validChars, char) {
-				t.Errorf("Expected only valid characters, got: %c", char)
+			got := w.Header().Get("X-Request-ID")
+			if tt.preserved && got != tt.requestID {
+				t.Errorf("Expected request ID %q to be preserved, got %q", tt.requestID, got)
 			}
-		}
-	}
-}
-
-func TestRandomString_ZeroLength(t *testing.T) {
-	result := randomString(0)
-	if result != "" {
-		t.Errorf("Expected empty string for length 0, got: %s", result)
+			if !tt.preserved && (got == tt.requestID || !isValidRequestID(got)) {
+				t.Errorf("Expected invalid request ID to be replaced, got %q", got)
+			}
+		})
 	}
 }
 
@@ -202,3 +238,230 @@ func TestRequestID_Uniqueness(t *testing.T) {
 		time.Sleep(1 * time.Millisecond)
 	}
 }
+
+func TestCORS_WildcardWithCredentials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(CORSConfig{
+		AllowedOrigins:   []string{"*", "https://app.example.com"},
+		AllowCredentials: true,
+	}))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "test"})
+	})
+
+	tests := []struct {
+		name           string
+		origin         string
+		expectedOrigin string
+	}{
+		{"listed origin", "https://app.example.com", "https://app.example.com"},
+		{"any other origin", "https://evil.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("Origin", tt.origin)
+			router.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.expectedOrigin {
+				t.Errorf("Expected allow origin %q, got %q", tt.expectedOrigin, got)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); tt.expectedOrigin == "" && got != "" {
+				t.Errorf("Expected no credentials header for an unlisted origin, got %q", got)
+			}
+		})
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{"GET", "POST", "PATCH"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowCredentials: true,
+		MaxAge:           5 * time.Minute,
+	}))
+	router.PATCH("/test", func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "test"})
+	})
+
+	tests := []struct {
+		name           string
+		origin         string
+		method         string
+		headers        string
+		expectedStatus int
+		expectedOrigin string
+	}{
+		{"exact origin", "https://app.example.com", "PATCH", "content-type", http.StatusNoContent, "https://app.example.com"},
+		{"wildcard subdomain", "https://api.example.org", "POST", "", http.StatusNoContent, "https://api.example.org"},
+		{"wildcard does not match apex", "https://example.org", "POST", "", http.StatusForbidden, ""},
+		{"unknown origin", "https://evil.com", "GET", "", http.StatusForbidden, ""},
+		{"method not allowed", "https://app.example.com", "DELETE", "", http.StatusForbidden, "https://app.example.com"},
+		{"header not allowed", "https://app.example.com", "POST", "X-Custom", http.StatusForbidden, "https://app.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("OPTIONS", "/test", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", tt.method)
+			if tt.headers != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.headers)
+			}
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.expectedOrigin {
+				t.Errorf("Expected allow origin %q, got %q", tt.expectedOrigin, got)
+			}
+			if tt.expectedStatus == http.StatusNoContent {
+				if got := w.Header().Get("Access-Control-Max-Age"); got != "300" {
+					t.Errorf("Expected max age 300, got %s", got)
+				}
+				if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+					t.Errorf("Expected credentials header, got %s", got)
+				}
+			}
+		})
+	}
+}
+
+func TestCORS_SimpleRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(DefaultCORSConfig()))
+	router.Handle("PATCH", "/test", func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "test"})
+	})
+
+	// Non-CORS requests pass through without CORS headers
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/test", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no CORS headers without Origin, got %s", got)
+	}
+
+	// Wildcard policy answers cross-origin requests with "*"
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PATCH", "/test", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected wildcard origin, got %s", got)
+	}
+	if got := w.Header().Get("Access-Control-Expose-Headers"); got != "X-Request-ID" {
+		t.Errorf("Expected X-Request-ID to be exposed, got %s", got)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/metrics-test/:id", func(c *gin.Context) {
+		c.JSON(201, gin.H{"message": "test"})
+	})
+
+	histogram := metrics.HTTPRequestDuration.WithLabelValues("GET", "/metrics-test/:id", "201")
+	before := histogram.Count()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics-test/42", nil)
+	router.ServeHTTP(w, req)
+
+	if histogram.Count() != before+1 {
+		t.Errorf("Expected request to be observed under its route template, got %d observations", histogram.Count()-before)
+	}
+	if metrics.HTTPRequestsInFlight.Value() != 0 {
+		t.Errorf("Expected no in-flight requests after completion, got %v", metrics.HTTPRequestsInFlight.Value())
+	}
+}
+
+func TestTracing(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	previous := tracing.Default()
+	tracing.SetDefault(tracing.NewTracer(exporter))
+	defer tracing.SetDefault(previous)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), Tracing())
+	router.GET("/trace-test/:id", func(c *gin.Context) {
+		outbound := http.Header{}
+		tracing.Inject(c.Request.Context(), outbound)
+		c.JSON(500, gin.H{"traceparent": outbound.Get(tracing.TraceparentHeader)})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/trace-test/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), "00-4bf92f3577b34da6a3ce929d0e0e4736-") {
+		t.Errorf("Expected handler context to carry the inbound trace ID, got %s", w.Body.String())
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "GET /trace-test/:id" {
+		t.Errorf("Expected span name 'GET /trace-test/:id', got %s", span.Name)
+	}
+	if span.Kind != tracing.SpanKindServer {
+		t.Errorf("Expected server span, got kind %d", span.Kind)
+	}
+	if span.ParentSpanID.String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected parent span 00f067aa0ba902b7, got %s", span.ParentSpanID)
+	}
+	if span.Attributes["http.status_code"] != 500 {
+		t.Errorf("Expected status code attribute 500, got %v", span.Attributes["http.status_code"])
+	}
+	if span.StatusCode != tracing.StatusError {
+		t.Errorf("Expected error status for 5xx, got %d", span.StatusCode)
+	}
+	if span.Attributes["request_id"] != w.Header().Get("X-Request-ID") {
+		t.Errorf("Expected request_id attribute %s, got %v", w.Header().Get("X-Request-ID"), span.Attributes["request_id"])
+	}
+}
+
+func TestRequestID_LogFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/fields/:id", func(c *gin.Context) {
+		fields := logger.FieldsFromContext(c.Request.Context())
+		c.JSON(200, gin.H{"request_id": fields["request_id"], "route": fields["route"]})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/fields/1", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	router.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), `"request_id":"req-123"`) {
+		t.Errorf("Expected request context to carry request_id, got %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"route":"/fields/:id"`) {
+		t.Errorf("Expected request context to carry the route template, got %s", w.Body.String())
+	}
+}
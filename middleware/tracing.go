@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
+)
+
+// Tracing starts a server span per request, continuing inbound W3C trace context; register after RequestID
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := tracing.Extract(c.Request.Context(), c.Request.Header)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.StartWithKind(ctx, c.Request.Method+" "+route, tracing.SpanKindServer)
+		defer span.End()
+
+		traceID := span.SpanContext().TraceID.String()
+		span.SetAttribute("http.method", c.Request.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("http.target", c.Request.URL.RequestURI())
+		span.SetAttribute("request_id", c.GetString("request_id"))
+		c.Set("trace_id", traceID)
+		ctx = logger.ContextWithFields(ctx, logger.Fields{"trace_id": traceID})
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttribute("http.status_code", status)
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(tracing.StatusError, http.StatusText(status))
+		}
+	}
+}
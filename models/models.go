@@ -1,20 +1,61 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/money"
+)
 
 // HealthCheck represents the health check response
 type HealthCheck struct {
 	Status    string    `json:"status"`
 	Timestamp time.Time `json:"timestamp"`
 	Version   string    `json:"version"`
+	Commit    string    `json:"commit,omitempty"`
 	Uptime    string    `json:"uptime"`
 }
 
+// ReadinessResponse represents the readiness probe response
+type ReadinessResponse struct {
+	Status    string                      `json:"status"` // ready, degraded or not_ready
+	Timestamp time.Time                   `json:"timestamp"`
+	Version   string                      `json:"version"`
+	Checks    map[string]DependencyStatus `json:"checks"`
+}
+
+// DependencyStatus represents the outcome of a single readiness check
+type DependencyStatus struct {
+	Status   string `json:"status"` // up or down
+	Critical bool   `json:"critical"`
+	Latency  string `json:"latency"`
+	Error    string `json:"error,omitempty"`
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	Code      int    `json:"code"`
+	ErrorCode string `json:"error_code,omitempty"` // Stable machine-readable error code
+	RequestID string `json:"request_id,omitempty"` // Request ID for log correlation
+}
+
+// ProblemDetails represents an RFC 7807 application/problem+json error response
+type ProblemDetails struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`   // Request ID of the failed request
+	ErrorCode string       `json:"error_code,omitempty"` // Stable machine-readable error code
+	RequestID string       `json:"request_id,omitempty"` // Request ID for log correlation
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError represents a single field-level validation violation
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
-	Code    int    `json:"code"`
 }
 
 // ForecastRequest represents a request for financial forecasting
@@ -24,29 +65,62 @@ type ForecastRequest struct {
 	Amount         float64 `json:"amount" binding:"required,gt=0"`
 	Periods        int     `json:"periods,omitempty"`       // Number of periods to forecast
 	ForecastType   string  `json:"forecast_type,omitempty"` // "linear", "exponential", "moving_average"
+	Calendar       string  `json:"calendar,omitempty"`      // "calendar" (every day), "weekdays" or "business" (pair holidays)
+	Frequency      string  `json:"frequency,omitempty"`     // "hourly", "daily", "weekly", "monthly", "quarterly"
+	StartDate      string  `json:"start_date,omitempty"`    // YYYY-MM-DD or RFC 3339; periods count from here instead of now
+	Timezone       string  `json:"timezone,omitempty"`      // IANA name such as "America/New_York"; defaults to UTC
 }
 
 // ForecastResponse represents a financial forecast response
 type ForecastResponse struct {
+	ForecastID      string           `json:"forecast_id,omitempty"` // Stable ID for retrieving the forecast from history
 	BaseCurrency    string           `json:"base_currency"`
 	TargetCurrency  string           `json:"target_currency"`
 	CurrentRate     float64          `json:"current_rate"`
-	Amount          float64          `json:"amount"`
+	Amount          money.Decimal    `json:"amount"` // Request amount rounded to the base currency's minor unit
 	ForecastType    string           `json:"forecast_type"`
+	Calendar        string           `json:"calendar,omitempty"`   // Calendar the period dates step over
+	Frequency       string           `json:"frequency,omitempty"`  // Length of one period
+	StartDate       string           `json:"start_date,omitempty"` // Start date from the request, if any
+	Timezone        string           `json:"timezone,omitempty"`   // Timezone the period dates are in
 	Periods         int              `json:"periods"`
 	Forecasts       []ForecastPeriod `json:"forecasts"`
 	GeneratedAt     time.Time        `json:"generated_at"`
 	ConfidenceScore float64          `json:"confidence_score"`
 }
 
+// ForecastListResponse represents one page of stored forecasts, newest first
+type ForecastListResponse struct {
+	Forecasts  []ForecastResponse `json:"forecasts"`
+	NextCursor string             `json:"next_cursor,omitempty"` // Pass as cursor to fetch the next page
+}
+
+// AccuracyReport represents forecast errors measured against realized rates over a rolling window
+type AccuracyReport struct {
+	WindowDays  int             `json:"window_days"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Stats       []AccuracyStats `json:"stats"`
+}
+
+// AccuracyStats represents the forecast error for one pair, model and horizon
+type AccuracyStats struct {
+	Pair         string  `json:"pair"`
+	ForecastType string  `json:"forecast_type"`
+	Frequency    string  `json:"frequency"`
+	Horizon      int     `json:"horizon"` // Forecast period, in periods of Frequency ahead
+	Samples      int     `json:"samples"`
+	MAPE         float64 `json:"mape"` // Mean absolute percentage error
+	Bias         float64 `json:"bias"` // Mean signed percentage error; positive means forecasts ran high
+}
+
 // ForecastPeriod represents a single period in the forecast
 type ForecastPeriod struct {
-	Period        int     `json:"period"`
-	Date          string  `json:"date"`
-	Rate          float64 `json:"rate"`
-	Amount        float64 `json:"amount"`
-	Change        float64 `json:"change"`         // Change from previous period
-	ChangePercent float64 `json:"change_percent"` // Percentage change from previous period
+	Period        int           `json:"period"`
+	Date          string        `json:"date"` // YYYY-MM-DD, or an RFC 3339 timestamp for hourly forecasts
+	Rate          float64       `json:"rate"`
+	Amount        money.Decimal `json:"amount"`         // Amount converted at Rate, rounded to the target currency's minor unit
+	Change        float64       `json:"change"`         // Change from previous period
+	ChangePercent float64       `json:"change_percent"` // Percentage change from previous period
 }
 
 // TrendAnalysis represents trend analysis data
@@ -68,14 +142,312 @@ type MultiCurrencyForecastRequest struct {
 	Amount       float64  `json:"amount" binding:"required,gt=0"`
 	Periods      int      `json:"periods,omitempty"`
 	ForecastType string   `json:"forecast_type,omitempty"`
+	Calendar     string   `json:"calendar,omitempty"`   // "calendar", "weekdays" or "business"; business uses each pair's joint calendar
+	Frequency    string   `json:"frequency,omitempty"`  // "hourly", "daily", "weekly", "monthly", "quarterly"
+	StartDate    string   `json:"start_date,omitempty"` // YYYY-MM-DD or RFC 3339; periods count from here instead of now
+	Timezone     string   `json:"timezone,omitempty"`   // IANA name; defaults to UTC
 }
 
 // MultiCurrencyForecastResponse represents a multi-currency forecast response
 type MultiCurrencyForecastResponse struct {
 	BaseCurrency string                      `json:"base_currency"`
-	Amount       float64                     `json:"amount"`
+	Amount       money.Decimal               `json:"amount"`
 	ForecastType string                      `json:"forecast_type"`
+	Calendar     string                      `json:"calendar,omitempty"`
+	Frequency    string                      `json:"frequency,omitempty"`
+	StartDate    string                      `json:"start_date,omitempty"`
+	Timezone     string                      `json:"timezone,omitempty"`
 	Periods      int                         `json:"periods"`
 	Currencies   map[string][]ForecastPeriod `json:"currencies"`
 	GeneratedAt  time.Time                   `json:"generated_at"`
 }
+
+// Currency represents an ISO 4217 currency and whether forecasts accept it
+type Currency struct {
+	Code       string   `json:"code"`
+	Numeric    string   `json:"numeric"` // Three-digit ISO 4217 numeric code
+	Name       string   `json:"name"`
+	MinorUnits int      `json:"minor_units"` // Digits after the decimal separator; amounts are rounded to this
+	Symbol     string   `json:"symbol"`
+	Countries  []string `json:"countries"` // ISO 3166-1 alpha-2 codes
+	Supported  bool     `json:"supported"` // Listed in SUPPORTED_CURRENCIES
+}
+
+// CurrencyListResponse represents the currencies accepted in forecast requests
+type CurrencyListResponse struct {
+	Currencies []Currency `json:"currencies"`
+}
+
+// Holding represents a balance held in one currency; negative amounts are short positions
+type Holding struct {
+	Currency string  `json:"currency" binding:"required"`
+	Amount   float64 `json:"amount" binding:"required"`
+}
+
+// PortfolioForecastRequest represents a request to forecast the value of several currency holdings in one reporting currency
+type PortfolioForecastRequest struct {
+	ReportingCurrency string    `json:"reporting_currency" binding:"required"`
+	Holdings          []Holding `json:"holdings" binding:"required,min=1,dive"`
+	Periods           int       `json:"periods,omitempty"`
+	ForecastType      string    `json:"forecast_type,omitempty"`
+	Calendar          string    `json:"calendar,omitempty"`         // "calendar", "weekdays" or "business"; business uses the joint calendar of every currency
+	Frequency         string    `json:"frequency,omitempty"`        // "hourly", "daily", "weekly", "monthly", "quarterly"
+	StartDate         string    `json:"start_date,omitempty"`       // YYYY-MM-DD or RFC 3339; periods count from here instead of now
+	Timezone          string    `json:"timezone,omitempty"`         // IANA name; defaults to UTC
+	ConfidenceLevel   float64   `json:"confidence_level,omitempty"` // Probability covered by the value bands; defaults to 0.95
+	WindowDays        int       `json:"window_days,omitempty"`      // Days of rate history used to estimate volatility and correlation; defaults to 90
+}
+
+// PortfolioForecastResponse represents the forecast value of each holding and of the whole portfolio
+type PortfolioForecastResponse struct {
+	ReportingCurrency string            `json:"reporting_currency"`
+	CurrentValue      money.Decimal     `json:"current_value"`
+	ForecastType      string            `json:"forecast_type"`
+	Calendar          string            `json:"calendar,omitempty"`
+	Frequency         string            `json:"frequency,omitempty"`
+	StartDate         string            `json:"start_date,omitempty"`
+	Timezone          string            `json:"timezone,omitempty"`
+	Periods           int               `json:"periods"`
+	ConfidenceLevel   float64           `json:"confidence_level"`
+	Holdings          []HoldingForecast `json:"holdings"`
+	Totals            []PortfolioPeriod `json:"totals"`
+	RiskModel         RiskModel         `json:"risk_model"`
+	GeneratedAt       time.Time         `json:"generated_at"`
+}
+
+// HoldingForecast represents the forecast value of one holding in the reporting currency
+type HoldingForecast struct {
+	Currency     string           `json:"currency"`
+	Amount       money.Decimal    `json:"amount"`        // Holding amount, rounded to its currency's minor unit
+	CurrentRate  float64          `json:"current_rate"`  // Reporting currency per unit of Currency
+	CurrentValue money.Decimal    `json:"current_value"` // Amount at CurrentRate in the reporting currency
+	Volatility   float64          `json:"volatility"`    // Daily standard deviation of the log return of CurrentRate
+	Forecasts    []ForecastPeriod `json:"forecasts"`     // Amount holds the forecast value in the reporting currency
+}
+
+// PortfolioPeriod represents the total portfolio value in one period with its value band
+type PortfolioPeriod struct {
+	Period int           `json:"period"`
+	Date   string        `json:"date"`
+	Value  money.Decimal `json:"value"`
+	Lower  money.Decimal `json:"lower"` // Lower bound of the value at the confidence level
+	Upper  money.Decimal `json:"upper"` // Upper bound of the value at the confidence level
+}
+
+// RiskModel describes how volatility and correlation were estimated
+type RiskModel struct {
+	Source       string      `json:"source"`       // "history" when estimated from stored rates, "default" when there was too little history
+	Observations int         `json:"observations"` // Daily returns used in the estimate
+	WindowDays   int         `json:"window_days"`
+	Currencies   []string    `json:"currencies"`  // Order of the Correlation rows and columns
+	Correlation  [][]float64 `json:"correlation"` // Correlation of daily log returns between the holdings' rates
+}
+
+// ValueAtRiskRequest represents a request for the value at risk and expected shortfall of currency positions
+type ValueAtRiskRequest struct {
+	ReportingCurrency string    `json:"reporting_currency" binding:"required"`
+	Positions         []Holding `json:"positions" binding:"required,min=1,dive"`
+	ConfidenceLevels  []float64 `json:"confidence_levels,omitempty"` // Defaults to [0.95, 0.99]
+	HorizonDays       []int     `json:"horizon_days,omitempty"`      // Holding periods in days; defaults to [1, 10]
+	Methods           []string  `json:"methods,omitempty"`           // "historical", "parametric", "monte_carlo"; defaults to every method the rate history supports
+	WindowDays        int       `json:"window_days,omitempty"`       // Days of rate history used; defaults to 365
+	Simulations       int       `json:"simulations,omitempty"`       // Monte Carlo paths; defaults to 10000
+	Seed              int64     `json:"seed,omitempty"`              // Monte Carlo random seed; a fixed seed gives repeatable results
+}
+
+// ValueAtRiskResponse represents the value at risk and expected shortfall of currency positions by method, confidence and horizon
+type ValueAtRiskResponse struct {
+	ReportingCurrency string          `json:"reporting_currency"`
+	PortfolioValue    money.Decimal   `json:"portfolio_value"`
+	Positions         []PositionValue `json:"positions"`
+	Measures          []RiskMeasure   `json:"measures"`
+	RiskModel         RiskModel       `json:"risk_model"`
+	Simulations       int             `json:"simulations,omitempty"` // Monte Carlo paths, when monte_carlo ran
+	Seed              int64           `json:"seed,omitempty"`        // Monte Carlo seed, when monte_carlo ran
+	GeneratedAt       time.Time       `json:"generated_at"`
+}
+
+// PositionValue represents the current value of one position in the reporting currency
+type PositionValue struct {
+	Currency     string        `json:"currency"`
+	Amount       money.Decimal `json:"amount"`
+	CurrentRate  float64       `json:"current_rate"` // Reporting currency per unit of Currency
+	CurrentValue money.Decimal `json:"current_value"`
+}
+
+// RiskMeasure represents the value at risk and expected shortfall from one method at one confidence level and horizon
+type RiskMeasure struct {
+	Method            string        `json:"method"`
+	ConfidenceLevel   float64       `json:"confidence_level"`
+	HorizonDays       int           `json:"horizon_days"`
+	ValueAtRisk       money.Decimal `json:"value_at_risk"`      // Loss not exceeded with probability ConfidenceLevel, as a positive amount
+	ExpectedShortfall money.Decimal `json:"expected_shortfall"` // Mean loss beyond ValueAtRisk
+	Scenarios         int           `json:"scenarios"`          // Historical or simulated outcomes behind the estimate; 0 for parametric
+}
+
+// CorrelationResponse represents correlation and covariance matrices of daily log returns against a base currency
+type CorrelationResponse struct {
+	Base         string              `json:"base"`
+	Currencies   []string            `json:"currencies"`            // Order of the matrix rows and columns
+	Unavailable  []string            `json:"unavailable,omitempty"` // Supported currencies with no rate history in the window
+	Method       string              `json:"method"`
+	Lambda       float64             `json:"lambda,omitempty"` // EWMA decay factor, when method is ewma
+	WindowDays   int                 `json:"window_days"`
+	From         string              `json:"from"` // YYYY-MM-DD
+	To           string              `json:"to"`   // YYYY-MM-DD
+	Observations int                 `json:"observations"`
+	Pearson      [][]float64         `json:"pearson"`
+	Spearman     [][]float64         `json:"spearman"`   // Rank correlation; always equally weighted
+	Covariance   [][]float64         `json:"covariance"` // Daily log return covariance
+	StepDays     int                 `json:"step_days,omitempty"`
+	Rolling      []CorrelationWindow `json:"rolling,omitempty"` // Every rolling window, oldest first, when more than one was requested
+	GeneratedAt  time.Time           `json:"generated_at"`
+}
+
+// CorrelationWindow represents the estimates over one rolling window
+type CorrelationWindow struct {
+	From         string      `json:"from"`
+	To           string      `json:"to"`
+	Observations int         `json:"observations"`
+	Pearson      [][]float64 `json:"pearson"`
+	Spearman     [][]float64 `json:"spearman"`
+	Covariance   [][]float64 `json:"covariance"`
+}
+
+// Shock represents a change applied to one currency's forecast in a scenario
+type Shock struct {
+	Currency      string  `json:"currency" binding:"required"`
+	Type          string  `json:"type" binding:"required"`  // "jump", "drift" or "volatility"
+	ChangePercent float64 `json:"change_percent,omitempty"` // Jump or drift: change in the currency's value against the base, e.g. -10
+	Multiplier    float64 `json:"multiplier,omitempty"`     // Volatility: factor applied to the currency's volatility
+	StartPeriod   int     `json:"start_period,omitempty"`   // First period affected; defaults to 1
+	EndPeriod     int     `json:"end_period,omitempty"`     // Drift: period the full change is reached; defaults to the last
+}
+
+// ScenarioForecastRequest represents a request to forecast positions under shocks or a historical stress scenario
+type ScenarioForecastRequest struct {
+	BaseCurrency    string   `json:"base_currency" binding:"required"`
+	Currencies      []string `json:"currencies" binding:"required,min=1"`
+	Amount          float64  `json:"amount" binding:"required,gt=0"` // Base currency amount held in each currency from today
+	Shocks          []Shock  `json:"shocks,omitempty" binding:"dive"`
+	StressScenario  string   `json:"stress_scenario,omitempty"` // Name of a historical stress scenario to replay
+	Periods         int      `json:"periods,omitempty"`
+	ForecastType    string   `json:"forecast_type,omitempty"`
+	Calendar        string   `json:"calendar,omitempty"`         // "calendar", "weekdays" or "business"; business uses the joint calendar of every currency
+	Frequency       string   `json:"frequency,omitempty"`        // "hourly", "daily", "weekly", "monthly", "quarterly"
+	StartDate       string   `json:"start_date,omitempty"`       // YYYY-MM-DD or RFC 3339; periods count from here instead of now
+	Timezone        string   `json:"timezone,omitempty"`         // IANA name; defaults to UTC
+	ConfidenceLevel float64  `json:"confidence_level,omitempty"` // Probability covered by the rate bands; defaults to 0.95
+	WindowDays      int      `json:"window_days,omitempty"`      // Days of rate history used to estimate volatility; defaults to 90
+}
+
+// ScenarioForecastResponse represents baseline and shocked forecasts with the impact of the shocks on the positions
+type ScenarioForecastResponse struct {
+	BaseCurrency    string             `json:"base_currency"`
+	Amount          money.Decimal      `json:"amount"`
+	ForecastType    string             `json:"forecast_type"`
+	Calendar        string             `json:"calendar,omitempty"`
+	Frequency       string             `json:"frequency,omitempty"`
+	StartDate       string             `json:"start_date,omitempty"`
+	Timezone        string             `json:"timezone,omitempty"`
+	Periods         int                `json:"periods"`
+	ConfidenceLevel float64            `json:"confidence_level"`
+	StressScenario  *StressScenario    `json:"stress_scenario,omitempty"`
+	Shocks          []Shock            `json:"shocks"`                // Every shock applied, including those replayed from the stress scenario
+	Unavailable     []string           `json:"unavailable,omitempty"` // Currencies the stress scenario could not be replayed for
+	Currencies      []ScenarioForecast `json:"currencies"`
+	Totals          []ScenarioTotal    `json:"totals"`
+	RiskModel       RiskModel          `json:"risk_model"`
+	GeneratedAt     time.Time          `json:"generated_at"`
+}
+
+// ScenarioForecast represents the baseline and shocked paths of one currency against the base
+type ScenarioForecast struct {
+	Currency    string           `json:"currency"`
+	CurrentRate float64          `json:"current_rate"` // Units of Currency per unit of the base
+	Volatility  float64          `json:"volatility"`   // Daily standard deviation of the log return of CurrentRate, before shocks
+	Baseline    []ScenarioPeriod `json:"baseline"`
+	Shocked     []ScenarioPeriod `json:"shocked"`
+}
+
+// ScenarioPeriod represents one period of a baseline or shocked path
+type ScenarioPeriod struct {
+	Period int           `json:"period"`
+	Date   string        `json:"date"`
+	Rate   float64       `json:"rate"`
+	Lower  float64       `json:"lower"`  // Lower bound of the rate at the confidence level
+	Upper  float64       `json:"upper"`  // Upper bound of the rate at the confidence level
+	Value  money.Decimal `json:"value"`  // Position value in the base currency
+	PnL    money.Decimal `json:"pnl"`    // Value less the amount
+	Impact money.Decimal `json:"impact"` // Shocked value less baseline value; zero on the baseline
+}
+
+// ScenarioTotal represents the value of every position in one period on both paths
+type ScenarioTotal struct {
+	Period        int           `json:"period"`
+	Date          string        `json:"date"`
+	BaselineValue money.Decimal `json:"baseline_value"`
+	ShockedValue  money.Decimal `json:"shocked_value"`
+	Impact        money.Decimal `json:"impact"`
+}
+
+// StressScenario represents a named historical market episode
+type StressScenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	From        string `json:"from"` // YYYY-MM-DD
+	To          string `json:"to"`   // YYYY-MM-DD
+}
+
+// StressScenarioListResponse represents the library of historical stress scenarios
+type StressScenarioListResponse struct {
+	Scenarios []StressScenario `json:"scenarios"`
+}
+
+// HedgingRequest represents a request to compare hedging a conversion with forwards against converting at the forecast rate
+type HedgingRequest struct {
+	BaseCurrency   string  `json:"base_currency" binding:"required"`
+	TargetCurrency string  `json:"target_currency" binding:"required"`
+	Amount         float64 `json:"amount" binding:"required,gt=0"` // Base currency amount converted in each period
+	Periods        int     `json:"periods,omitempty"`
+	ForecastType   string  `json:"forecast_type,omitempty"` // Model the unhedged rate is forecast with
+	Calendar       string  `json:"calendar,omitempty"`      // "calendar", "weekdays" or "business"
+	Frequency      string  `json:"frequency,omitempty"`     // "hourly", "daily", "weekly", "monthly", "quarterly"
+	StartDate      string  `json:"start_date,omitempty"`    // YYYY-MM-DD or RFC 3339; periods count from here instead of now
+	Timezone       string  `json:"timezone,omitempty"`      // IANA name; defaults to UTC
+}
+
+// HedgingResponse represents covered interest parity forward rates against the model forecast for each period
+type HedgingResponse struct {
+	BaseCurrency   string          `json:"base_currency"`
+	TargetCurrency string          `json:"target_currency"`
+	SpotRate       float64         `json:"spot_rate"`
+	Amount         money.Decimal   `json:"amount"`
+	ForecastType   string          `json:"forecast_type"`
+	Calendar       string          `json:"calendar,omitempty"`
+	Frequency      string          `json:"frequency,omitempty"`
+	StartDate      string          `json:"start_date,omitempty"`
+	Timezone       string          `json:"timezone,omitempty"`
+	Periods        int             `json:"periods"`
+	Forwards       []HedgingPeriod `json:"forwards"`
+	HedgedTotal    money.Decimal   `json:"hedged_total"`    // Target currency received over every period with forwards
+	UnhedgedTotal  money.Decimal   `json:"unhedged_total"`  // Target currency expected over every period at the forecast rates
+	HedgingBenefit money.Decimal   `json:"hedging_benefit"` // Hedged less unhedged total; negative is the expected cost of hedging
+	GeneratedAt    time.Time       `json:"generated_at"`
+}
+
+// HedgingPeriod represents the forward and forecast rates for one period and the outcome of hedging its amount
+type HedgingPeriod struct {
+	Period             int           `json:"period"`
+	Date               string        `json:"date"`
+	Days               float64       `json:"days"`                 // Term of the forward from today
+	BaseInterestRate   float64       `json:"base_interest_rate"`   // Annual percent rate of the base currency for the term
+	TargetInterestRate float64       `json:"target_interest_rate"` // Annual percent rate of the target currency for the term
+	ForwardRate        float64       `json:"forward_rate"`
+	ForwardPoints      float64       `json:"forward_points"` // Forward less spot rate
+	ForecastRate       float64       `json:"forecast_rate"`
+	HedgedAmount       money.Decimal `json:"hedged_amount"`   // Amount converted at ForwardRate
+	UnhedgedAmount     money.Decimal `json:"unhedged_amount"` // Amount converted at ForecastRate
+	HedgingBenefit     money.Decimal `json:"hedging_benefit"` // Hedged less unhedged amount; negative is the expected cost of hedging
+	BenefitPercent     float64       `json:"benefit_percent"` // HedgingBenefit as a percentage of UnhedgedAmount
+}
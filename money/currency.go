@@ -0,0 +1,19 @@
+package money
+
+import "github.com/dalfonso89/financial-forecasting-service/currency"
+
+// defaultMinorUnits is the number of fractional digits assumed for codes outside the ISO 4217 registry
+const defaultMinorUnits = 2
+
+// MinorUnits returns the number of fractional digits ISO 4217 defines for a currency, defaulting to 2
+func MinorUnits(code string) int32 {
+	if c, ok := currency.Lookup(code); ok {
+		return int32(c.MinorUnits)
+	}
+	return defaultMinorUnits
+}
+
+// RoundAmount rounds an amount to the minor unit of its currency
+func RoundAmount(amount Decimal, currency string, mode RoundingMode) Decimal {
+	return amount.Round(MinorUnits(currency), mode)
+}
@@ -0,0 +1,254 @@
+package money
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact base-10 number equal to value × 10^-scale. The zero value is 0.
+type Decimal struct {
+	value  *big.Int // nil means zero
+	scale  int32
+	quoted bool // Encode as a JSON string; set only on the copies MarshalJSONQuoted encodes
+}
+
+// RoundingMode selects how Round resolves a discarded half
+type RoundingMode string
+
+const (
+	// RoundHalfEven rounds halves to the even neighbour, so 2.5 becomes 2 and 3.5 becomes 4
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundHalfUp rounds halves away from zero, so 2.5 becomes 3 and -2.5 becomes -3
+	RoundHalfUp RoundingMode = "half_up"
+)
+
+// maxExponent bounds the exponent Parse accepts, so input such as "1e2000000000" cannot build a huge number
+const maxExponent = 100
+
+// ParseRoundingMode validates a rounding mode name
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	switch mode := RoundingMode(name); mode {
+	case RoundHalfEven, RoundHalfUp:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown rounding mode %q", name)
+}
+
+// New returns value × 10^-scale
+func New(value int64, scale int32) Decimal {
+	return Decimal{value: big.NewInt(value), scale: scale}
+}
+
+// NewFromInt returns an integer decimal
+func NewFromInt(value int64) Decimal {
+	return New(value, 0)
+}
+
+// NewFromFloat returns the shortest decimal that round-trips to f, so 0.1 becomes exactly 0.1; it panics on NaN or infinity
+func NewFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Sprintf("money: cannot convert %v to a decimal", f))
+	}
+	d, err := Parse(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Parse reads a decimal such as "-1234.5678" or "1.5e3"
+func Parse(s string) (Decimal, error) {
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		if exponent, err = strconv.ParseInt(s[i+1:], 10, 32); err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+		if exponent > maxExponent || exponent < -maxExponent {
+			return Decimal{}, fmt.Errorf("decimal %q has an exponent outside ±%d", s, maxExponent)
+		}
+		mantissa = s[:i]
+	}
+
+	sign := ""
+	if mantissa != "" && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+	whole, fraction, hasPoint := strings.Cut(mantissa, ".")
+	if !isDigits(whole) || (hasPoint && !isDigits(fraction)) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	value, _ := new(big.Int).SetString(sign+whole+fraction, 10)
+	scale := int64(len(fraction))
+
+	scale -= exponent
+	if scale < 0 {
+		value.Mul(value, pow10(-scale))
+		scale = 0
+	}
+	if scale > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("decimal %q has too many fractional digits", s)
+	}
+	return Decimal{value: value, scale: int32(scale)}, nil
+}
+
+// RequireFromString parses s and panics if it is invalid; intended for constants and tests
+func RequireFromString(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{value: a.Add(a, b), scale: scale}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{value: a.Sub(a, b), scale: scale}
+}
+
+// Mul returns d × other exactly
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{value: new(big.Int).Mul(d.bigInt(), other.bigInt()), scale: d.scale + other.scale}
+}
+
+// Round returns d rounded to places fractional digits using mode; the result always has exactly places digits
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if d.scale <= places {
+		return Decimal{value: new(big.Int).Mul(d.bigInt(), pow10(int64(places-d.scale))), scale: places}
+	}
+
+	divisor := pow10(int64(d.scale - places))
+	quotient, remainder := new(big.Int).QuoRem(d.bigInt(), divisor, new(big.Int))
+	if remainder.Sign() != 0 {
+		// Compare twice the discarded part with the divisor to find which side of the half it falls
+		half := new(big.Int).Abs(remainder)
+		half.Lsh(half, 1)
+		switch cmp := half.Cmp(divisor); {
+		case cmp > 0, cmp == 0 && (mode != RoundHalfEven || quotient.Bit(0) == 1):
+			quotient.Add(quotient, big.NewInt(int64(d.Sign())))
+		}
+	}
+	return Decimal{value: quotient, scale: places}
+}
+
+// Cmp returns -1, 0 or +1 as d is less than, equal to or greater than other
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and other are numerically equal, ignoring scale
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Sign returns -1, 0 or +1
+func (d Decimal) Sign() int {
+	return d.bigInt().Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Scale returns the number of fractional digits
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Float64 returns the nearest float64
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d in fixed point with exactly Scale fractional digits
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.bigInt()).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON encodes d as a JSON number; MarshalJSONQuoted encodes it as a string
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.quoted {
+		return []byte(strconv.Quote(d.String())), nil
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// bigInt returns the coefficient, treating nil as zero
+func (d Decimal) bigInt() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+// align returns copies of both coefficients at a common scale
+func align(a, b Decimal) (*big.Int, *big.Int, int32) {
+	x, y := new(big.Int).Set(a.bigInt()), new(big.Int).Set(b.bigInt())
+	switch {
+	case a.scale < b.scale:
+		x.Mul(x, pow10(int64(b.scale-a.scale)))
+		return x, y, b.scale
+	case a.scale > b.scale:
+		y.Mul(y, pow10(int64(a.scale-b.scale)))
+	}
+	return x, y, a.scale
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// pow10 returns 10^n
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
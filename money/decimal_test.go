@@ -0,0 +1,147 @@
+package money
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"0", "0", false},
+		{"1234.5678", "1234.5678", false},
+		{"-0.05", "-0.05", false},
+		{"+7.10", "7.10", false},
+		{"1.5e3", "1500", false},
+		{"25E-4", "0.0025", false},
+		{"", "", true},
+		{"1.", "", true},
+		{".5", "", true},
+		{"1.2.3", "", true},
+		{"--1", "", true},
+		{"12a", "", true},
+		{"1e", "", true},
+		{"1e100", "1" + strings.Repeat("0", 100), false},
+		{"1e2000000000", "", true},
+		{"1e-101", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && d.String() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, d.String())
+			}
+		})
+	}
+}
+
+func TestNewFromFloat(t *testing.T) {
+	if got := NewFromFloat(0.1).Add(NewFromFloat(0.2)).String(); got != "0.3" {
+		t.Errorf("Expected 0.1 + 0.2 = 0.3, got %s", got)
+	}
+	if got := NewFromFloat(1e21).String(); got != "1000000000000000000000" {
+		t.Errorf("Expected 1e21 in fixed point, got %s", got)
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a, b := RequireFromString("1000.10"), RequireFromString("0.8585")
+	if got := a.Mul(b).String(); got != "858.585850" {
+		t.Errorf("Expected 858.585850, got %s", got)
+	}
+	if got := a.Sub(b).String(); got != "999.2415" {
+		t.Errorf("Expected 999.2415, got %s", got)
+	}
+	if !RequireFromString("1.50").Equal(RequireFromString("1.5")) {
+		t.Error("Expected 1.50 to equal 1.5")
+	}
+	if RequireFromString("-2").Cmp(RequireFromString("1")) != -1 {
+		t.Error("Expected -2 < 1")
+	}
+	var zero Decimal
+	if !zero.IsZero() || zero.String() != "0" || !zero.Add(a).Equal(a) {
+		t.Error("Expected the zero value to behave as 0")
+	}
+}
+
+func TestDecimal_Round(t *testing.T) {
+	tests := []struct {
+		value    string
+		places   int32
+		mode     RoundingMode
+		expected string
+	}{
+		{"2.5", 0, RoundHalfEven, "2"},
+		{"3.5", 0, RoundHalfEven, "4"},
+		{"2.5", 0, RoundHalfUp, "3"},
+		{"-2.5", 0, RoundHalfUp, "-3"},
+		{"-2.5", 0, RoundHalfEven, "-2"},
+		{"1.005", 2, RoundHalfUp, "1.01"},
+		{"1.005", 2, RoundHalfEven, "1.00"},
+		{"1.0051", 2, RoundHalfEven, "1.01"},
+		{"-0.004", 2, RoundHalfUp, "0.00"},
+		{"-0.006", 2, RoundHalfUp, "-0.01"},
+		{"7", 3, RoundHalfEven, "7.000"},
+	}
+	for _, tt := range tests {
+		if got := RequireFromString(tt.value).Round(tt.places, tt.mode).String(); got != tt.expected {
+			t.Errorf("Expected %s rounded %s to %d places to be %s, got %s", tt.value, tt.mode, tt.places, tt.expected, got)
+		}
+	}
+}
+
+func TestRoundAmount_MinorUnits(t *testing.T) {
+	amount := RequireFromString("1234.5678")
+	tests := map[string]string{
+		"USD": "1234.57",
+		"JPY": "1235",
+		"KWD": "1234.568",
+		"bhd": "1234.568",
+		"XYZ": "1234.57",
+	}
+	for currency, expected := range tests {
+		if got := RoundAmount(amount, currency, RoundHalfEven).String(); got != expected {
+			t.Errorf("Expected %s amount %s, got %s", currency, expected, got)
+		}
+	}
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	amount := RequireFromString("858.50")
+	encoded, _ := json.Marshal(amount)
+	if string(encoded) != "858.50" {
+		t.Errorf("Expected a JSON number, got %s", encoded)
+	}
+
+	encoded, _ = MarshalJSONQuoted(amount)
+	if string(encoded) != `"858.50"` {
+		t.Errorf("Expected a JSON string, got %s", encoded)
+	}
+
+	for _, input := range []string{`858.5`, `"858.50"`} {
+		var decoded Decimal
+		if err := json.Unmarshal([]byte(input), &decoded); err != nil || !decoded.Equal(amount) {
+			t.Errorf("Expected %s to decode to %s, got %s (err %v)", input, amount, decoded, err)
+		}
+	}
+	var invalid Decimal
+	if err := json.Unmarshal([]byte(`"abc"`), &invalid); err == nil {
+		t.Error("Expected an error decoding a non-numeric string")
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	if mode, err := ParseRoundingMode("half_even"); err != nil || mode != RoundHalfEven {
+		t.Errorf("Expected half_even, got %q (err %v)", mode, err)
+	}
+	if _, err := ParseRoundingMode("ceiling"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
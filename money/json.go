@@ -0,0 +1,62 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var decimalType = reflect.TypeOf(Decimal{})
+
+// MarshalJSONQuoted encodes v like json.Marshal, except that every Decimal in it is written as a string such as
+// "858.50". The choice is made per call, so responses that want numbers and values encoded elsewhere are unaffected.
+// v is copied by a JSON round trip and the Decimals of the copy are marked for quoting, so field names, embedded
+// structs and tag options follow encoding/json exactly. Decimals are reached through typed fields, pointers, slices,
+// arrays and maps; a Decimal held in an interface{} decodes as a float64 and stays a number.
+func MarshalJSONQuoted(v interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil || v == nil {
+		return encoded, err
+	}
+
+	decoded := reflect.New(reflect.TypeOf(v))
+	if err := json.Unmarshal(encoded, decoded.Interface()); err != nil {
+		return nil, fmt.Errorf("money: cannot copy %T to quote its decimals: %w", v, err)
+	}
+	markQuoted(decoded.Elem())
+	return json.Marshal(decoded.Elem().Interface())
+}
+
+// markQuoted marks every Decimal reachable from the addressable value v to be encoded as a string
+func markQuoted(v reflect.Value) {
+	if v.Type() == decimalType {
+		d := v.Interface().(Decimal)
+		d.quoted = true
+		v.Set(reflect.ValueOf(d))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			markQuoted(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				markQuoted(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			markQuoted(v.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			// Map values are not addressable, so each is marked in a copy and stored back
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			markQuoted(value)
+			v.SetMapIndex(key, value)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMarshalJSONQuoted(t *testing.T) {
+	type period struct {
+		Amount Decimal   `json:"amount"`
+		Rate   float64   `json:"rate"`
+		Date   time.Time `json:"date"`
+	}
+	type response struct {
+		Amount   Decimal             `json:"amount"`
+		Fee      *Decimal            `json:"fee,omitempty"`
+		Periods  []period            `json:"periods"`
+		ByTarget map[string][]period `json:"by_target"`
+		Note     string              `json:"note,omitempty"`
+		Ignored  Decimal             `json:"-"`
+	}
+	value := response{
+		Amount:   RequireFromString("1000.00"),
+		Periods:  []period{{Amount: RequireFromString("858.50"), Rate: 0.8585}},
+		ByTarget: map[string][]period{"GBP": {{Amount: RequireFromString("790.1"), Rate: 0.7901}}, "EUR": nil},
+		Ignored:  RequireFromString("1"),
+	}
+
+	quoted, err := MarshalJSONQuoted(&value)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := `{"amount":"1000.00","periods":[{"amount":"858.50","rate":0.8585,"date":"0001-01-01T00:00:00Z"}],` +
+		`"by_target":{"EUR":null,"GBP":[{"amount":"790.1","rate":0.7901,"date":"0001-01-01T00:00:00Z"}]}}`
+	if string(quoted) != expected {
+		t.Errorf("Expected %s, got %s", expected, quoted)
+	}
+
+	// Apart from the quotes, the output matches encoding/json, and the value is left as it was
+	numbers, _ := json.Marshal(&value)
+	unquoted := `{"amount":1000.00,"periods":[{"amount":858.50,"rate":0.8585,"date":"0001-01-01T00:00:00Z"}],` +
+		`"by_target":{"EUR":null,"GBP":[{"amount":790.1,"rate":0.7901,"date":"0001-01-01T00:00:00Z"}]}}`
+	if string(numbers) != unquoted {
+		t.Errorf("Expected %s, got %s", unquoted, numbers)
+	}
+}
+
+func TestMarshalJSONQuoted_EmbeddedFieldsAndTagOptions(t *testing.T) {
+	type Totals struct {
+		Total Decimal `json:"total"`
+	}
+	type Fees struct {
+		Fee Decimal `json:"fee"`
+	}
+	type response struct {
+		Totals                // Promoted into the response object
+		*Fees                 // Promoted through a pointer
+		Named   Totals        `json:"named"`
+		Periods int           `json:"periods,string"`
+		Spread  Decimal       `json:"spread,omitempty"`
+		Limit   Decimal       `json:"limit,string"` // Marshalers ignore the string option
+		Extra   *Decimal      `json:"extra,omitempty"`
+		Window  time.Duration `json:"window"`
+	}
+	value := response{
+		Totals:  Totals{Total: RequireFromString("12.50")},
+		Fees:    &Fees{Fee: RequireFromString("0.25")},
+		Named:   Totals{Total: RequireFromString("3")},
+		Periods: 7,
+		Limit:   RequireFromString("100"),
+		Window:  time.Second,
+	}
+
+	quoted, err := MarshalJSONQuoted(value)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := `{"total":"12.50","fee":"0.25","named":{"total":"3"},"periods":"7","spread":"0","limit":"100","window":1000000000}`
+	if string(quoted) != expected {
+		t.Errorf("Expected %s, got %s", expected, quoted)
+	}
+}
+
+func TestMarshalJSONQuoted_Values(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"decimal", RequireFromString("858.50"), `"858.50"`},
+		{"nil", nil, `null`},
+		{"map of decimals", map[string]Decimal{"EUR": RequireFromString("0.85")}, `{"EUR":"0.85"}`},
+		{"array of pointers", [2]*Decimal{nil, func() *Decimal { d := RequireFromString("1.5"); return &d }()}, `[null,"1.5"]`},
+		// Interface values decode without their Go types, so Decimals held in them keep numeric encoding
+		{"interface", map[string]interface{}{"total": RequireFromString("2.5")}, `{"total":2.5}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quoted, err := MarshalJSONQuoted(tt.value)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if string(quoted) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, quoted)
+			}
+		})
+	}
+}
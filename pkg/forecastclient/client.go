@@ -0,0 +1,432 @@
+// Package forecastclient provides a typed Go client for the financial forecasting API.
+package forecastclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+)
+
+// Config contains the settings for the forecasting API client
+type Config struct {
+	// BaseURL is the service root, e.g. "http://localhost:8082"
+	BaseURL string
+	// HTTPClient is used for all requests; a client with a 30s timeout is used when nil
+	HTTPClient *http.Client
+	// AuthToken is sent as a bearer token in the Authorization header when set
+	AuthToken string
+	// Headers are added to every request, e.g. an API key header
+	Headers map[string]string
+	// MaxRetries is the number of retries for network errors and retryable status codes; only idempotent requests are
+	// retried unless RetryPOST is set
+	MaxRetries int
+	// RetryPOST also retries POST requests. A POST the server handled before the failure is then handled again, so a
+	// forecast may be stored twice in the history and counted twice in accuracy statistics.
+	RetryPOST bool
+	// RetryBackoff is the initial delay between retries; it doubles on each attempt
+	RetryBackoff time.Duration
+}
+
+// Client is a typed client for the forecasting API
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	authToken    string
+	headers      map[string]string
+	maxRetries   int
+	retryPOST    bool
+	retryBackoff time.Duration
+}
+
+// APIError is returned when the API responds with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Response   models.ErrorResponse
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Response.RequestID != "" {
+		return fmt.Sprintf("forecast API returned status %d (%s): %s [request_id=%s]", e.StatusCode, e.Response.ErrorCode, e.Response.Message, e.Response.RequestID)
+	}
+	return fmt.Sprintf("forecast API returned status %d (%s): %s", e.StatusCode, e.Response.ErrorCode, e.Response.Message)
+}
+
+// LatestOptions holds the optional query parameters of the latest forecast endpoint
+type LatestOptions struct {
+	Amount       float64
+	Periods      int
+	ForecastType string
+}
+
+// CorrelationOptions selects the window and estimator for Correlation; zero values use the server defaults
+type CorrelationOptions struct {
+	WindowDays int
+	Method     string // "sample" or "ewma"
+	Lambda     float64
+	Windows    int
+	StepDays   int
+}
+
+// ListOptions filters and pages ListForecasts; zero values are omitted
+type ListOptions struct {
+	Pair         string // "BASE/TARGET"
+	ForecastType string
+	From         time.Time
+	To           time.Time
+	Limit        int
+	Cursor       string
+}
+
+// New creates a new forecasting API client
+func New(cfg Config) *Client {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	retryBackoff := cfg.RetryBackoff
+	if retryBackoff <= 0 {
+		retryBackoff = 200 * time.Millisecond
+	}
+
+	return &Client{
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		httpClient:   httpClient,
+		authToken:    cfg.AuthToken,
+		headers:      cfg.Headers,
+		maxRetries:   cfg.MaxRetries,
+		retryPOST:    cfg.RetryPOST,
+		retryBackoff: retryBackoff,
+	}
+}
+
+// GenerateForecast generates a single currency forecast
+func (c *Client) GenerateForecast(ctx context.Context, req *models.ForecastRequest) (*models.ForecastResponse, error) {
+	var response models.ForecastResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/forecast", req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// MultiCurrency generates forecasts for multiple currencies
+func (c *Client) MultiCurrency(ctx context.Context, req *models.MultiCurrencyForecastRequest) (*models.MultiCurrencyForecastResponse, error) {
+	var response models.MultiCurrencyForecastResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/forecast/multi-currency", req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Portfolio forecasts the value of several currency holdings in a reporting currency
+func (c *Client) Portfolio(ctx context.Context, req *models.PortfolioForecastRequest) (*models.PortfolioForecastResponse, error) {
+	var response models.PortfolioForecastResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/forecast/portfolio", req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Scenario forecasts currencies against a base with and without shocks or a historical stress scenario
+func (c *Client) Scenario(ctx context.Context, req *models.ScenarioForecastRequest) (*models.ScenarioForecastResponse, error) {
+	var response models.ScenarioForecastResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/forecast/scenario", req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// StressScenarios lists the historical stress scenarios a scenario forecast can replay
+func (c *Client) StressScenarios(ctx context.Context) ([]models.StressScenario, error) {
+	var response models.StressScenarioListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/forecast/scenarios", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Scenarios, nil
+}
+
+// Hedging prices forwards for each forecast period and compares hedging an amount with converting it at the forecast rate
+func (c *Client) Hedging(ctx context.Context, req *models.HedgingRequest) (*models.HedgingResponse, error) {
+	var response models.HedgingResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/forecast/hedging", req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ValueAtRisk estimates the value at risk and expected shortfall of currency positions
+func (c *Client) ValueAtRisk(ctx context.Context, req *models.ValueAtRiskRequest) (*models.ValueAtRiskResponse, error) {
+	var response models.ValueAtRiskResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/risk/var", req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Correlation returns correlation and covariance matrices of every supported currency against a base
+func (c *Client) Correlation(ctx context.Context, base string, opts CorrelationOptions) (*models.CorrelationResponse, error) {
+	query := url.Values{}
+	query.Set("base", base)
+	if opts.WindowDays > 0 {
+		query.Set("window", strconv.Itoa(opts.WindowDays))
+	}
+	if opts.Method != "" {
+		query.Set("method", opts.Method)
+	}
+	if opts.Lambda > 0 {
+		query.Set("lambda", strconv.FormatFloat(opts.Lambda, 'f', -1, 64))
+	}
+	if opts.Windows > 0 {
+		query.Set("windows", strconv.Itoa(opts.Windows))
+	}
+	if opts.StepDays > 0 {
+		query.Set("step", strconv.Itoa(opts.StepDays))
+	}
+
+	var response models.CorrelationResponse
+	if err := c.do(ctx, http.MethodGet, withQuery("/api/v1/analytics/correlation", query), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AnalyzeTrend analyzes the trend for a currency pair; periods <= 0 uses the server default
+func (c *Client) AnalyzeTrend(ctx context.Context, baseCurrency, targetCurrency string, periods int) (*models.TrendAnalysis, error) {
+	query := url.Values{}
+	if periods > 0 {
+		query.Set("periods", strconv.Itoa(periods))
+	}
+
+	var response models.TrendAnalysis
+	path := withQuery(fmt.Sprintf("/api/v1/forecast/trend/%s/%s", url.PathEscape(baseCurrency), url.PathEscape(targetCurrency)), query)
+	if err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Latest generates a forecast based on the latest exchange rates
+func (c *Client) Latest(ctx context.Context, baseCurrency, targetCurrency string, opts LatestOptions) (*models.ForecastResponse, error) {
+	query := url.Values{}
+	if opts.Amount > 0 {
+		query.Set("amount", strconv.FormatFloat(opts.Amount, 'f', -1, 64))
+	}
+	if opts.Periods > 0 {
+		query.Set("periods", strconv.Itoa(opts.Periods))
+	}
+	if opts.ForecastType != "" {
+		query.Set("type", opts.ForecastType)
+	}
+
+	var response models.ForecastResponse
+	path := withQuery(fmt.Sprintf("/api/v1/forecast/latest/%s/%s", url.PathEscape(baseCurrency), url.PathEscape(targetCurrency)), query)
+	if err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ClearCache clears the server-side forecast cache
+func (c *Client) ClearCache(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/forecast/cache", nil, nil)
+}
+
+// Currencies returns the ISO 4217 details of every supported currency
+func (c *Client) Currencies(ctx context.Context) ([]models.Currency, error) {
+	var response models.CurrencyListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/currencies", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Currencies, nil
+}
+
+// Currency returns the ISO 4217 details of one currency and whether the server supports it
+func (c *Client) Currency(ctx context.Context, code string) (*models.Currency, error) {
+	var response models.Currency
+	if err := c.do(ctx, http.MethodGet, "/api/v1/currencies/"+url.PathEscape(code), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListForecasts returns one page of stored forecasts, newest first; pass NextCursor back as Cursor for the next page
+func (c *Client) ListForecasts(ctx context.Context, opts ListOptions) (*models.ForecastListResponse, error) {
+	query := url.Values{}
+	if opts.Pair != "" {
+		query.Set("pair", opts.Pair)
+	}
+	if opts.ForecastType != "" {
+		query.Set("type", opts.ForecastType)
+	}
+	if !opts.From.IsZero() {
+		query.Set("from", opts.From.Format(time.RFC3339))
+	}
+	if !opts.To.IsZero() {
+		query.Set("to", opts.To.Format(time.RFC3339))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		query.Set("cursor", opts.Cursor)
+	}
+
+	var response models.ForecastListResponse
+	if err := c.do(ctx, http.MethodGet, withQuery("/api/v1/forecasts", query), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetForecast returns a stored forecast by its ID
+func (c *Client) GetForecast(ctx context.Context, id string) (*models.ForecastResponse, error) {
+	var response models.ForecastResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/forecasts/"+url.PathEscape(id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Accuracy reports how closely past forecasts matched realized rates; empty pair and forecastType report every group
+func (c *Client) Accuracy(ctx context.Context, pair, forecastType string) (*models.AccuracyReport, error) {
+	query := url.Values{}
+	if pair != "" {
+		query.Set("pair", pair)
+	}
+	if forecastType != "" {
+		query.Set("type", forecastType)
+	}
+
+	var response models.AccuracyReport
+	if err := c.do(ctx, http.MethodGet, withQuery("/api/v1/forecast/accuracy", query), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// do executes a request, retrying it when that is safe, and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	maxRetries := c.maxRetries
+	if method == http.MethodPost && !c.retryPOST {
+		maxRetries = 0
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.retryBackoff << (attempt - 1)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		retryable, err := c.attempt(ctx, method, path, payload, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return lastErr
+}
+
+// attempt performs a single HTTP round trip and reports whether a failure may be retried
+func (c *Client) attempt(ctx context.Context, method, path string, payload []byte, out interface{}) (bool, error) {
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// Ask for the legacy ErrorResponse shape so errors decode into models.ErrorResponse
+	req.Header.Set("Accept", "application/json")
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to call forecast API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(respBody, &apiErr.Response); err != nil || apiErr.Response.Error == "" {
+			apiErr.Response = models.ErrorResponse{
+				Error:   http.StatusText(resp.StatusCode),
+				Message: string(respBody),
+				Code:    resp.StatusCode,
+			}
+		}
+		return isRetryableStatus(resp.StatusCode), apiErr
+	}
+
+	if out == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return false, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return false, nil
+}
+
+// isRetryableStatus reports whether a status code indicates a transient failure
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// withQuery appends encoded query parameters to a path
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// IsErrorCode reports whether err is an APIError with the given machine-readable code
+func IsErrorCode(err error, errorCode string) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Response.ErrorCode == errorCode
+}
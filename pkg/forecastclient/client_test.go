@@ -0,0 +1,308 @@
+package forecastclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/api"
+	"github.com/dalfonso89/financial-forecasting-service/config"
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
+	"github.com/dalfonso89/financial-forecasting-service/service"
+)
+
+// newTestAPI starts the real router backed by a fake currency exchange service
+func newTestAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	currencyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85,"GBP":0.73},"provider":"test"}`))
+	}))
+	t.Cleanup(currencyServer.Close)
+
+	cfg := &config.Config{
+		CurrencyExchangeServiceURL: currencyServer.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+		DefaultForecastPeriods:     5,
+		SupportedCurrencies:        []string{"USD", "EUR", "GBP", "JPY"},
+	}
+	loggerInstance := logger.New("error")
+	handlers := api.NewHandlers(api.HandlerConfig{
+		Logger:             loggerInstance,
+		ForecastingService: service.NewForecastingService(cfg, loggerInstance),
+		Config:             cfg,
+	})
+
+	server := httptest.NewServer(handlers.SetupRoutes())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Endpoints(t *testing.T) {
+	server := newTestAPI(t)
+	client := New(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	forecast, err := client.GenerateForecast(ctx, &models.ForecastRequest{
+		BaseCurrency:   "USD",
+		TargetCurrency: "EUR",
+		Amount:         1000,
+		Periods:        3,
+		ForecastType:   "linear",
+	})
+	if err != nil {
+		t.Fatalf("GenerateForecast returned error: %v", err)
+	}
+	if forecast.CurrentRate != 0.85 || len(forecast.Forecasts) != 3 {
+		t.Errorf("Unexpected forecast: %+v", forecast)
+	}
+
+	forecasts, err := client.ListForecasts(ctx, ListOptions{Pair: "USD/EUR", Limit: 10})
+	if err != nil {
+		t.Fatalf("ListForecasts returned error: %v", err)
+	}
+	if len(forecasts.Forecasts) != 1 || forecasts.Forecasts[0].ForecastID != forecast.ForecastID {
+		t.Errorf("Unexpected forecast list: %+v", forecasts)
+	}
+
+	stored, err := client.GetForecast(ctx, forecast.ForecastID)
+	if err != nil {
+		t.Fatalf("GetForecast returned error: %v", err)
+	}
+	if stored.ForecastID != forecast.ForecastID || len(stored.Forecasts) != 3 {
+		t.Errorf("Unexpected stored forecast: %+v", stored)
+	}
+	if _, err := client.GetForecast(ctx, "unknown"); !IsErrorCode(err, service.CodeForecastNotFound) {
+		t.Errorf("Expected forecast_not_found error, got %v", err)
+	}
+
+	// No forecast period has come due yet, so nothing has been scored
+	accuracy, err := client.Accuracy(ctx, "USD/EUR", "linear")
+	if err != nil {
+		t.Fatalf("Accuracy returned error: %v", err)
+	}
+	if accuracy.WindowDays != 30 || len(accuracy.Stats) != 0 {
+		t.Errorf("Unexpected accuracy report: %+v", accuracy)
+	}
+
+	multi, err := client.MultiCurrency(ctx, &models.MultiCurrencyForecastRequest{
+		BaseCurrency: "USD",
+		Currencies:   []string{"EUR", "GBP"},
+		Amount:       1000,
+		Periods:      2,
+	})
+	if err != nil {
+		t.Fatalf("MultiCurrency returned error: %v", err)
+	}
+	if len(multi.Currencies) != 2 {
+		t.Errorf("Expected 2 currencies, got %d", len(multi.Currencies))
+	}
+
+	portfolio, err := client.Portfolio(ctx, &models.PortfolioForecastRequest{
+		ReportingCurrency: "USD",
+		Holdings:          []models.Holding{{Currency: "USD", Amount: 100}, {Currency: "EUR", Amount: 85}},
+		Periods:           2,
+	})
+	if err != nil {
+		t.Fatalf("Portfolio returned error: %v", err)
+	}
+	if len(portfolio.Totals) != 2 || !portfolio.CurrentValue.Equal(money.NewFromInt(200)) {
+		t.Errorf("Unexpected portfolio forecast: %+v", portfolio)
+	}
+
+	scenario, err := client.Scenario(ctx, &models.ScenarioForecastRequest{
+		BaseCurrency: "USD",
+		Currencies:   []string{"EUR"},
+		Amount:       1000,
+		Periods:      2,
+		Shocks:       []models.Shock{{Currency: "EUR", Type: "jump", ChangePercent: -10}},
+	})
+	if err != nil {
+		t.Fatalf("Scenario returned error: %v", err)
+	}
+	if len(scenario.Totals) != 2 || scenario.Totals[1].Impact.Sign() >= 0 {
+		t.Errorf("Unexpected scenario forecast: %+v", scenario)
+	}
+
+	scenarios, err := client.StressScenarios(ctx)
+	if err != nil {
+		t.Fatalf("StressScenarios returned error: %v", err)
+	}
+	if len(scenarios) == 0 || scenarios[0].Name == "" {
+		t.Errorf("Unexpected stress scenarios: %+v", scenarios)
+	}
+
+	// The test API has no interest rate curves to price forwards from
+	if _, err := client.Hedging(ctx, &models.HedgingRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1000}); !IsErrorCode(err, service.CodeCurveNotFound) {
+		t.Errorf("Expected curve_not_found error, got %v", err)
+	}
+
+	risk, err := client.ValueAtRisk(ctx, &models.ValueAtRiskRequest{
+		ReportingCurrency: "USD",
+		Positions:         []models.Holding{{Currency: "EUR", Amount: 85}},
+		Methods:           []string{"parametric"},
+		HorizonDays:       []int{1},
+	})
+	if err != nil {
+		t.Fatalf("ValueAtRisk returned error: %v", err)
+	}
+	if len(risk.Measures) != 2 || risk.Measures[0].Method != "parametric" {
+		t.Errorf("Unexpected value at risk: %+v", risk)
+	}
+
+	trend, err := client.AnalyzeTrend(ctx, "USD", "GBP", 10)
+	if err != nil {
+		t.Fatalf("AnalyzeTrend returned error: %v", err)
+	}
+	if trend.CurrencyPair != "USD/GBP" || trend.AnalysisPeriod != 10 {
+		t.Errorf("Unexpected trend analysis: %+v", trend)
+	}
+
+	latest, err := client.Latest(ctx, "USD", "EUR", LatestOptions{Amount: 500, Periods: 4, ForecastType: "exponential"})
+	if err != nil {
+		t.Fatalf("Latest returned error: %v", err)
+	}
+	if !latest.Amount.Equal(money.NewFromInt(500)) || latest.ForecastType != "exponential" || len(latest.Forecasts) != 4 {
+		t.Errorf("Unexpected latest forecast: %+v", latest)
+	}
+
+	if err := client.ClearCache(ctx); err != nil {
+		t.Fatalf("ClearCache returned error: %v", err)
+	}
+
+	currencies, err := client.Currencies(ctx)
+	if err != nil {
+		t.Fatalf("Currencies returned error: %v", err)
+	}
+	if len(currencies) != 4 || currencies[0].Code != "USD" || currencies[0].Name != "US Dollar" {
+		t.Errorf("Expected 4 currencies starting with USD, got %+v", currencies)
+	}
+
+	currency, err := client.Currency(ctx, "jpy")
+	if err != nil {
+		t.Fatalf("Currency returned error: %v", err)
+	}
+	if currency.Code != "JPY" || currency.MinorUnits != 0 || !currency.Supported {
+		t.Errorf("Unexpected currency: %+v", currency)
+	}
+	if _, err := client.Currency(ctx, "XYZ"); !IsErrorCode(err, "currency_not_found") {
+		t.Errorf("Expected currency_not_found error, got %v", err)
+	}
+
+	// The test API runs no snapshot job, so it has no rate history to compute returns from
+	if _, err := client.Correlation(ctx, "USD", CorrelationOptions{WindowDays: 30, Method: "ewma", Lambda: 0.97}); !IsErrorCode(err, service.CodeInsufficientHistory) {
+		t.Errorf("Expected insufficient_history error, got %v", err)
+	}
+}
+
+func TestClient_DecodesErrorResponse(t *testing.T) {
+	server := newTestAPI(t)
+	client := New(Config{BaseURL: server.URL})
+
+	_, err := client.GenerateForecast(context.Background(), &models.ForecastRequest{
+		BaseCurrency:   "XXX",
+		TargetCurrency: "EUR",
+		Amount:         1000,
+	})
+	if err == nil {
+		t.Fatal("Expected error for unsupported currency, got nil")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", apiErr.StatusCode)
+	}
+	if !IsErrorCode(err, service.CodeUnsupportedCurrency) {
+		t.Errorf("Expected error code %s, got %s", service.CodeUnsupportedCurrency, apiErr.Response.ErrorCode)
+	}
+}
+
+func TestClient_RetriesAndAuth(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-API-Key") != "key" {
+			t.Errorf("Expected auth headers, got %v", r.Header)
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"currencies":[{"code":"USD","numeric":"840","name":"US Dollar","minor_units":2,"symbol":"$","countries":["US"],"supported":true}]}`))
+	}))
+	defer server.Close()
+
+	client := New(Config{
+		BaseURL:      server.URL,
+		AuthToken:    "secret",
+		Headers:      map[string]string{"X-API-Key": "key"},
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+
+	currencies, err := client.Currencies(context.Background())
+	if err != nil {
+		t.Fatalf("Expected retries to succeed, got %v", err)
+	}
+	if len(currencies) != 1 || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("Expected 3 calls and one currency, got %d calls and %v", calls, currencies)
+	}
+}
+
+func TestClient_RetriesPOSTOnlyWhenEnabled(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1)%2 == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"forecast_id":"0190b3a5-0000-7000-8000-000000000000","base_currency":"USD","target_currency":"EUR"}`))
+	}))
+	defer server.Close()
+
+	request := &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1000}
+
+	// The gateway may have failed after the server stored the forecast, so a retry could store it twice
+	client := New(Config{BaseURL: server.URL, MaxRetries: 2, RetryBackoff: time.Millisecond})
+	if _, err := client.GenerateForecast(context.Background(), request); err == nil {
+		t.Error("Expected the 502 to be returned, got nil")
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Expected a single call, got %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	client = New(Config{BaseURL: server.URL, MaxRetries: 2, RetryBackoff: time.Millisecond, RetryPOST: true})
+	if _, err := client.GenerateForecast(context.Background(), request); err != nil {
+		t.Errorf("Expected the retry to succeed, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid request","message":"bad","code":400,"error_code":"invalid_request"}`))
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL, MaxRetries: 3, RetryBackoff: time.Millisecond})
+	if err := client.ClearCache(context.Background()); !IsErrorCode(err, "invalid_request") {
+		t.Errorf("Expected invalid_request error, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Expected a single call, got %d", calls)
+	}
+}
@@ -0,0 +1,196 @@
+package ratehistory
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// errClosed is returned by a FileStore after Close
+var errClosed = errors.New("rate history store is closed")
+
+// FileStore keeps rate history in an append-only JSON lines file, indexed in memory; when a day is recorded
+// more than once, the last line wins, and the superseded lines are dropped the next time the file is opened
+type FileStore struct {
+	path  string
+	index *index
+
+	// mu serializes appends so each record is written as one line
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileStore opens or creates the rate history file at path and loads its records
+func OpenFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create rate history directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rate history file: %w", err)
+	}
+
+	store := &FileStore{path: path, index: newIndex(), file: file}
+	if err := store.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// load indexes every complete record and positions the file for appending;
+// a partial last line left by a crash mid-write is truncated, and a file with superseded records is compacted
+func (s *FileStore) load() error {
+	reader := bufio.NewReader(s.file)
+	var offset int64
+	records := 0
+	for line := 1; ; line++ {
+		record, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(record) > 0 {
+				if err := s.file.Truncate(offset); err != nil {
+					return fmt.Errorf("failed to truncate partial rate history record: %w", err)
+				}
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read rate history file: %w", err)
+		}
+		offset += int64(len(record))
+
+		if record = bytes.TrimSpace(record); len(record) == 0 {
+			continue
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(record, &snapshot); err != nil {
+			return fmt.Errorf("invalid rate history record at %s:%d: %w", s.path, line, err)
+		}
+		if _, err := time.Parse(dateLayout, snapshot.Date); err != nil || snapshot.Base == "" {
+			return fmt.Errorf("invalid rate history record at %s:%d: base and a YYYY-MM-DD date are required", s.path, line)
+		}
+		s.index.add(snapshot)
+		records++
+	}
+
+	if records > s.index.count() {
+		return s.compact()
+	}
+	if _, err := s.file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek rate history file: %w", err)
+	}
+	return nil
+}
+
+// compact rewrites the file with one record per base and date, replacing it atomically so a crash
+// part way through leaves the original file in place
+func (s *FileStore) compact() error {
+	var buffer bytes.Buffer
+	for _, snapshot := range s.index.dated("", "9999-12-31") {
+		record, err := json.Marshal(snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to encode rate snapshot: %w", err)
+		}
+		buffer.Write(record)
+		buffer.WriteByte('\n')
+	}
+
+	temp := s.path + ".tmp"
+	if err := os.WriteFile(temp, buffer.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write compacted rate history file: %w", err)
+	}
+	file, err := os.OpenFile(temp, os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open compacted rate history file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync compacted rate history file: %w", err)
+	}
+	if err := os.Rename(temp, s.path); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to replace rate history file: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to seek rate history file: %w", err)
+	}
+
+	s.file.Close()
+	s.file = file
+	return nil
+}
+
+// Record implements Store; the record is synced to disk before Record returns. A snapshot with the same rates as the
+// one already recorded for its base and date is not written again.
+func (s *FileStore) Record(ctx context.Context, snapshot Snapshot) error {
+	record, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode rate snapshot: %w", err)
+	}
+	record = append(record, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return errClosed
+	}
+	if s.index.holds(snapshot) {
+		return nil
+	}
+	if _, err := s.file.Write(record); err != nil {
+		return fmt.Errorf("failed to write rate history record: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync rate history file: %w", err)
+	}
+
+	s.index.add(snapshot)
+	return nil
+}
+
+// Snapshots implements Store
+func (s *FileStore) Snapshots(ctx context.Context, from, to time.Time) ([]Snapshot, error) {
+	return s.index.between(from, to), nil
+}
+
+// Ping implements Store by checking that the rate history file is still open and present
+func (s *FileStore) Ping(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return errClosed
+	}
+	if _, err := os.Stat(s.path); err != nil {
+		return fmt.Errorf("rate history file unavailable: %w", err)
+	}
+	return nil
+}
+
+// Close implements Store
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// Open returns a FileStore for path, or a MemoryStore when path is empty
+func Open(path string) (Store, error) {
+	if path == "" {
+		return NewMemoryStore(), nil
+	}
+	return OpenFileStore(path)
+}
@@ -0,0 +1,131 @@
+package ratehistory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "rates.jsonl")
+	ctx := context.Background()
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-01", Rates: map[string]float64{"EUR": 0.90}})
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-01", Rates: map[string]float64{"EUR": 0.91}})
+	if err := store.Close(); err != nil {
+		t.Fatalf("Expected no error closing, got %v", err)
+	}
+	if err := store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-02"}); err == nil {
+		t.Error("Expected an error recording after Close")
+	}
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error reopening, got %v", err)
+	}
+	defer reopened.Close()
+
+	snapshots, _ := reopened.Snapshots(ctx, day(1), day(31))
+	if len(snapshots) != 1 || snapshots[0].Rates["EUR"] != 0.91 {
+		t.Errorf("Expected the last snapshot of the day to win, got %+v", snapshots)
+	}
+}
+
+func TestFileStore_CompactsOnLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	ctx := context.Background()
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, rate := range []float64{0.90, 0.91, 0.92} {
+		store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-01", Rates: map[string]float64{"EUR": rate}})
+	}
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-02", Rates: map[string]float64{"EUR": 0.93}})
+	store.Close()
+
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error reopening, got %v", err)
+	}
+	defer store.Close()
+	data, _ := os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.Contains(lines[0], "0.92") {
+		t.Fatalf("Expected one record per day, got:\n%s", data)
+	}
+
+	// The compacted file is still appended to
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-03", Rates: map[string]float64{"EUR": 0.94}})
+	data, _ = os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 || !strings.Contains(lines[2], `"2024-03-03"`) {
+		t.Errorf("Expected the new record after the compacted ones, got:\n%s", data)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Expected no temporary file left behind, got %v", err)
+	}
+}
+
+func TestFileStore_SkipsUnchangedSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	ctx := context.Background()
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-01", Rates: map[string]float64{"EUR": 0.9, "GBP": 0.8}}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-01", Rates: map[string]float64{"EUR": 0.9}})
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Errorf("Expected unchanged snapshots to be written once, got:\n%s", data)
+	}
+}
+
+func TestFileStore_TruncatesPartialRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	ctx := context.Background()
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-01", Rates: map[string]float64{"EUR": 0.9}})
+	store.Close()
+
+	// Simulate a crash part way through writing a record
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	file.WriteString(`{"base":"USD","da`)
+	file.Close()
+
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Expected partial record to be dropped, got %v", err)
+	}
+	defer store.Close()
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-02", Rates: map[string]float64{"EUR": 0.92}})
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.Contains(lines[1], `"2024-03-02"`) {
+		t.Errorf("Expected the new record to replace the partial one, got:\n%s", data)
+	}
+}
+
+func TestFileStore_RejectsInvalidRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	os.WriteFile(path, []byte(`{"base":"USD","date":"March 1","rates":{}}`+"\n"), 0o600)
+
+	if _, err := OpenFileStore(path); err == nil || !strings.Contains(err.Error(), ":1") {
+		t.Errorf("Expected an error naming line 1, got %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package ratehistory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// dateLayout is the layout of Snapshot.Date
+const dateLayout = "2006-01-02"
+
+// Snapshot holds the rates quoted against one base currency on one UTC day
+type Snapshot struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`  // YYYY-MM-DD in UTC
+	Rates map[string]float64 `json:"rates"` // Units of each currency per unit of Base
+}
+
+// Point holds the rates of several currencies against a common base on one day
+type Point struct {
+	Date  string
+	Rates map[string]float64 // Units of each currency per unit of the base; the base itself is 1
+}
+
+// Store keeps one snapshot per base currency and day; a later snapshot for the same day replaces the earlier one,
+// so the stored rates are the last quotes seen that day
+type Store interface {
+	// Record stores a snapshot, replacing any earlier one for the same base and date
+	Record(ctx context.Context, snapshot Snapshot) error
+	// Snapshots returns every snapshot dated from from to to inclusive, ordered by date and base
+	Snapshots(ctx context.Context, from, to time.Time) ([]Snapshot, error)
+	// Ping reports whether the store can accept writes
+	Ping(ctx context.Context) error
+	// Close releases the store's resources
+	Close() error
+}
+
+// DateOf returns the snapshot date of an instant
+func DateOf(t time.Time) string {
+	return t.UTC().Format(dateLayout)
+}
+
+// Series returns one point per day from from to to with the rates of every recorded currency against base.
+// Days without a snapshot for base are filled by triangulating through another base quoted that day; days where
+// base cannot be priced are skipped.
+func Series(ctx context.Context, store Store, base string, from, to time.Time) ([]Point, error) {
+	snapshots, err := store.Snapshots(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []Point
+	for start := 0; start < len(snapshots); {
+		end := start
+		for end < len(snapshots) && snapshots[end].Date == snapshots[start].Date {
+			end++
+		}
+		if rates := crossRates(snapshots[start:end], base); rates != nil {
+			points = append(points, Point{Date: snapshots[start].Date, Rates: rates})
+		}
+		start = end
+	}
+	return points, nil
+}
+
+// crossRates combines one day's snapshots into rates against base, preferring direct quotes
+func crossRates(day []Snapshot, base string) map[string]float64 {
+	sorted := append([]Snapshot(nil), day...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Base == base && sorted[j].Base != base })
+
+	var rates map[string]float64
+	for _, snapshot := range sorted {
+		// Units of base per unit of the snapshot's base
+		pivot := 1.0
+		if snapshot.Base != base {
+			quote, ok := snapshot.Rates[base]
+			if !ok || quote <= 0 {
+				continue
+			}
+			pivot = quote
+		}
+		if rates == nil {
+			rates = map[string]float64{base: 1}
+		}
+		if _, ok := rates[snapshot.Base]; !ok {
+			rates[snapshot.Base] = 1 / pivot
+		}
+		for currency, rate := range snapshot.Rates {
+			if _, ok := rates[currency]; !ok && rate > 0 {
+				rates[currency] = rate / pivot
+			}
+		}
+	}
+	return rates
+}
+
+// index keeps snapshots in memory keyed by date and base
+type index struct {
+	mu        sync.RWMutex
+	snapshots map[string]Snapshot
+}
+
+// newIndex creates an empty index
+func newIndex() *index {
+	return &index{snapshots: make(map[string]Snapshot)}
+}
+
+// add inserts or replaces a snapshot, copying its rates
+func (idx *index) add(snapshot Snapshot) {
+	rates := make(map[string]float64, len(snapshot.Rates))
+	for currency, rate := range snapshot.Rates {
+		rates[currency] = rate
+	}
+	snapshot.Rates = rates
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.snapshots[snapshot.Date+"/"+snapshot.Base] = snapshot
+}
+
+// holds reports whether the index already has a snapshot for the same base and date with the same rates
+func (idx *index) holds(snapshot Snapshot) bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	stored, ok := idx.snapshots[snapshot.Date+"/"+snapshot.Base]
+	if !ok || len(stored.Rates) != len(snapshot.Rates) {
+		return false
+	}
+	for currency, rate := range snapshot.Rates {
+		if storedRate, ok := stored.Rates[currency]; !ok || storedRate != rate {
+			return false
+		}
+	}
+	return true
+}
+
+// count returns the number of snapshots in the index
+func (idx *index) count() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.snapshots)
+}
+
+// between returns the snapshots dated from from to to inclusive, ordered by date and base
+func (idx *index) between(from, to time.Time) []Snapshot {
+	return idx.dated(DateOf(from), DateOf(to))
+}
+
+// dated returns the snapshots whose dates fall from first to last inclusive, ordered by date and base
+func (idx *index) dated(first, last string) []Snapshot {
+	idx.mu.RLock()
+	snapshots := make([]Snapshot, 0, len(idx.snapshots))
+	for _, snapshot := range idx.snapshots {
+		if snapshot.Date >= first && snapshot.Date <= last {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Date != snapshots[j].Date {
+			return snapshots[i].Date < snapshots[j].Date
+		}
+		return snapshots[i].Base < snapshots[j].Base
+	})
+	return snapshots
+}
+
+// MemoryStore keeps rate history in memory; it is lost on restart
+type MemoryStore struct {
+	index *index
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{index: newIndex()}
+}
+
+// Record implements Store
+func (s *MemoryStore) Record(ctx context.Context, snapshot Snapshot) error {
+	s.index.add(snapshot)
+	return nil
+}
+
+// Snapshots implements Store
+func (s *MemoryStore) Snapshots(ctx context.Context, from, to time.Time) ([]Snapshot, error) {
+	return s.index.between(from, to), nil
+}
+
+// Ping implements Store
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close implements Store
+func (s *MemoryStore) Close() error {
+	return nil
+}
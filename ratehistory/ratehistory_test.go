@@ -0,0 +1,75 @@
+package ratehistory
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+// day returns midnight UTC of a day in March 2024
+func day(d int) time.Time {
+	return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestMemoryStore_RecordReplacesSameDay(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-01", Rates: map[string]float64{"EUR": 0.90}})
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-01", Rates: map[string]float64{"EUR": 0.91}})
+	store.Record(ctx, Snapshot{Base: "EUR", Date: "2024-03-01", Rates: map[string]float64{"USD": 1.1}})
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-02", Rates: map[string]float64{"EUR": 0.92}})
+
+	snapshots, err := store.Snapshots(ctx, day(1), day(1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Base != "EUR" || snapshots[1].Rates["EUR"] != 0.91 {
+		t.Errorf("Expected the EUR snapshot and the last USD snapshot of the day, got %+v", snapshots)
+	}
+}
+
+func TestSeries(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	store.Record(ctx, Snapshot{Base: "USD", Date: "2024-03-01", Rates: map[string]float64{"EUR": 0.9, "GBP": 0.8}})
+	store.Record(ctx, Snapshot{Base: "EUR", Date: "2024-03-02", Rates: map[string]float64{"USD": 1.25, "GBP": 0.85}})
+	store.Record(ctx, Snapshot{Base: "GBP", Date: "2024-03-03", Rates: map[string]float64{"JPY": 190}})
+
+	tests := []struct {
+		name   string
+		base   string
+		date   string
+		target string
+		rate   float64
+	}{
+		{"direct quote", "USD", "2024-03-01", "EUR", 0.9},
+		{"inverse of the snapshot base", "USD", "2024-03-02", "EUR", 0.8},
+		{"triangulated", "USD", "2024-03-02", "GBP", 0.68},
+		{"cross on a day without the base", "EUR", "2024-03-01", "GBP", 0.8 / 0.9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := Series(ctx, store, tt.base, day(1), day(3))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for _, point := range points {
+				if point.Date != tt.date {
+					continue
+				}
+				if math.Abs(point.Rates[tt.target]-tt.rate) > 1e-12 {
+					t.Errorf("Expected %s/%s %v on %s, got %v", tt.base, tt.target, tt.rate, tt.date, point.Rates[tt.target])
+				}
+				return
+			}
+			t.Errorf("Expected a point on %s, got %+v", tt.date, points)
+		})
+	}
+
+	// USD cannot be priced on the 3rd, when only GBP/JPY was quoted
+	points, _ := Series(ctx, store, "USD", day(1), day(3))
+	if len(points) != 2 {
+		t.Errorf("Expected 2 points, got %+v", points)
+	}
+}
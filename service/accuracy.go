@@ -0,0 +1,510 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+	"github.com/dalfonso89/financial-forecasting-service/history"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+)
+
+// defaultAccuracyWindow is used when the configuration sets no rolling window
+const defaultAccuracyWindow = 30 * 24 * time.Hour
+
+// accuracyObservation compares one forecast period with the rate realized on its date
+type accuracyObservation struct {
+	forecastID   string
+	pair         string
+	forecastType string
+	frequency    string
+	horizon      int
+	forecast     float64
+	actual       float64
+	observedAt   time.Time // Midnight UTC of the date whose close was compared
+}
+
+// pendingPeriod is a stored forecast period waiting to be scored against the close of its date
+type pendingPeriod struct {
+	base        string
+	target      string
+	date        string              // YYYY-MM-DD; hourly periods are scored against the close of their day
+	observation accuracyObservation // Everything but the actual rate and its date
+}
+
+// accuracyTracker accumulates forecast errors over a rolling window
+type accuracyTracker struct {
+	mu           sync.Mutex
+	observations []accuracyObservation
+	evaluated    map[string]time.Time       // forecast_id/period to the date it was observed on
+	pending      map[string][]pendingPeriod // Period date to the stored forecast periods due on it
+	indexed      map[string]indexedForecast // forecast_id to how far its periods are in pending
+	scanned      bool                       // Whether forecasts stored before the service started are indexed
+}
+
+// indexedForecast tracks a stored forecast whose periods are in the pending index. Only periods dated within one
+// accuracy window of today are indexed; later ones are read back from the history as the window reaches them.
+type indexedForecast struct {
+	lastDate string // Date of the forecast's last period; the entry is dropped once it falls out of the window
+	nextDate string // Date of the first period not yet indexed, or empty when every period is
+}
+
+// newAccuracyTracker creates an empty tracker
+func newAccuracyTracker() *accuracyTracker {
+	return &accuracyTracker{
+		evaluated: make(map[string]time.Time),
+		pending:   make(map[string][]pendingPeriod),
+		indexed:   make(map[string]indexedForecast),
+	}
+}
+
+// SetAccuracyLog replaces the log accuracy observations are persisted to and restores the observations it holds within
+// the rolling window, so periods scored before a restart are reported and not scored again; call it before the service
+// starts tracking accuracy
+func (fs *ForecastingService) SetAccuracyLog(ctx context.Context, log history.ObservationLog) error {
+	cutoff := fs.accuracyCutoff(fs.clock.Now())
+	records, err := log.Since(ctx, ratehistory.DateOf(cutoff))
+	if err != nil {
+		return err
+	}
+	observations := make([]accuracyObservation, 0, len(records))
+	for _, record := range records {
+		observedAt, err := time.Parse("2006-01-02", record.Date)
+		if err != nil {
+			continue
+		}
+		observations = append(observations, accuracyObservation{
+			forecastID:   record.ForecastID,
+			pair:         record.Pair,
+			forecastType: record.ForecastType,
+			frequency:    record.Frequency,
+			horizon:      record.Period,
+			forecast:     record.Forecast,
+			actual:       record.Actual,
+			observedAt:   observedAt,
+		})
+	}
+	fs.accuracyLog = log
+	fs.accuracy.record(observations, cutoff)
+	return nil
+}
+
+// EvaluateAccuracy indexes the periods of forecasts stored before the service started on its first run, then compares
+// every period dated before today with that date's close in the rate history, and returns how many were observed.
+// Periods missed while the service was down, or whose close was not yet recorded, are caught up on later runs until
+// they fall out of the rolling window.
+func (fs *ForecastingService) EvaluateAccuracy(ctx context.Context) (int, error) {
+	now := fs.clock.Now()
+	cutoff := fs.accuracyCutoff(now)
+	cutoffDate, untilDate := ratehistory.DateOf(cutoff), fs.accuracyIndexHorizon(now)
+	if err := fs.indexStoredForecasts(ctx, cutoffDate, untilDate); err != nil {
+		return 0, err
+	}
+	if err := fs.indexLaterPeriods(ctx, cutoffDate, untilDate); err != nil {
+		return 0, err
+	}
+	due := fs.accuracy.due(now, cutoffDate)
+
+	// Read each base currency's closes once, from its earliest due date
+	earliest := make(map[string]string)
+	for _, period := range due {
+		if date, exists := earliest[period.base]; !exists || period.date < date {
+			earliest[period.base] = period.date
+		}
+	}
+	closes := make(map[string]map[string]map[string]float64)
+	var firstErr error
+	for base, date := range earliest {
+		from, _ := time.Parse("2006-01-02", date)
+		points, err := ratehistory.Series(ctx, fs.rateHistory, base, from, now)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to read rate history: %w", err)
+			}
+			continue
+		}
+		closes[base] = make(map[string]map[string]float64, len(points))
+		for _, point := range points {
+			closes[base][point.Date] = point.Rates
+		}
+	}
+
+	var observed []accuracyObservation
+	for _, period := range due {
+		actual, ok := closes[period.base][period.date][period.target]
+		if !ok || actual <= 0 {
+			continue
+		}
+		observation := period.observation
+		observation.actual = actual
+		observation.observedAt, _ = time.Parse("2006-01-02", period.date)
+		observed = append(observed, observation)
+	}
+
+	// Scores that fail to persist are kept in memory, and scored again after a restart
+	if err := fs.accuracyLog.Append(ctx, observationRecords(observed)); err != nil {
+		fs.logger.WithContext(ctx).Errorf("Failed to persist %d forecast accuracy observations: %v", len(observed), err)
+	}
+	fs.accuracy.record(observed, cutoff)
+	metrics.ForecastAccuracyObservations.Add(float64(len(observed)))
+	return len(observed), firstErr
+}
+
+// indexStoredForecasts adds the periods of every stored forecast to the pending index, once; forecasts saved while the
+// service runs are indexed by saveForecast
+func (fs *ForecastingService) indexStoredForecasts(ctx context.Context, cutoffDate, untilDate string) error {
+	if fs.accuracy.storedForecastsIndexed() {
+		return nil
+	}
+	filter := history.Filter{Limit: history.MaxLimit}
+	for {
+		page, err := fs.history.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for _, forecast := range page.Forecasts {
+			fs.accuracy.addPending(forecast, cutoffDate, untilDate)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	fs.accuracy.markStoredForecastsIndexed()
+	return nil
+}
+
+// indexLaterPeriods reads back the forecasts with periods the index horizon has reached since they were indexed and
+// adds those periods to the pending index
+func (fs *ForecastingService) indexLaterPeriods(ctx context.Context, cutoffDate, untilDate string) error {
+	for _, id := range fs.accuracy.reached(untilDate) {
+		forecast, err := fs.history.Get(ctx, id)
+		if errors.Is(err, history.ErrNotFound) {
+			// Evicted from the history; its remaining periods cannot be scored
+			fs.accuracy.forget(id)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fs.accuracy.addPending(*forecast, cutoffDate, untilDate)
+	}
+	return nil
+}
+
+// saveForecast records a forecast in the history and queues its periods for accuracy scoring
+func (fs *ForecastingService) saveForecast(ctx context.Context, forecast *models.ForecastResponse) error {
+	if err := fs.history.Save(ctx, forecast); err != nil {
+		return err
+	}
+	now := fs.clock.Now()
+	fs.accuracy.addPending(*forecast, ratehistory.DateOf(fs.accuracyCutoff(now)), fs.accuracyIndexHorizon(now))
+	return nil
+}
+
+// observationRecords converts observations to their persisted form
+func observationRecords(observations []accuracyObservation) []history.Observation {
+	records := make([]history.Observation, len(observations))
+	for i, observation := range observations {
+		records[i] = history.Observation{
+			ForecastID:   observation.forecastID,
+			Period:       observation.horizon,
+			Pair:         observation.pair,
+			ForecastType: observation.forecastType,
+			Frequency:    observation.frequency,
+			Date:         ratehistory.DateOf(observation.observedAt),
+			Forecast:     observation.forecast,
+			Actual:       observation.actual,
+		}
+	}
+	return records
+}
+
+// RunAccuracyTracking evaluates forecast accuracy immediately and then every interval until ctx is done
+func (fs *ForecastingService) RunAccuracyTracking(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		observed, err := fs.EvaluateAccuracy(ctx)
+		if err != nil {
+			fs.logger.Warnf("Forecast accuracy evaluation incomplete: %v", err)
+		}
+		if observed > 0 {
+			fs.logger.Infof("Recorded %d forecast accuracy observations", observed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AccuracyReport summarizes forecast errors over the rolling window, optionally for one pair or forecast type
+func (fs *ForecastingService) AccuracyReport(pair, forecastType string) *models.AccuracyReport {
+	pair = currency.Normalize(pair)
+	window := fs.accuracyWindow()
+	now := fs.clock.Now()
+	cutoff := fs.accuracyCutoff(now)
+	report := &models.AccuracyReport{
+		WindowDays:  int(window / (24 * time.Hour)),
+		GeneratedAt: now,
+		Stats:       []models.AccuracyStats{},
+	}
+
+	type groupKey struct {
+		pair, forecastType, frequency string
+		horizon                       int
+	}
+	groups := make(map[groupKey]*models.AccuracyStats)
+	for _, observation := range fs.accuracy.since(cutoff) {
+		if (pair != "" && observation.pair != pair) || (forecastType != "" && observation.forecastType != forecastType) {
+			continue
+		}
+		key := groupKey{observation.pair, observation.forecastType, observation.frequency, observation.horizon}
+		stats, exists := groups[key]
+		if !exists {
+			stats = &models.AccuracyStats{Pair: key.pair, ForecastType: key.forecastType, Frequency: key.frequency, Horizon: key.horizon}
+			groups[key] = stats
+		}
+		relativeError := (observation.forecast - observation.actual) / observation.actual * 100
+		stats.Samples++
+		stats.Bias += relativeError
+		if relativeError < 0 {
+			relativeError = -relativeError
+		}
+		stats.MAPE += relativeError
+	}
+
+	for _, stats := range groups {
+		stats.MAPE /= float64(stats.Samples)
+		stats.Bias /= float64(stats.Samples)
+		report.Stats = append(report.Stats, *stats)
+	}
+	sort.Slice(report.Stats, func(i, j int) bool {
+		a, b := report.Stats[i], report.Stats[j]
+		if a.Pair != b.Pair {
+			return a.Pair < b.Pair
+		}
+		if a.ForecastType != b.ForecastType {
+			return a.ForecastType < b.ForecastType
+		}
+		if a.Frequency != b.Frequency {
+			return a.Frequency < b.Frequency
+		}
+		return a.Horizon < b.Horizon
+	})
+	return report
+}
+
+// accuracyWindow returns the configured rolling window
+func (fs *ForecastingService) accuracyWindow() time.Duration {
+	if window := fs.config.Load().AccuracyWindow; window > 0 {
+		return window
+	}
+	return defaultAccuracyWindow
+}
+
+// storedForecastsIndexed reports whether the forecasts stored before the service started have been indexed
+func (t *accuracyTracker) storedForecastsIndexed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.scanned
+}
+
+// markStoredForecastsIndexed records that the forecasts stored before the service started have been indexed
+func (t *accuracyTracker) markStoredForecastsIndexed() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scanned = true
+}
+
+// addPending indexes a stored forecast's periods dated from cutoffDate up to, but not including, untilDate that have
+// not been scored. A forecast already indexed only has the periods the horizon reached since added.
+func (t *accuracyTracker) addPending(forecast models.ForecastResponse, cutoffDate, untilDate string) {
+	frequency := forecast.Frequency
+	if frequency == "" {
+		frequency = "daily"
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	from := cutoffDate
+	if entry, indexed := t.indexed[forecast.ForecastID]; indexed {
+		if entry.nextDate == "" || entry.nextDate >= untilDate {
+			return
+		}
+		from = max(from, entry.nextDate)
+	}
+	var entry indexedForecast
+	for _, period := range forecast.Forecasts {
+		date := periodDate(period.Date)
+		entry.lastDate = max(entry.lastDate, date)
+		if date >= untilDate {
+			if entry.nextDate == "" || date < entry.nextDate {
+				entry.nextDate = date
+			}
+			continue
+		}
+		if _, evaluated := t.evaluated[evaluationKey(forecast.ForecastID, period.Period)]; evaluated || date < from {
+			continue
+		}
+		t.pending[date] = append(t.pending[date], pendingPeriod{
+			base:   forecast.BaseCurrency,
+			target: forecast.TargetCurrency,
+			date:   date,
+			observation: accuracyObservation{
+				forecastID:   forecast.ForecastID,
+				pair:         forecast.BaseCurrency + "/" + forecast.TargetCurrency,
+				forecastType: forecast.ForecastType,
+				frequency:    frequency,
+				horizon:      period.Period,
+				forecast:     period.Rate,
+			},
+		})
+	}
+	if entry.lastDate >= cutoffDate {
+		t.indexed[forecast.ForecastID] = entry
+	}
+}
+
+// reached returns the indexed forecasts with periods dated before untilDate that are not yet in the pending index
+func (t *accuracyTracker) reached(untilDate string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var ids []string
+	for forecastID, entry := range t.indexed {
+		if entry.nextDate != "" && entry.nextDate < untilDate {
+			ids = append(ids, forecastID)
+		}
+	}
+	return ids
+}
+
+// forget stops indexing the remaining periods of a forecast; those already pending are still scored
+func (t *accuracyTracker) forget(forecastID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if entry, indexed := t.indexed[forecastID]; indexed {
+		entry.nextDate = ""
+		t.indexed[forecastID] = entry
+	}
+}
+
+// periodDate returns the UTC date a forecast period is scored on; hourly periods are RFC 3339 timestamps in the
+// request's timezone and take the close of their UTC day
+func periodDate(date string) string {
+	if len(date) <= len("2006-01-02") {
+		return date
+	}
+	at, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return date[:len("2006-01-02")]
+	}
+	return ratehistory.DateOf(at)
+}
+
+// due returns the pending periods dated before today in UTC, whose closes are final, and drops those already scored or
+// dated before cutoffDate
+func (t *accuracyTracker) due(now time.Time, cutoffDate string) []pendingPeriod {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	today := now.UTC().Format("2006-01-02")
+	var due []pendingPeriod
+	for date, periods := range t.pending {
+		if date >= today {
+			continue
+		}
+		if date < cutoffDate {
+			delete(t.pending, date)
+			continue
+		}
+		kept := periods[:0]
+		for _, period := range periods {
+			if _, evaluated := t.evaluated[evaluationKey(period.observation.forecastID, period.observation.horizon)]; !evaluated {
+				kept = append(kept, period)
+				due = append(due, period)
+			}
+		}
+		if len(kept) == 0 {
+			delete(t.pending, date)
+		} else {
+			t.pending[date] = kept
+		}
+	}
+	// Forecasts whose periods all fell out of the window have nothing left to index
+	for forecastID, entry := range t.indexed {
+		if entry.lastDate < cutoffDate {
+			delete(t.indexed, forecastID)
+		}
+	}
+	return due
+}
+
+// accuracyCutoff returns midnight UTC at the start of the rolling window; observations dated before it are dropped
+func (fs *ForecastingService) accuracyCutoff(now time.Time) time.Time {
+	start := now.Add(-fs.accuracyWindow()).UTC()
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// accuracyIndexHorizon returns the date one rolling window after today in UTC; forecast periods dated from it on are
+// left out of the pending index until the horizon reaches them
+func (fs *ForecastingService) accuracyIndexHorizon(now time.Time) string {
+	return ratehistory.DateOf(now.UTC().Add(fs.accuracyWindow()))
+}
+
+// evaluationKey identifies one period of one stored forecast
+func evaluationKey(forecastID string, period int) string {
+	return forecastID + "/" + strconv.Itoa(period)
+}
+
+// record adds observations, marks their periods as scored and drops everything observed before cutoff
+func (t *accuracyTracker) record(observations []accuracyObservation, cutoff time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	kept := t.observations[:0]
+	for _, observation := range t.observations {
+		if !observation.observedAt.Before(cutoff) {
+			kept = append(kept, observation)
+		}
+	}
+	for _, observation := range observations {
+		if !observation.observedAt.Before(cutoff) {
+			kept = append(kept, observation)
+			t.evaluated[evaluationKey(observation.forecastID, observation.horizon)] = observation.observedAt
+		}
+	}
+	t.observations = kept
+
+	// Periods dated before the window are never indexed again, so older markers can go
+	for key, at := range t.evaluated {
+		if at.Before(cutoff) {
+			delete(t.evaluated, key)
+		}
+	}
+}
+
+// since returns a copy of the observations made at or after cutoff
+func (t *accuracyTracker) since(cutoff time.Time) []accuracyObservation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var result []accuracyObservation
+	for _, observation := range t.observations {
+		if !observation.observedAt.Before(cutoff) {
+			result = append(result, observation)
+		}
+	}
+	return result
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/config"
+	"github.com/dalfonso89/financial-forecasting-service/history"
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+	"github.com/dalfonso89/financial-forecasting-service/uuid"
+)
+
+// accuracyNow is the clock of the accuracy tests; closes up to 2025-03-09 are final
+var accuracyNow = time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// accuracyDate returns the date days before accuracyNow
+func accuracyDate(days int) string {
+	return ratehistory.DateOf(accuracyNow.AddDate(0, 0, -days))
+}
+
+// storedForecast builds a history record with one period per date, in order
+func storedForecast(target, forecastType string, periods ...models.ForecastPeriod) models.ForecastResponse {
+	for i := range periods {
+		periods[i].Period = i + 1
+	}
+	return models.ForecastResponse{
+		ForecastID:     uuid.NewV7(time.Now()),
+		BaseCurrency:   "USD",
+		TargetCurrency: target,
+		ForecastType:   forecastType,
+		Forecasts:      periods,
+		GeneratedAt:    accuracyNow.AddDate(0, 0, -7),
+	}
+}
+
+// recordClose stores a USD snapshot for the date days before accuracyNow
+func recordClose(store ratehistory.Store, days int, rates map[string]float64) {
+	store.Record(context.Background(), ratehistory.Snapshot{Base: "USD", Date: accuracyDate(days), Rates: rates})
+}
+
+// newAccuracyTestService creates a service over the given forecast and rate histories, running at accuracyNow
+func newAccuracyTestService(store history.Store, rates ratehistory.Store) *ForecastingService {
+	cfg := &config.Config{SupportedCurrencies: []string{"USD", "EUR", "GBP"}}
+	service := NewForecastingServiceWithHistory(cfg, logger.New("error"), store)
+	service.SetRateHistory(rates)
+	service.SetClock(ClockFunc(func() time.Time { return accuracyNow }))
+	return service
+}
+
+// wasEvaluated reports whether a forecast period has already been scored
+func (t *accuracyTracker) wasEvaluated(forecastID string, period int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, evaluated := t.evaluated[evaluationKey(forecastID, period)]
+	return evaluated
+}
+
+// pendingPeriods returns how many forecast periods are waiting to be scored
+func (t *accuracyTracker) pendingPeriods() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var count int
+	for _, periods := range t.pending {
+		count += len(periods)
+	}
+	return count
+}
+
+// failingRateHistory is a rate history whose reads fail
+type failingRateHistory struct {
+	*ratehistory.MemoryStore
+}
+
+// Snapshots implements ratehistory.Store
+func (failingRateHistory) Snapshots(ctx context.Context, from, to time.Time) ([]ratehistory.Snapshot, error) {
+	return nil, errors.New("disk unavailable")
+}
+
+func TestForecastingService_EvaluateAccuracy(t *testing.T) {
+	yesterday, today := accuracyDate(1), accuracyDate(0)
+
+	store := history.NewMemoryStore()
+	for _, forecast := range []models.ForecastResponse{
+		storedForecast("EUR", "linear", models.ForecastPeriod{Date: yesterday, Rate: 0.88}),
+		storedForecast("EUR", "linear", models.ForecastPeriod{Date: yesterday, Rate: 0.76}),
+		storedForecast("EUR", "exponential", models.ForecastPeriod{Date: yesterday, Rate: 0.80}, models.ForecastPeriod{Date: today, Rate: 0.81}),
+		storedForecast("JPY", "linear", models.ForecastPeriod{Date: yesterday, Rate: 150}), // not recorded
+	} {
+		forecast := forecast
+		store.Save(context.Background(), &forecast)
+	}
+	rates := ratehistory.NewMemoryStore()
+	recordClose(rates, 1, map[string]float64{"EUR": 0.80})
+	recordClose(rates, 0, map[string]float64{"EUR": 0.90}) // today's close is not final
+	service := newAccuracyTestService(store, rates)
+
+	observed, err := service.EvaluateAccuracy(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if observed != 3 {
+		t.Errorf("Expected 3 periods dated yesterday to be observed, got %d", observed)
+	}
+	if observed, _ := service.EvaluateAccuracy(context.Background()); observed != 0 {
+		t.Errorf("Expected periods to be scored only once, got %d more", observed)
+	}
+
+	report := service.AccuracyReport("", "")
+	if report.WindowDays != 30 {
+		t.Errorf("Expected default window of 30 days, got %d", report.WindowDays)
+	}
+	if len(report.Stats) != 2 {
+		t.Fatalf("Expected stats for 2 pair/model/horizon groups, got %+v", report.Stats)
+	}
+
+	tests := []struct {
+		forecastType string
+		samples      int
+		mape         float64
+		bias         float64
+	}{
+		{"exponential", 1, 0, 0},
+		{"linear", 2, 7.5, 2.5}, // +10% and -5%
+	}
+	for i, tt := range tests {
+		stats := report.Stats[i]
+		if stats.Pair != "USD/EUR" || stats.ForecastType != tt.forecastType || stats.Frequency != "daily" || stats.Horizon != 1 {
+			t.Errorf("Expected USD/EUR %s daily horizon 1, got %+v", tt.forecastType, stats)
+		}
+		if stats.Samples != tt.samples {
+			t.Errorf("Expected %d samples for %s, got %d", tt.samples, tt.forecastType, stats.Samples)
+		}
+		if math.Abs(stats.MAPE-tt.mape) > 1e-9 || math.Abs(stats.Bias-tt.bias) > 1e-9 {
+			t.Errorf("Expected MAPE %.2f and bias %.2f for %s, got %.4f and %.4f", tt.mape, tt.bias, tt.forecastType, stats.MAPE, stats.Bias)
+		}
+	}
+
+	if filtered := service.AccuracyReport("USD/EUR", "linear"); len(filtered.Stats) != 1 || filtered.Stats[0].Samples != 2 {
+		t.Errorf("Expected only the linear group, got %+v", filtered.Stats)
+	}
+	if filtered := service.AccuracyReport("USD/GBP", ""); len(filtered.Stats) != 0 {
+		t.Errorf("Expected no stats for an unscored pair, got %+v", filtered.Stats)
+	}
+}
+
+func TestForecastingService_EvaluateAccuracy_CatchesUp(t *testing.T) {
+	// A forecast whose periods fell on days the job did not run, one of them outside the window
+	forecast := storedForecast("EUR", "linear",
+		models.ForecastPeriod{Date: accuracyDate(40), Rate: 0.80},
+		models.ForecastPeriod{Date: accuracyDate(3), Rate: 0.80},
+		models.ForecastPeriod{Date: accuracyDate(2), Rate: 0.80},
+		models.ForecastPeriod{Date: accuracyDate(1) + "T15:00:00Z", Rate: 0.80}, // hourly periods take the day's close
+	)
+	store := history.NewMemoryStore()
+	store.Save(context.Background(), &forecast)
+
+	rates := ratehistory.NewMemoryStore()
+	for _, days := range []int{40, 3, 1} {
+		recordClose(rates, days, map[string]float64{"EUR": 0.80})
+	}
+	service := newAccuracyTestService(store, rates)
+
+	if observed, err := service.EvaluateAccuracy(context.Background()); err != nil || observed != 2 {
+		t.Fatalf("Expected the 2 recorded days in the window to be observed, got %d and %v", observed, err)
+	}
+	if service.accuracy.wasEvaluated(forecast.ForecastID, 1) {
+		t.Error("Expected the period outside the window not to be scored")
+	}
+
+	// The missing close is scored once it is recorded
+	recordClose(rates, 2, map[string]float64{"EUR": 0.80})
+	if observed, _ := service.EvaluateAccuracy(context.Background()); observed != 1 {
+		t.Errorf("Expected the backfilled day to be observed, got %d", observed)
+	}
+}
+
+func TestForecastingService_EvaluateAccuracy_LongHorizons(t *testing.T) {
+	// A quarterly forecast generated three years ago reaches yesterday in its twelfth period
+	generatedAt := accuracyNow.AddDate(-3, 0, 0)
+	quarterly := storedForecast("EUR", "linear", make([]models.ForecastPeriod, 12)...)
+	quarterly.ForecastID, quarterly.GeneratedAt, quarterly.Frequency = uuid.NewV7(generatedAt), generatedAt, "quarterly"
+	quarterly.Forecasts[11].Date, quarterly.Forecasts[11].Rate = accuracyDate(1), 0.88
+	store := history.NewMemoryStore()
+	store.Save(context.Background(), &quarterly)
+
+	rates := ratehistory.NewMemoryStore()
+	recordClose(rates, 1, map[string]float64{"EUR": 0.80})
+	service := newAccuracyTestService(store, rates)
+	if observed, err := service.EvaluateAccuracy(context.Background()); err != nil || observed != 1 {
+		t.Fatalf("Expected the quarterly period to be observed, got %d and %v", observed, err)
+	}
+	if stats := service.AccuracyReport("", "").Stats; len(stats) != 1 || stats[0].Frequency != "quarterly" || stats[0].Horizon != 12 {
+		t.Errorf("Expected a quarterly horizon 12 group, got %+v", stats)
+	}
+
+	// Forecasts saved after a run are indexed by the next one
+	daily := storedForecast("EUR", "exponential", models.ForecastPeriod{Date: accuracyDate(1), Rate: 0.80})
+	service.saveForecast(context.Background(), &daily)
+	if observed, _ := service.EvaluateAccuracy(context.Background()); observed != 1 {
+		t.Errorf("Expected the new forecast to be observed, got %d", observed)
+	}
+}
+
+func TestForecastingService_EvaluateAccuracy_ForecastSavedOutOfOrder(t *testing.T) {
+	store := history.NewMemoryStore()
+	newer := storedForecast("EUR", "linear", models.ForecastPeriod{Date: accuracyDate(1), Rate: 0.88})
+	newer.ForecastID = uuid.NewV7(accuracyNow)
+	store.Save(context.Background(), &newer)
+
+	rates := ratehistory.NewMemoryStore()
+	recordClose(rates, 1, map[string]float64{"EUR": 0.80, "GBP": 0.70})
+	service := newAccuracyTestService(store, rates)
+	if observed, err := service.EvaluateAccuracy(context.Background()); err != nil || observed != 1 {
+		t.Fatalf("Expected the stored forecast to be observed, got %d and %v", observed, err)
+	}
+
+	// A forecast whose ID sorts before one already scored, as after a clock step back or a save in the same millisecond
+	older := storedForecast("GBP", "linear", models.ForecastPeriod{Date: accuracyDate(1), Rate: 0.77})
+	older.ForecastID = uuid.NewV7(accuracyNow.Add(-time.Hour))
+	if older.ForecastID >= newer.ForecastID {
+		t.Fatalf("Expected %s to sort before %s", older.ForecastID, newer.ForecastID)
+	}
+	if err := service.saveForecast(context.Background(), &older); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+	if observed, err := service.EvaluateAccuracy(context.Background()); err != nil || observed != 1 {
+		t.Fatalf("Expected the out-of-order forecast to be observed, got %d and %v", observed, err)
+	}
+	if !service.accuracy.wasEvaluated(older.ForecastID, 1) {
+		t.Error("Expected the out-of-order forecast to be scored")
+	}
+	if observed, _ := service.EvaluateAccuracy(context.Background()); observed != 0 {
+		t.Errorf("Expected periods to be scored only once, got %d more", observed)
+	}
+}
+
+func TestForecastingService_EvaluateAccuracy_IndexesWithinWindow(t *testing.T) {
+	// A quarterly forecast reaching decades ahead: only the period due in the window is held in memory
+	forecast := storedForecast("EUR", "linear", make([]models.ForecastPeriod, 120)...)
+	forecast.Frequency = "quarterly"
+	for i := range forecast.Forecasts {
+		forecast.Forecasts[i].Date = ratehistory.DateOf(accuracyNow.AddDate(0, 3*i+1, 0))
+		forecast.Forecasts[i].Rate = 0.88
+	}
+	store := history.NewMemoryStore()
+	rates := ratehistory.NewMemoryStore()
+	service := newAccuracyTestService(store, rates)
+	if err := service.saveForecast(context.Background(), &forecast); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+	if pending := service.accuracy.pendingPeriods(); pending != 0 {
+		t.Errorf("Expected no period beyond the window to be indexed, got %d", pending)
+	}
+
+	// Once the window reaches the first period it is read back from the history and scored when its close is final
+	later := accuracyNow.AddDate(0, 1, 2)
+	service.SetClock(ClockFunc(func() time.Time { return later }))
+	if _, err := service.EvaluateAccuracy(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pending := service.accuracy.pendingPeriods(); pending != 1 {
+		t.Errorf("Expected only the period within the window to be indexed, got %d", pending)
+	}
+	recordClose(rates, -31, map[string]float64{"EUR": 0.80})
+	if observed, err := service.EvaluateAccuracy(context.Background()); err != nil || observed != 1 {
+		t.Errorf("Expected the first quarterly period to be observed, got %d and %v", observed, err)
+	}
+}
+
+func TestAccuracyTracker_addPending_HourlyInUTC(t *testing.T) {
+	tracker := newAccuracyTracker()
+	forecast := storedForecast("EUR", "linear",
+		models.ForecastPeriod{Date: "2025-03-09T20:00:00-05:00"}, // 2025-03-10 in UTC
+		models.ForecastPeriod{Date: "2025-03-10T08:00:00+09:00"}, // 2025-03-09 in UTC
+	)
+	forecast.Frequency = "hourly"
+	tracker.addPending(forecast, "2025-02-01", "2025-04-01")
+
+	for date, horizon := range map[string]int{"2025-03-10": 1, "2025-03-09": 2} {
+		if periods := tracker.pending[date]; len(periods) != 1 || periods[0].observation.horizon != horizon {
+			t.Errorf("Expected period %d to be due on %s, got %+v", horizon, date, periods)
+		}
+	}
+}
+
+func TestForecastingService_EvaluateAccuracy_RateHistoryFailure(t *testing.T) {
+	store := history.NewMemoryStore()
+	forecast := storedForecast("EUR", "linear", models.ForecastPeriod{Date: accuracyDate(1), Rate: 0.88})
+	store.Save(context.Background(), &forecast)
+
+	service := newAccuracyTestService(store, failingRateHistory{ratehistory.NewMemoryStore()})
+	observed, err := service.EvaluateAccuracy(context.Background())
+	if err == nil {
+		t.Error("Expected an error, got nil")
+	}
+	if observed != 0 {
+		t.Errorf("Expected nothing observed, got %d", observed)
+	}
+	if service.accuracy.wasEvaluated(forecast.ForecastID, 1) {
+		t.Error("Expected the period to stay due for the next run")
+	}
+}
+
+func TestForecastingService_SetAccuracyLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forecasts.jsonl")
+	store := history.NewMemoryStore()
+	forecast := storedForecast("EUR", "linear", models.ForecastPeriod{Date: accuracyDate(1), Rate: 0.88})
+	store.Save(context.Background(), &forecast)
+	rates := ratehistory.NewMemoryStore()
+	recordClose(rates, 1, map[string]float64{"EUR": 0.80})
+
+	log, err := history.OpenObservationLog(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	service := newAccuracyTestService(store, rates)
+	if err := service.SetAccuracyLog(context.Background(), log); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if observed, _ := service.EvaluateAccuracy(context.Background()); observed != 1 {
+		t.Fatalf("Expected 1 observation, got %d", observed)
+	}
+	log.Close()
+
+	// A restarted service reports the persisted scores and does not score the period again
+	log, err = history.OpenObservationLog(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer log.Close()
+	restarted := newAccuracyTestService(store, rates)
+	if err := restarted.SetAccuracyLog(context.Background(), log); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats := restarted.AccuracyReport("", "").Stats; len(stats) != 1 || stats[0].Samples != 1 || math.Abs(stats[0].MAPE-10) > 1e-9 {
+		t.Errorf("Expected the persisted observation to be reported, got %+v", stats)
+	}
+	if observed, _ := restarted.EvaluateAccuracy(context.Background()); observed != 0 {
+		t.Errorf("Expected no period to be scored again, got %d", observed)
+	}
+}
+
+func TestAccuracyTracker_RollingWindow(t *testing.T) {
+	tracker := newAccuracyTracker()
+	now := time.Now()
+	tracker.record([]accuracyObservation{
+		{forecastID: "old", horizon: 1, pair: "USD/EUR", observedAt: now.AddDate(0, 0, -40)},
+		{forecastID: "new", horizon: 1, pair: "USD/EUR", observedAt: now.AddDate(0, 0, -1)},
+	}, time.Time{})
+
+	tracker.record(nil, now.AddDate(0, 0, -30))
+	if kept := tracker.since(time.Time{}); len(kept) != 1 {
+		t.Errorf("Expected observations outside the window to be dropped, got %d", len(kept))
+	}
+	if tracker.wasEvaluated("old", 1) {
+		t.Error("Expected evaluation markers outside the window to be dropped")
+	}
+	if !tracker.wasEvaluated("new", 1) {
+		t.Error("Expected evaluation markers inside the window to be kept")
+	}
+}
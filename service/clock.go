@@ -0,0 +1,75 @@
+package service
+
+import (
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+)
+
+// Clock supplies the current time to the forecasting service
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface
+type ClockFunc func() time.Time
+
+// Now implements Clock
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock reads the wall clock
+var SystemClock Clock = ClockFunc(time.Now)
+
+// defaultTimezone is used when a request does not name one, so dates do not depend on the replica serving it
+const defaultTimezone = "UTC"
+
+// loadTimezone resolves an IANA timezone name, defaulting to UTC
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		name = defaultTimezone
+	}
+	return time.LoadLocation(name)
+}
+
+// parseStartDate parses a YYYY-MM-DD date as midnight in location, or an RFC 3339 timestamp converted to location
+func parseStartDate(value string, location *time.Location) (time.Time, error) {
+	if start, err := time.ParseInLocation("2006-01-02", value, location); err == nil {
+		return start, nil
+	}
+	start, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return start.In(location), nil
+}
+
+// forecastStart returns the instant forecast periods count from: the request's start date, or the clock's now, in the request's timezone.
+// The request must already be validated.
+func (fs *ForecastingService) forecastStart(req *models.ForecastRequest) time.Time {
+	location, err := loadTimezone(req.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	if req.StartDate != "" {
+		if start, err := parseStartDate(req.StartDate, location); err == nil {
+			return start
+		}
+	}
+	return fs.clock.Now().In(location)
+}
+
+// validateSchedule checks the start date and timezone of a request
+func validateSchedule(startDate, timezone string) error {
+	location, err := loadTimezone(timezone)
+	if err != nil {
+		return newValidationError(CodeValidationError, "unknown timezone %q", timezone)
+	}
+	if startDate != "" {
+		if _, err := parseStartDate(startDate, location); err != nil {
+			return newValidationError(CodeValidationError, "start_date must be YYYY-MM-DD or an RFC 3339 timestamp")
+		}
+	}
+	return nil
+}
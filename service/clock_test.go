@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/config"
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+)
+
+// TestForecastingService_GenerateForecast_Reproducible tests that a pinned clock, start date and timezone fix the period dates
+func TestForecastingService_GenerateForecast_Reproducible(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR"},
+		CurrencyExchangeServiceURL: server.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+	}
+	service := NewForecastingService(cfg, logger.New("error"))
+	now := time.Date(2025, 3, 7, 23, 30, 0, 0, time.UTC)
+	service.SetClock(ClockFunc(func() time.Time { return now }))
+
+	tests := []struct {
+		name     string
+		request  models.ForecastRequest
+		timezone string
+		dates    []string
+	}{
+		{"default UTC", models.ForecastRequest{}, "UTC", []string{"2025-03-08", "2025-03-09"}},
+		{"timezone ahead of UTC", models.ForecastRequest{Timezone: "Asia/Tokyo"}, "Asia/Tokyo", []string{"2025-03-09", "2025-03-10"}},
+		{"start date", models.ForecastRequest{StartDate: "2025-01-30", Timezone: "America/New_York", Frequency: "monthly"}, "America/New_York", []string{"2025-02-28", "2025-03-30"}},
+		{"start timestamp", models.ForecastRequest{StartDate: "2025-06-01T02:00:00Z", Timezone: "America/New_York"}, "America/New_York", []string{"2025-06-01", "2025-06-02"}},
+		{"hourly across DST", models.ForecastRequest{StartDate: "2025-03-09T00:30:00-05:00", Timezone: "America/New_York", Frequency: "hourly"}, "America/New_York", []string{"2025-03-09T01:00:00-05:00", "2025-03-09T03:00:00-04:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.request
+			req.BaseCurrency, req.TargetCurrency, req.Amount, req.Periods = "USD", "EUR", 1000, len(tt.dates)
+
+			response, err := service.GenerateForecast(context.Background(), &req)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !response.GeneratedAt.Equal(now) {
+				t.Errorf("Expected generated_at from the clock, got %v", response.GeneratedAt)
+			}
+			if response.Timezone != tt.timezone {
+				t.Errorf("Expected timezone %s, got %s", tt.timezone, response.Timezone)
+			}
+			for i, period := range response.Forecasts {
+				if period.Date != tt.dates[i] {
+					t.Errorf("Expected period %d on %s, got %s", period.Period, tt.dates[i], period.Date)
+				}
+			}
+		})
+	}
+}
+
+// TestForecastingService_CacheExpiryUsesClock tests that cached forecasts expire by the injected clock, not wall time
+func TestForecastingService_CacheExpiryUsesClock(t *testing.T) {
+	var upstreamCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR"},
+		CurrencyExchangeServiceURL: server.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+		ForecastCacheTTL:           time.Minute,
+	}
+	service := NewForecastingService(cfg, logger.New("error"))
+	// Pinned years in the past, so wall time alone would expire every entry
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.SetClock(ClockFunc(func() time.Time { return now }))
+	generate := func() {
+		t.Helper()
+		req := &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1000, Periods: 2}
+		if _, err := service.GenerateForecast(context.Background(), req); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	generate()
+	now = now.Add(30 * time.Second)
+	generate()
+	if upstreamCalls != 1 {
+		t.Errorf("Expected the forecast to be served from cache within the TTL, got %d upstream calls", upstreamCalls)
+	}
+
+	now = now.Add(time.Minute)
+	generate()
+	if upstreamCalls != 2 {
+		t.Errorf("Expected the forecast to be recomputed once the clock passes the TTL, got %d upstream calls", upstreamCalls)
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		startDate string
+		timezone  string
+		wantErr   bool
+	}{
+		{"defaults", "", "", false},
+		{"date in timezone", "2025-01-02", "Europe/London", false},
+		{"timestamp", "2025-01-02T15:04:05+09:00", "", false},
+		{"unknown timezone", "", "Mars/Olympus_Mons", true},
+		{"malformed date", "02/01/2025", "", true},
+		{"impossible date", "2025-02-30", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchedule(tt.startDate, tt.timezone)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && ErrorCode(err) != CodeValidationError {
+				t.Errorf("Expected code %s, got %s", CodeValidationError, ErrorCode(err))
+			}
+		})
+	}
+}
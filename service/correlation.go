@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/dalfonso89/financial-forecasting-service/analytics"
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+)
+
+// Correlation estimation methods
+const (
+	correlationSample = "sample"
+	correlationEWMA   = "ewma"
+)
+
+// Correlation defaults and limits
+const (
+	defaultCorrelationWindowDays = 90
+	defaultCorrelationStepDays   = 7
+	defaultEWMALambda            = 0.94 // RiskMetrics decay for daily returns
+	maxCorrelationWindows        = 60
+)
+
+// CorrelationOptions selects the rate history window and estimator for CorrelationMatrix; zero values use the defaults
+type CorrelationOptions struct {
+	Base       string
+	WindowDays int
+	Method     string // "sample" or "ewma"
+	Lambda     float64
+	Windows    int // Rolling windows, ending StepDays apart
+	StepDays   int
+}
+
+// CorrelationMatrix estimates the correlation and covariance of the daily log returns of every supported currency against
+// the base from rate history, over the latest window and optionally over earlier rolling windows
+func (fs *ForecastingService) CorrelationMatrix(ctx context.Context, options CorrelationOptions) (*models.CorrelationResponse, error) {
+	options.Base = currency.Normalize(options.Base)
+	if options.WindowDays == 0 {
+		options.WindowDays = defaultCorrelationWindowDays
+	}
+	if options.Method == "" {
+		options.Method = correlationSample
+	}
+	if options.Method != correlationEWMA {
+		options.Lambda = 0
+	} else if options.Lambda == 0 {
+		options.Lambda = defaultEWMALambda
+	}
+	if options.Windows == 0 {
+		options.Windows = 1
+	}
+	if options.StepDays == 0 {
+		options.StepDays = defaultCorrelationStepDays
+	}
+	if err := fs.validateCorrelationOptions(options); err != nil {
+		return nil, err
+	}
+
+	now := fs.clock.Now()
+	latest, err := ratehistory.Series(ctx, fs.rateHistory, options.Base, now.AddDate(0, 0, -options.WindowDays), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate history: %w", err)
+	}
+
+	// Currencies never quoted in the latest window would leave no complete points, so they are reported instead
+	quoted := make(map[string]bool)
+	for _, point := range latest {
+		for code := range point.Rates {
+			quoted[code] = true
+		}
+	}
+	response := &models.CorrelationResponse{
+		Base:        options.Base,
+		Currencies:  []string{},
+		Method:      options.Method,
+		Lambda:      options.Lambda,
+		WindowDays:  options.WindowDays,
+		GeneratedAt: now,
+	}
+	for _, code := range fs.SupportedCurrencies() {
+		code = currency.Normalize(code)
+		switch {
+		case code == options.Base:
+		case quoted[code]:
+			response.Currencies = append(response.Currencies, code)
+		default:
+			response.Unavailable = append(response.Unavailable, code)
+		}
+	}
+
+	var windows []models.CorrelationWindow
+	for k := options.Windows - 1; k >= 0; k-- {
+		to := now.AddDate(0, 0, -k*options.StepDays)
+		from := to.AddDate(0, 0, -options.WindowDays)
+		points := latest
+		if k > 0 {
+			if points, err = ratehistory.Series(ctx, fs.rateHistory, options.Base, from, to); err != nil {
+				return nil, fmt.Errorf("failed to read rate history: %w", err)
+			}
+		}
+
+		returns := analytics.LogReturns(points, response.Currencies)
+		observations := 0
+		if len(returns) > 0 {
+			observations = len(returns[0])
+		}
+		// Earlier windows without enough history are left out; the latest one must have it
+		if observations < 2 {
+			if k == 0 {
+				return nil, newValidationError(CodeInsufficientHistory,
+					"correlation needs at least 2 daily returns against %s in the last %d days of rate history; %d available",
+					options.Base, options.WindowDays, observations)
+			}
+			continue
+		}
+
+		covariance := analytics.Covariance(returns)
+		if options.Method == correlationEWMA {
+			covariance = analytics.EWMACovariance(returns, options.Lambda)
+		}
+		windows = append(windows, models.CorrelationWindow{
+			From:         ratehistory.DateOf(from),
+			To:           ratehistory.DateOf(to),
+			Observations: observations,
+			Pearson:      roundMatrix(analytics.Correlation(covariance)),
+			Spearman:     roundMatrix(analytics.SpearmanCorrelation(returns)),
+			Covariance:   covariance,
+		})
+	}
+
+	current := windows[len(windows)-1]
+	response.From, response.To, response.Observations = current.From, current.To, current.Observations
+	response.Pearson, response.Spearman, response.Covariance = current.Pearson, current.Spearman, current.Covariance
+	if options.Windows > 1 {
+		response.StepDays, response.Rolling = options.StepDays, windows
+	}
+
+	fs.logger.WithContext(ctx).Infof("Estimated %s correlation of %d currencies against %s over %d windows",
+		options.Method, len(response.Currencies), options.Base, len(windows))
+	return response, nil
+}
+
+// validateCorrelationOptions validates correlation options after defaults are applied
+func (fs *ForecastingService) validateCorrelationOptions(options CorrelationOptions) error {
+	if options.Base == "" {
+		return newValidationError(CodeValidationError, "base currency is required")
+	}
+	if !fs.isCurrencySupported(options.Base) {
+		return newValidationError(CodeUnsupportedCurrency, "base currency %s is not supported", options.Base)
+	}
+	if options.WindowDays < 2 || options.WindowDays > maxRiskWindowDays {
+		return newValidationError(CodeValidationError, "window must be between 2 and %d days", maxRiskWindowDays)
+	}
+	switch options.Method {
+	case correlationSample:
+	case correlationEWMA:
+		if math.IsNaN(options.Lambda) || options.Lambda <= 0 || options.Lambda >= 1 {
+			return newValidationError(CodeValidationError, "lambda must be greater than 0 and less than 1")
+		}
+	default:
+		return newValidationError(CodeValidationError, "unsupported correlation method: %s", options.Method)
+	}
+	if options.Windows < 1 || options.Windows > maxCorrelationWindows {
+		return newValidationError(CodeValidationError, "windows must be between 1 and %d", maxCorrelationWindows)
+	}
+	if options.StepDays < 1 || options.StepDays > maxRiskWindowDays {
+		return newValidationError(CodeValidationError, "step must be between 1 and %d days", maxRiskWindowDays)
+	}
+	return nil
+}
+
+// roundMatrix rounds correlations to four decimal places in place
+func roundMatrix(matrix [][]float64) [][]float64 {
+	for i := range matrix {
+		for j := range matrix[i] {
+			matrix[i][j] = math.Round(matrix[i][j]*1e4) / 1e4
+		}
+	}
+	return matrix
+}
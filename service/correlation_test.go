@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestForecastingService_CorrelationMatrix(t *testing.T) {
+	service := newTestService(t)
+	seedRateHistory(service, -1)
+
+	response, err := service.CorrelationMatrix(context.Background(), CorrelationOptions{Base: "usd", WindowDays: 90})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Currencies) != 2 || response.Currencies[0] != "EUR" || response.Currencies[1] != "JPY" {
+		t.Fatalf("Expected EUR and JPY, got %v", response.Currencies)
+	}
+	if len(response.Unavailable) != 1 || response.Unavailable[0] != "GBP" {
+		t.Errorf("Expected GBP to be unavailable, got %v", response.Unavailable)
+	}
+	if response.Observations != 39 || response.To != "2025-03-10" || response.From != "2024-12-10" {
+		t.Errorf("Expected 39 returns from 2024-12-10 to 2025-03-10, got %d from %s to %s", response.Observations, response.From, response.To)
+	}
+	if response.Pearson[0][1] != -1 || response.Spearman[0][1] != -1 {
+		t.Errorf("Expected opposed currencies to correlate -1, got Pearson %v and Spearman %v", response.Pearson[0][1], response.Spearman[0][1])
+	}
+	if variance := response.Covariance[0][0]; math.Abs(variance-response.Covariance[1][1]) > 1e-12 || variance <= 0 {
+		t.Errorf("Expected equal positive variances, got %v", response.Covariance)
+	}
+	if response.Rolling != nil {
+		t.Errorf("Expected no rolling windows by default, got %d", len(response.Rolling))
+	}
+}
+
+func TestForecastingService_CorrelationMatrix_Rolling(t *testing.T) {
+	service := newTestService(t)
+	seedRateHistory(service, 1)
+
+	sample, err := service.CorrelationMatrix(context.Background(), CorrelationOptions{Base: "USD", WindowDays: 20, Windows: 4, StepDays: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// History covers the 40 days to 2025-03-09, so the oldest 20-day window, ending 2025-02-08, holds 10 returns
+	if len(sample.Rolling) != 4 || sample.Rolling[0].To != "2025-02-08" || sample.Rolling[0].Observations != 10 {
+		t.Fatalf("Expected 4 windows from 2025-02-08, got %+v", sample.Rolling)
+	}
+	if last := sample.Rolling[3]; last.To != sample.To || last.Observations != sample.Observations {
+		t.Errorf("Expected the last rolling window to be the latest, got %+v", last)
+	}
+
+	ewma, err := service.CorrelationMatrix(context.Background(), CorrelationOptions{Base: "USD", WindowDays: 20, Method: "ewma"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ewma.Lambda != defaultEWMALambda || ewma.Pearson[0][1] != 1 {
+		t.Errorf("Expected EWMA at the default lambda with correlation 1, got lambda %v and %v", ewma.Lambda, ewma.Pearson[0][1])
+	}
+	if ewma.Covariance[0][0] == sample.Covariance[0][0] {
+		t.Errorf("Expected EWMA variance to differ from the sample variance %v", sample.Covariance[0][0])
+	}
+}
+
+func TestForecastingService_CorrelationMatrix_Validation(t *testing.T) {
+	service := newTestService(t)
+
+	tests := []struct {
+		name    string
+		options CorrelationOptions
+		code    string
+	}{
+		{"missing base", CorrelationOptions{}, CodeValidationError},
+		{"unsupported base", CorrelationOptions{Base: "CHF"}, CodeUnsupportedCurrency},
+		{"window", CorrelationOptions{Base: "USD", WindowDays: 1}, CodeValidationError},
+		{"method", CorrelationOptions{Base: "USD", Method: "kendall"}, CodeValidationError},
+		{"lambda", CorrelationOptions{Base: "USD", Method: "ewma", Lambda: 1}, CodeValidationError},
+		{"NaN lambda", CorrelationOptions{Base: "USD", Method: "ewma", Lambda: math.NaN()}, CodeValidationError},
+		{"windows", CorrelationOptions{Base: "USD", Windows: maxCorrelationWindows + 1}, CodeValidationError},
+		{"no history", CorrelationOptions{Base: "USD"}, CodeInsufficientHistory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CorrelationMatrix(context.Background(), tt.options); ErrorCode(err) != tt.code {
+				t.Errorf("Expected code %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
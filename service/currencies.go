@@ -0,0 +1,53 @@
+package service
+
+import (
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+)
+
+// currencySet indexes the configured supported currencies
+type currencySet map[string]bool
+
+// newCurrencySet normalizes and indexes a list of currency codes
+func newCurrencySet(codes []string) *currencySet {
+	set := make(currencySet, len(codes))
+	for _, code := range codes {
+		set[currency.Normalize(code)] = true
+	}
+	return &set
+}
+
+// Currencies returns the registry entry of every supported currency, in configured order
+func (fs *ForecastingService) Currencies() []models.Currency {
+	codes := fs.SupportedCurrencies()
+	currencies := make([]models.Currency, 0, len(codes))
+	for _, code := range codes {
+		if entry, ok := currency.Lookup(code); ok {
+			currencies = append(currencies, currencyModel(entry, true))
+		}
+	}
+	return currencies
+}
+
+// Currency returns the registry entry for an ISO 4217 code, ignoring case, and whether forecasts accept it
+func (fs *ForecastingService) Currency(code string) (*models.Currency, error) {
+	entry, ok := currency.Lookup(code)
+	if !ok {
+		return nil, newNotFoundError(CodeCurrencyNotFound, "%s is not an ISO 4217 currency code", code)
+	}
+	model := currencyModel(entry, fs.isCurrencySupported(entry.Code))
+	return &model, nil
+}
+
+// currencyModel converts a registry entry to its API representation
+func currencyModel(entry currency.Currency, supported bool) models.Currency {
+	return models.Currency{
+		Code:       entry.Code,
+		Numeric:    entry.Numeric,
+		Name:       entry.Name,
+		MinorUnits: entry.MinorUnits,
+		Symbol:     entry.Symbol,
+		Countries:  entry.Countries,
+		Supported:  supported,
+	}
+}
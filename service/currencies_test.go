@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/config"
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+)
+
+func TestForecastingService_Currency(t *testing.T) {
+	cfg := &config.Config{SupportedCurrencies: []string{"USD", "EUR"}}
+	service := NewForecastingService(cfg, logger.New("error"))
+
+	tests := []struct {
+		name      string
+		code      string
+		expected  string
+		supported bool
+		wantErr   bool
+	}{
+		{"supported", "USD", "USD", true, false},
+		{"mixed case", " eUr ", "EUR", true, false},
+		{"ISO but not supported", "chf", "CHF", false, false},
+		{"unknown", "ABC", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currency, err := service.Currency(tt.code)
+			if tt.wantErr {
+				if ErrorCode(err) != CodeCurrencyNotFound {
+					t.Errorf("Expected code %s, got %v", CodeCurrencyNotFound, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if currency.Code != tt.expected || currency.Supported != tt.supported {
+				t.Errorf("Expected %s with supported=%v, got %+v", tt.expected, tt.supported, currency)
+			}
+		})
+	}
+
+	service.UpdateConfig(&config.Config{SupportedCurrencies: []string{"CHF"}})
+	if currency, _ := service.Currency("CHF"); !currency.Supported {
+		t.Error("Expected CHF to be supported after a config reload")
+	}
+	if currencies := service.Currencies(); len(currencies) != 1 || currencies[0].Name != "Swiss Franc" {
+		t.Errorf("Expected only the Swiss Franc, got %+v", currencies)
+	}
+}
+
+func TestForecastingService_GenerateForecast_CaseInsensitiveCurrencies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"JPY":150.25}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "JPY"},
+		CurrencyExchangeServiceURL: server.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+	}
+	service := NewForecastingService(cfg, logger.New("error"))
+
+	response, err := service.GenerateForecast(context.Background(), &models.ForecastRequest{
+		BaseCurrency: "usd", TargetCurrency: "Jpy", Amount: 10, Periods: 1,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.BaseCurrency != "USD" || response.TargetCurrency != "JPY" {
+		t.Errorf("Expected USD/JPY, got %s/%s", response.BaseCurrency, response.TargetCurrency)
+	}
+	if response.Forecasts[0].Amount.Scale() != 0 {
+		t.Errorf("Expected JPY amounts with no minor unit, got %s", response.Forecasts[0].Amount)
+	}
+}
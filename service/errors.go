@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/dalfonso89/financial-forecasting-service/client"
+)
+
+// Error categories returned by the forecasting service. Use errors.Is to test for them.
+var (
+	ErrValidation          = errors.New("validation failed")
+	ErrNotFound            = errors.New("not found")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrUpstreamTimeout     = errors.New("upstream timeout")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrCanceled            = errors.New("canceled")
+)
+
+// Stable machine-readable error codes
+const (
+	CodeValidationError         = "validation_error"
+	CodeUnsupportedCurrency     = "unsupported_currency"
+	CodeUnsupportedForecastType = "unsupported_forecast_type"
+	CodeUnsupportedCalendar     = "unsupported_calendar"
+	CodeUnsupportedFrequency    = "unsupported_frequency"
+	CodeCurrencyNotFound        = "currency_not_found"
+	CodeForecastNotFound        = "forecast_not_found"
+	CodeInsufficientHistory     = "insufficient_history"
+	CodeCurveNotFound           = "curve_not_found"
+	CodeUpstreamUnavailable     = "upstream_unavailable"
+	CodeUpstreamTimeout         = "upstream_timeout"
+	CodeRateLimited             = "rate_limited"
+	CodeClientClosedRequest     = "client_closed_request"
+	CodeInternalError           = "internal_error"
+)
+
+// Error is a typed service error carrying its category and a stable code
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// Unwrap exposes both the category and the underlying cause to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// ErrorCode returns the stable code of a service error, or CodeInternalError for anything else
+func ErrorCode(err error) string {
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Code
+	}
+	return CodeInternalError
+}
+
+// newValidationError creates a validation error with the given code
+func newValidationError(code, format string, args ...interface{}) error {
+	return &Error{Kind: ErrValidation, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// newNotFoundError creates a not-found error with the given code
+func newNotFoundError(code, format string, args ...interface{}) error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// newUpstreamError classifies a currency client error into an upstream error category
+func newUpstreamError(err error) error {
+	const message = "failed to fetch exchange rates"
+
+	var statusErr *client.StatusError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The caller went away; this is not an upstream failure
+		return &Error{Kind: ErrCanceled, Code: CodeClientClosedRequest, Message: "request canceled", Err: err}
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return &Error{Kind: ErrUpstreamTimeout, Code: CodeUpstreamTimeout, Message: message, Err: err}
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests:
+		return &Error{Kind: ErrRateLimited, Code: CodeRateLimited, Message: message, Err: err}
+	default:
+		return &Error{Kind: ErrUpstreamUnavailable, Code: CodeUpstreamUnavailable, Message: message, Err: err}
+	}
+}
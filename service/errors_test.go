@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/dalfonso89/financial-forecasting-service/client"
+)
+
+// TestNewUpstreamError tests classification of currency client failures
+func TestNewUpstreamError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedKind error
+		expectedCode string
+	}{
+		{
+			name:         "deadline exceeded",
+			err:          fmt.Errorf("failed to fetch rates: %w", context.DeadlineExceeded),
+			expectedKind: ErrUpstreamTimeout,
+			expectedCode: CodeUpstreamTimeout,
+		},
+		{
+			name:         "client canceled",
+			err:          fmt.Errorf("failed to fetch rates: %w", context.Canceled),
+			expectedKind: ErrCanceled,
+			expectedCode: CodeClientClosedRequest,
+		},
+		{
+			name:         "rate limited",
+			err:          &client.StatusError{StatusCode: http.StatusTooManyRequests},
+			expectedKind: ErrRateLimited,
+			expectedCode: CodeRateLimited,
+		},
+		{
+			name:         "server error",
+			err:          &client.StatusError{StatusCode: http.StatusInternalServerError},
+			expectedKind: ErrUpstreamUnavailable,
+			expectedCode: CodeUpstreamUnavailable,
+		},
+		{
+			name:         "connection failure",
+			err:          errors.New("connection refused"),
+			expectedKind: ErrUpstreamUnavailable,
+			expectedCode: CodeUpstreamUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newUpstreamError(tt.err)
+			if !errors.Is(err, tt.expectedKind) {
+				t.Errorf("Expected error kind %v, got %v", tt.expectedKind, err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("Expected underlying error to be preserved")
+			}
+			if code := ErrorCode(err); code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, code)
+			}
+		})
+	}
+}
+
+// TestErrorCode tests code extraction from wrapped and untyped errors
+func TestErrorCode(t *testing.T) {
+	wrapped := fmt.Errorf("invalid request: %w", newValidationError(CodeUnsupportedCurrency, "base currency %s is not supported", "XXX"))
+	if !errors.Is(wrapped, ErrValidation) {
+		t.Error("Expected wrapped error to be a validation error")
+	}
+	if code := ErrorCode(wrapped); code != CodeUnsupportedCurrency {
+		t.Errorf("Expected code %s, got %s", CodeUnsupportedCurrency, code)
+	}
+	if code := ErrorCode(errors.New("boom")); code != CodeInternalError {
+		t.Errorf("Expected code %s, got %s", CodeInternalError, code)
+	}
+}
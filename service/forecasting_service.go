@@ -2,188 +2,349 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/dalfonso89/financial-forecasting-service/calendar"
 	"github.com/dalfonso89/financial-forecasting-service/client"
 	"github.com/dalfonso89/financial-forecasting-service/config"
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+	"github.com/dalfonso89/financial-forecasting-service/curve"
+	"github.com/dalfonso89/financial-forecasting-service/history"
 	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
 	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
+	"github.com/dalfonso89/financial-forecasting-service/uuid"
 )
 
+// maxAmount is the largest request amount; converted values and their variances stay finite below it
+const maxAmount = 1e15
+
+// maxHorizonDays is how far ahead the last forecast period may fall, whatever the frequency: five years
+const maxHorizonDays = 5 * 365.25
+
 // ForecastingService handles financial forecasting operations
 type ForecastingService struct {
-	config         *config.Config
+	config         atomic.Pointer[config.Config] // Swapped by UpdateConfig when configuration is reloaded
+	supported      atomic.Pointer[currencySet]   // Index of config.SupportedCurrencies, swapped with it
+	curves         atomic.Pointer[curve.Set]     // Interest rate curves, swapped by SetInterestRateCurves
 	logger         logger.Logger
 	currencyClient *client.CurrencyClient
+	history        history.Store
+	rateHistory    ratehistory.Store
+	accuracy       *accuracyTracker
+	accuracyLog    history.ObservationLog
+	clock          Clock
 
 	// Cache for forecasts
 	cacheMutex sync.RWMutex
 	cache      map[string]models.ForecastResponse
 }
 
-// NewForecastingService creates a new forecasting service
+// NewForecastingService creates a new forecasting service that keeps forecast history in memory
 func NewForecastingService(cfg *config.Config, logger logger.Logger) *ForecastingService {
-	return &ForecastingService{
-		config:         cfg,
+	return NewForecastingServiceWithHistory(cfg, logger, history.NewMemoryStore())
+}
+
+// NewForecastingServiceWithHistory creates a new forecasting service that records every generated forecast in store
+func NewForecastingServiceWithHistory(cfg *config.Config, logger logger.Logger, store history.Store) *ForecastingService {
+	service := &ForecastingService{
 		logger:         logger,
 		currencyClient: client.NewCurrencyClient(cfg, logger),
+		history:        store,
+		rateHistory:    ratehistory.NewMemoryStore(),
+		accuracy:       newAccuracyTracker(),
+		accuracyLog:    history.DiscardObservationLog(),
+		clock:          SystemClock,
 		cache:          make(map[string]models.ForecastResponse),
 	}
+	service.UpdateConfig(cfg)
+	service.SetInterestRateCurves(curve.Set{})
+	return service
+}
+
+// UpdateConfig atomically replaces the configuration used for defaults, validation, caching and limits
+func (fs *ForecastingService) UpdateConfig(cfg *config.Config) {
+	fs.supported.Store(newCurrencySet(cfg.SupportedCurrencies))
+	fs.config.Store(cfg)
+}
+
+// SetInterestRateCurves atomically replaces the interest rate curves forward rates are priced from
+func (fs *ForecastingService) SetInterestRateCurves(curves curve.Set) {
+	fs.curves.Store(&curves)
+}
+
+// SetClock replaces the clock used for forecast dates and timestamps; call it before the service handles requests
+func (fs *ForecastingService) SetClock(clock Clock) {
+	fs.clock = clock
+}
+
+// SupportedCurrencies returns the currencies currently accepted in requests
+func (fs *ForecastingService) SupportedCurrencies() []string {
+	return fs.config.Load().SupportedCurrencies
 }
 
 // GenerateForecast generates a financial forecast for a currency pair
-func (fs *ForecastingService) GenerateForecast(ctx context.Context, req *models.ForecastRequest) (*models.ForecastResponse, error) {
+func (fs *ForecastingService) GenerateForecast(ctx context.Context, req *models.ForecastRequest) (response *models.ForecastResponse, err error) {
+	req.BaseCurrency, req.TargetCurrency = currency.Normalize(req.BaseCurrency), currency.Normalize(req.TargetCurrency)
+	ctx = logger.ContextWithFields(ctx, logger.Fields{"currency_pair": req.BaseCurrency + "/" + req.TargetCurrency})
+	requestLogger := fs.logger.WithContext(ctx)
+	ctx, span := tracing.Start(ctx, "ForecastingService.GenerateForecast")
+	span.SetAttribute("currency.pair", req.BaseCurrency+"/"+req.TargetCurrency)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	// Validate request
 	if err := fs.validateForecastRequest(req); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
 	// Set defaults
+	model := fs.modelFor(req.BaseCurrency, req.TargetCurrency)
 	if req.Periods == 0 {
-		req.Periods = fs.config.DefaultForecastPeriods
+		req.Periods = model.Periods
 	}
 	if req.ForecastType == "" {
-		req.ForecastType = "linear"
+		req.ForecastType = model.ForecastType
+	}
+	if req.Calendar == "" {
+		req.Calendar = calendar.Daily
+	}
+	if req.Frequency == "" {
+		req.Frequency = "daily"
 	}
+	if req.Timezone == "" {
+		req.Timezone = defaultTimezone
+	}
+
+	span.SetAttribute("forecast.type", req.ForecastType)
+	span.SetAttribute("forecast.periods", req.Periods)
 
 	// Check cache first
+	_, cacheSpan := tracing.Start(ctx, "forecast.cache_lookup")
 	cacheKey := fs.generateCacheKey(req)
 	fs.cacheMutex.RLock()
-	if cached, exists := fs.cache[cacheKey]; exists {
-		fs.cacheMutex.RUnlock()
-		fs.logger.Debugf("Returning cached forecast for %s/%s", req.BaseCurrency, req.TargetCurrency)
+	cached, exists := fs.cache[cacheKey]
+	fs.cacheMutex.RUnlock()
+	if ttl := fs.config.Load().ForecastCacheTTL; exists && ttl > 0 && fs.clock.Now().Sub(cached.GeneratedAt) >= ttl {
+		exists = false
+	}
+	cacheSpan.SetAttribute("cache.hit", exists)
+	cacheSpan.End()
+	if exists {
+		metrics.ForecastCacheHits.Inc()
+		requestLogger.Debugf("Returning cached forecast for %s/%s", req.BaseCurrency, req.TargetCurrency)
 		return &cached, nil
 	}
-	fs.cacheMutex.RUnlock()
+	metrics.ForecastCacheMisses.Inc()
 
 	// Fetch current exchange rates
-	rates, err := fs.currencyClient.GetRates(ctx, req.BaseCurrency)
+	rates, err := fs.getRates(ctx, req.BaseCurrency)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+		return nil, newUpstreamError(err)
 	}
 
 	// Get current rate for target currency
 	currentRate, exists := rates.Rates[req.TargetCurrency]
 	if !exists {
-		return nil, fmt.Errorf("target currency %s not found in exchange rates", req.TargetCurrency)
+		return nil, newNotFoundError(CodeCurrencyNotFound, "target currency %s not found in exchange rates", req.TargetCurrency)
 	}
 
 	// Generate forecast based on type
 	var forecasts []models.ForecastPeriod
 	var confidenceScore float64
 
+	_, computeSpan := tracing.Start(ctx, "forecast.compute")
+	computeSpan.SetAttribute("forecast.type", req.ForecastType)
+	computeStart := time.Now()
+	samples := fs.resampleRates(ctx, currentRate, req)
 	switch req.ForecastType {
 	case "linear":
-		forecasts, confidenceScore = fs.generateLinearForecast(currentRate, req)
+		forecasts, confidenceScore = fs.generateLinearForecast(currentRate, req, samples)
 	case "exponential":
-		forecasts, confidenceScore = fs.generateExponentialForecast(currentRate, req)
+		forecasts, confidenceScore = fs.generateExponentialForecast(currentRate, req, samples)
 	case "moving_average":
-		forecasts, confidenceScore = fs.generateMovingAverageForecast(currentRate, req)
+		forecasts, confidenceScore = fs.generateMovingAverageForecast(currentRate, req, samples)
 	default:
-		return nil, fmt.Errorf("unsupported forecast type: %s", req.ForecastType)
+		computeSpan.End()
+		return nil, newValidationError(CodeUnsupportedForecastType, "unsupported forecast type: %s", req.ForecastType)
 	}
+	metrics.ForecastDuration.WithLabelValues(req.ForecastType).Observe(time.Since(computeStart).Seconds())
+	computeSpan.End()
 
 	// Create response
-	response := &models.ForecastResponse{
+	generatedAt := fs.clock.Now()
+	response = &models.ForecastResponse{
+		ForecastID:      uuid.NewV7(generatedAt),
 		BaseCurrency:    req.BaseCurrency,
 		TargetCurrency:  req.TargetCurrency,
 		CurrentRate:     currentRate,
-		Amount:          req.Amount,
+		Amount:          fs.baseAmount(req.Amount, req.BaseCurrency),
 		ForecastType:    req.ForecastType,
+		Calendar:        req.Calendar,
+		Frequency:       req.Frequency,
+		StartDate:       req.StartDate,
+		Timezone:        req.Timezone,
 		Periods:         req.Periods,
 		Forecasts:       forecasts,
-		GeneratedAt:     time.Now(),
+		GeneratedAt:     generatedAt,
 		ConfidenceScore: confidenceScore,
 	}
 
+	// Record the forecast for later retrieval and accuracy scoring; history failures do not fail the request
+	if err := fs.saveForecast(ctx, response); err != nil {
+		metrics.ForecastHistoryWriteErrors.Inc()
+		requestLogger.Errorf("Failed to record forecast %s in history: %v", response.ForecastID, err)
+		response.ForecastID = ""
+	}
+	span.SetAttribute("forecast.id", response.ForecastID)
+
 	// Cache the result
 	fs.cacheMutex.Lock()
 	fs.cache[cacheKey] = *response
 	fs.cacheMutex.Unlock()
 
-	fs.logger.Infof("Generated %s forecast for %s/%s with %d periods", req.ForecastType, req.BaseCurrency, req.TargetCurrency, req.Periods)
+	requestLogger.Infof("Generated %s forecast for %s/%s with %d periods", req.ForecastType, req.BaseCurrency, req.TargetCurrency, req.Periods)
 	return response, nil
 }
 
 // GenerateMultiCurrencyForecast generates forecasts for multiple currencies
-func (fs *ForecastingService) GenerateMultiCurrencyForecast(ctx context.Context, req *models.MultiCurrencyForecastRequest) (*models.MultiCurrencyForecastResponse, error) {
-	// Set defaults
+func (fs *ForecastingService) GenerateMultiCurrencyForecast(ctx context.Context, req *models.MultiCurrencyForecastRequest) (response *models.MultiCurrencyForecastResponse, err error) {
+	req.BaseCurrency = currency.Normalize(req.BaseCurrency)
+	for i, code := range req.Currencies {
+		req.Currencies[i] = currency.Normalize(code)
+	}
+	ctx = logger.ContextWithFields(ctx, logger.Fields{"base_currency": req.BaseCurrency})
+	requestLogger := fs.logger.WithContext(ctx)
+	ctx, span := tracing.Start(ctx, "ForecastingService.GenerateMultiCurrencyForecast")
+	span.SetAttribute("currency.base", req.BaseCurrency)
+	span.SetAttribute("currency.count", len(req.Currencies))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// Set defaults; per-pair overrides do not apply across several targets
+	model := fs.modelFor("", "")
 	if req.Periods == 0 {
-		req.Periods = fs.config.DefaultForecastPeriods
+		req.Periods = model.Periods
 	}
 	if req.ForecastType == "" {
-		req.ForecastType = "linear"
+		req.ForecastType = model.ForecastType
+	}
+	if !IsForecastTypeSupported(req.ForecastType) {
+		return nil, newValidationError(CodeUnsupportedForecastType, "unsupported forecast type: %s", req.ForecastType)
+	}
+	if req.Calendar == "" {
+		req.Calendar = calendar.Daily
+	}
+	if !isCalendarSupported(req.Calendar) {
+		return nil, newValidationError(CodeUnsupportedCalendar, "unsupported calendar: %s", req.Calendar)
+	}
+	if req.Frequency == "" {
+		req.Frequency = "daily"
+	}
+	if !isFrequencySupported(req.Frequency) {
+		return nil, newValidationError(CodeUnsupportedFrequency, "unsupported frequency: %s", req.Frequency)
+	}
+	if req.Timezone == "" {
+		req.Timezone = defaultTimezone
+	}
+	if err := validateSchedule(req.StartDate, req.Timezone); err != nil {
+		return nil, err
+	}
+	if err := validateAmount(req.Amount); err != nil {
+		return nil, err
 	}
 
 	// Fetch current exchange rates
-	rates, err := fs.currencyClient.GetRates(ctx, req.BaseCurrency)
+	rates, err := fs.getRates(ctx, req.BaseCurrency)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+		return nil, newUpstreamError(err)
 	}
 
 	// Generate forecasts for each currency
+	_, computeSpan := tracing.Start(ctx, "forecast.compute")
+	computeSpan.SetAttribute("forecast.type", req.ForecastType)
 	currencyForecasts := make(map[string][]models.ForecastPeriod)
 
-	for _, currency := range req.Currencies {
-		rate, exists := rates.Rates[currency]
+	for _, code := range req.Currencies {
+		rate, exists := rates.Rates[code]
 		if !exists {
-			fs.logger.Warnf("Currency %s not found in exchange rates, skipping", currency)
+			requestLogger.WithField("currency_pair", req.BaseCurrency+"/"+code).Warnf("Currency %s not found in exchange rates, skipping", code)
 			continue
 		}
 
 		forecastReq := &models.ForecastRequest{
 			BaseCurrency:   req.BaseCurrency,
-			TargetCurrency: currency,
+			TargetCurrency: code,
 			Amount:         req.Amount,
 			Periods:        req.Periods,
 			ForecastType:   req.ForecastType,
+			Calendar:       req.Calendar,
+			Frequency:      req.Frequency,
+			StartDate:      req.StartDate,
+			Timezone:       req.Timezone,
 		}
 
-		var forecasts []models.ForecastPeriod
-		switch req.ForecastType {
-		case "linear":
-			forecasts, _ = fs.generateLinearForecast(rate, forecastReq)
-		case "exponential":
-			forecasts, _ = fs.generateExponentialForecast(rate, forecastReq)
-		case "moving_average":
-			forecasts, _ = fs.generateMovingAverageForecast(rate, forecastReq)
-		}
-
-		currencyForecasts[currency] = forecasts
+		computeStart := time.Now()
+		currencyForecasts[code], _ = fs.generatePeriods(ctx, rate, forecastReq)
+		metrics.ForecastDuration.WithLabelValues(req.ForecastType).Observe(time.Since(computeStart).Seconds())
 	}
+	computeSpan.End()
 
-	response := &models.MultiCurrencyForecastResponse{
+	response = &models.MultiCurrencyForecastResponse{
 		BaseCurrency: req.BaseCurrency,
-		Amount:       req.Amount,
+		Amount:       fs.baseAmount(req.Amount, req.BaseCurrency),
 		ForecastType: req.ForecastType,
+		Calendar:     req.Calendar,
+		Frequency:    req.Frequency,
+		StartDate:    req.StartDate,
+		Timezone:     req.Timezone,
 		Periods:      req.Periods,
 		Currencies:   currencyForecasts,
-		GeneratedAt:  time.Now(),
+		GeneratedAt:  fs.clock.Now(),
 	}
 
-	fs.logger.Infof("Generated multi-currency forecast for %d currencies", len(currencyForecasts))
+	requestLogger.Infof("Generated multi-currency forecast for %d currencies", len(currencyForecasts))
 	return response, nil
 }
 
 // AnalyzeTrend analyzes the trend for a currency pair
-func (fs *ForecastingService) AnalyzeTrend(ctx context.Context, baseCurrency, targetCurrency string, periods int) (*models.TrendAnalysis, error) {
+func (fs *ForecastingService) AnalyzeTrend(ctx context.Context, baseCurrency, targetCurrency string, periods int) (analysis *models.TrendAnalysis, err error) {
+	baseCurrency, targetCurrency = currency.Normalize(baseCurrency), currency.Normalize(targetCurrency)
+	ctx = logger.ContextWithFields(ctx, logger.Fields{"currency_pair": baseCurrency + "/" + targetCurrency})
+	ctx, span := tracing.Start(ctx, "ForecastingService.AnalyzeTrend")
+	span.SetAttribute("currency.pair", baseCurrency+"/"+targetCurrency)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	// For now, we'll use a simple analysis based on current rates
 	// In a real implementation, you might want to fetch historical data
-	rates, err := fs.currencyClient.GetRates(ctx, baseCurrency)
+	rates, err := fs.getRates(ctx, baseCurrency)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+		return nil, newUpstreamError(err)
 	}
 
 	rate, exists := rates.Rates[targetCurrency]
 	if !exists {
-		return nil, fmt.Errorf("target currency %s not found in exchange rates", targetCurrency)
+		return nil, newNotFoundError(CodeCurrencyNotFound, "target currency %s not found in exchange rates", targetCurrency)
 	}
 
 	// Simple trend analysis (in a real implementation, you'd use historical data)
-	analysis := &models.TrendAnalysis{
+	analysis = &models.TrendAnalysis{
 		CurrencyPair:   fmt.Sprintf("%s/%s", baseCurrency, targetCurrency),
 		Trend:          "sideways", // Placeholder
 		Volatility:     0.05,       // Placeholder
@@ -191,80 +352,322 @@ func (fs *ForecastingService) AnalyzeTrend(ctx context.Context, baseCurrency, ta
 		MinRate:        rate * 0.95,
 		MaxRate:        rate * 1.05,
 		AnalysisPeriod: periods,
-		GeneratedAt:    time.Now(),
+		GeneratedAt:    fs.clock.Now(),
 	}
 
 	return analysis, nil
 }
 
+// GetForecast returns a previously generated forecast by ID
+func (fs *ForecastingService) GetForecast(ctx context.Context, id string) (*models.ForecastResponse, error) {
+	forecast, err := fs.history.Get(ctx, id)
+	if errors.Is(err, history.ErrNotFound) {
+		return nil, newNotFoundError(CodeForecastNotFound, "forecast %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read forecast history: %w", err)
+	}
+	return forecast, nil
+}
+
+// ListForecasts returns one page of previously generated forecasts, newest first
+func (fs *ForecastingService) ListForecasts(ctx context.Context, filter history.Filter) (*models.ForecastListResponse, error) {
+	filter.Pair = currency.Normalize(filter.Pair)
+	if filter.Pair != "" {
+		currencies := strings.Split(filter.Pair, "/")
+		if len(currencies) != 2 || currencies[0] == "" || currencies[1] == "" {
+			return nil, newValidationError(CodeValidationError, "pair must have the form BASE/TARGET")
+		}
+	}
+	if filter.ForecastType != "" && !IsForecastTypeSupported(filter.ForecastType) {
+		return nil, newValidationError(CodeUnsupportedForecastType, "unsupported forecast type: %s", filter.ForecastType)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, newValidationError(CodeValidationError, "from must be before to")
+	}
+
+	page, err := fs.history.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read forecast history: %w", err)
+	}
+	return &models.ForecastListResponse{Forecasts: page.Forecasts, NextCursor: page.NextCursor}, nil
+}
+
 // validateForecastRequest validates the forecast request
 func (fs *ForecastingService) validateForecastRequest(req *models.ForecastRequest) error {
 	if req.BaseCurrency == "" {
-		return fmt.Errorf("base currency is required")
+		return newValidationError(CodeValidationError, "base currency is required")
 	}
 	if req.TargetCurrency == "" {
-		return fmt.Errorf("target currency is required")
+		return newValidationError(CodeValidationError, "target currency is required")
+	}
+	if err := validateAmount(req.Amount); err != nil {
+		return err
 	}
 	if req.Amount <= 0 {
-		return fmt.Errorf("amount must be greater than 0")
+		return newValidationError(CodeValidationError, "amount must be greater than 0")
 	}
 	if req.Periods < 0 {
-		return fmt.Errorf("periods cannot be negative")
+		return newValidationError(CodeValidationError, "periods cannot be negative")
 	}
 	if req.Periods > 365 {
-		return fmt.Errorf("periods cannot exceed 365")
+		return newValidationError(CodeValidationError, "periods cannot exceed 365")
+	}
+	if !isCalendarSupported(req.Calendar) {
+		return newValidationError(CodeUnsupportedCalendar, "unsupported calendar: %s", req.Calendar)
+	}
+	if !isFrequencySupported(req.Frequency) {
+		return newValidationError(CodeUnsupportedFrequency, "unsupported frequency: %s", req.Frequency)
+	}
+	if err := validateHorizon(req.Periods, req.Frequency); err != nil {
+		return err
+	}
+	if err := validateSchedule(req.StartDate, req.Timezone); err != nil {
+		return err
 	}
 
 	// Check if currencies are supported
 	if !fs.isCurrencySupported(req.BaseCurrency) {
-		return fmt.Errorf("base currency %s is not supported", req.BaseCurrency)
+		return newValidationError(CodeUnsupportedCurrency, "base currency %s is not supported", req.BaseCurrency)
 	}
 	if !fs.isCurrencySupported(req.TargetCurrency) {
-		return fmt.Errorf("target currency %s is not supported", req.TargetCurrency)
+		return newValidationError(CodeUnsupportedCurrency, "target currency %s is not supported", req.TargetCurrency)
 	}
 
 	return nil
 }
 
-// isCurrencySupported checks if a currency is supported
-func (fs *ForecastingService) isCurrencySupported(currency string) bool {
-	for _, supported := range fs.config.SupportedCurrencies {
-		if supported == currency {
-			return true
+// validateAmount rejects request amounts that are not finite or too large to convert and compound without overflowing
+func validateAmount(amount float64) error {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) || math.Abs(amount) > maxAmount {
+		return newValidationError(CodeValidationError, "amount must be a finite number no larger than %.0f", maxAmount)
+	}
+	return nil
+}
+
+// validateHorizon rejects forecasts whose last period falls more than maxHorizonDays ahead at the given frequency
+func validateHorizon(periods int, frequency string) error {
+	limit := int(math.Floor(maxHorizonDays/periodDays(frequency) + 1e-9))
+	if periods > limit {
+		if frequency == "" {
+			frequency = "daily"
 		}
+		return newValidationError(CodeValidationError, "periods cannot exceed %d at %s frequency", limit, frequency)
+	}
+	return nil
+}
+
+// modelFor returns the forecast defaults for a pair, applying any per-pair override over the global defaults
+func (fs *ForecastingService) modelFor(baseCurrency, targetCurrency string) config.ModelConfig {
+	cfg := fs.config.Load()
+	model := config.ModelConfig{ForecastType: cfg.DefaultForecastType, Periods: cfg.DefaultForecastPeriods}
+	if model.ForecastType == "" {
+		model.ForecastType = "linear"
+	}
+
+	if override, ok := cfg.ModelPairs[baseCurrency+"/"+targetCurrency]; ok {
+		if override.ForecastType != "" {
+			model.ForecastType = override.ForecastType
+		}
+		if override.Periods != 0 {
+			model.Periods = override.Periods
+		}
+	}
+	return model
+}
+
+// isCurrencySupported checks if a normalized currency code is supported
+func (fs *ForecastingService) isCurrencySupported(code string) bool {
+	return (*fs.supported.Load())[code]
+}
+
+// IsForecastTypeSupported checks if a forecast type is implemented
+func IsForecastTypeSupported(forecastType string) bool {
+	switch forecastType {
+	case "linear", "exponential", "moving_average":
+		return true
+	}
+	return false
+}
+
+// isCalendarSupported checks if a forecast calendar is known; empty selects the default
+func isCalendarSupported(name string) bool {
+	switch name {
+	case "", calendar.Daily, calendar.Weekdays, calendar.Business:
+		return true
 	}
 	return false
 }
 
+// isFrequencySupported checks if a forecast frequency is known; empty selects daily
+func isFrequencySupported(frequency string) bool {
+	switch frequency {
+	case "", "hourly", "daily", "weekly", "monthly", "quarterly":
+		return true
+	}
+	return false
+}
+
+// periodDays returns the length of one forecast period in days; model trends are defined per day
+func periodDays(frequency string) float64 {
+	switch frequency {
+	case "hourly":
+		return 1.0 / 24
+	case "weekly":
+		return 7
+	case "monthly":
+		return 365.25 / 12
+	case "quarterly":
+		return 365.25 / 4
+	default:
+		return 1
+	}
+}
+
+// forecastDates returns the date of each forecast period after start at the request's frequency, skipping the days the request's calendar closes for its pair
+func forecastDates(req *models.ForecastRequest, start time.Time) []string {
+	return periodDates(req, start, periodCalendar(req.Calendar, req.BaseCurrency, req.TargetCurrency))
+}
+
+// periodCalendar returns the calendar a forecast over currencies steps over, or nil when every day is open
+func periodCalendar(name string, currencies ...string) *calendar.Calendar {
+	switch name {
+	case calendar.Weekdays:
+		return calendar.WeekendsOnly()
+	case calendar.Business:
+		members := make([]*calendar.Calendar, len(currencies))
+		for i, code := range currencies {
+			members[i] = calendar.ForCurrency(code)
+		}
+		return calendar.Joint(members...)
+	}
+	return nil
+}
+
+// periodDates returns the date of each forecast period after start at the request's frequency, skipping the days periodCalendar closes.
+// Hourly periods are RFC 3339 timestamps; the others are dates, with weekly and longer periods rolled to a business day by modified following.
+func periodDates(req *models.ForecastRequest, start time.Time, periodCalendar *calendar.Calendar) []string {
+	dates := make([]string, req.Periods)
+	switch req.Frequency {
+	case "hourly":
+		date := time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), 0, 0, 0, start.Location())
+		for i := range dates {
+			date = date.Add(time.Hour)
+			for periodCalendar != nil && !periodCalendar.IsBusinessDay(date) {
+				date = date.Add(time.Hour)
+			}
+			dates[i] = date.Format(time.RFC3339)
+		}
+	case "weekly", "monthly", "quarterly":
+		for i := range dates {
+			var date time.Time
+			switch req.Frequency {
+			case "weekly":
+				date = start.AddDate(0, 0, 7*(i+1))
+			case "monthly":
+				date = addMonths(start, i+1)
+			default:
+				date = addMonths(start, 3*(i+1))
+			}
+			if periodCalendar != nil {
+				date = periodCalendar.ModifiedFollowing(date)
+			}
+			dates[i] = date.Format("2006-01-02")
+		}
+	default:
+		date := start
+		for i := range dates {
+			if periodCalendar == nil {
+				date = date.AddDate(0, 0, 1)
+			} else {
+				date = periodCalendar.AddBusinessDays(date, 1)
+			}
+			dates[i] = date.Format("2006-01-02")
+		}
+	}
+	return dates
+}
+
+// addMonths adds months to date, clamping to the end of shorter months so Jan 31 + 1 month is Feb 28 or 29
+func addMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(date.Day(), lastDay)-1)
+}
+
 // generateCacheKey generates a cache key for the request
 func (fs *ForecastingService) generateCacheKey(req *models.ForecastRequest) string {
-	return fmt.Sprintf("%s_%s_%s_%d_%d", req.BaseCurrency, req.TargetCurrency, req.ForecastType, int(req.Amount), req.Periods)
+	return fmt.Sprintf("%s_%s_%s_%s_%s_%s_%s_%s_%d", req.BaseCurrency, req.TargetCurrency, req.ForecastType, req.Calendar, req.Frequency, req.StartDate, req.Timezone, money.NewFromFloat(req.Amount), req.Periods)
+}
+
+// AmountsAsStrings reports whether responses should encode amounts as JSON strings rather than numbers
+func (fs *ForecastingService) AmountsAsStrings() bool {
+	return fs.config.Load().AmountsAsStrings
+}
+
+// roundingMode returns the configured rounding mode for amounts
+func (fs *ForecastingService) roundingMode() money.RoundingMode {
+	mode, err := money.ParseRoundingMode(fs.config.Load().RoundingMode)
+	if err != nil {
+		return money.RoundHalfUp
+	}
+	return mode
 }
 
-// generateLinearForecast generates a linear forecast
-func (fs *ForecastingService) generateLinearForecast(currentRate float64, req *models.ForecastRequest) ([]models.ForecastPeriod, float64) {
+// baseAmount converts a request amount to a decimal rounded to the minor unit of its currency
+func (fs *ForecastingService) baseAmount(amount float64, currency string) money.Decimal {
+	return money.RoundAmount(money.NewFromFloat(amount), currency, fs.roundingMode())
+}
+
+// convertAmount converts a request amount at rate in decimal arithmetic, rounded to the minor unit of the target currency
+func (fs *ForecastingService) convertAmount(req *models.ForecastRequest, rate float64) money.Decimal {
+	converted := fs.baseAmount(req.Amount, req.BaseCurrency).Mul(money.NewFromFloat(rate))
+	return money.RoundAmount(converted, req.TargetCurrency, fs.roundingMode())
+}
+
+// generatePeriods runs the model selected by the request's forecast type, which must already be validated, on the pair's
+// rate history resampled to the request's frequency
+func (fs *ForecastingService) generatePeriods(ctx context.Context, currentRate float64, req *models.ForecastRequest) ([]models.ForecastPeriod, float64) {
+	samples := fs.resampleRates(ctx, currentRate, req)
+	switch req.ForecastType {
+	case "exponential":
+		return fs.generateExponentialForecast(currentRate, req, samples)
+	case "moving_average":
+		return fs.generateMovingAverageForecast(currentRate, req, samples)
+	default:
+		return fs.generateLinearForecast(currentRate, req, samples)
+	}
+}
+
+// generateLinearForecast generates a linear forecast; the trend is linear in the log of the rate, so a falling rate
+// approaches zero but never crosses it
+func (fs *ForecastingService) generateLinearForecast(currentRate float64, req *models.ForecastRequest, samples rateSamples) ([]models.ForecastPeriod, float64) {
 	forecasts := make([]models.ForecastPeriod, req.Periods)
+	dates := forecastDates(req, fs.forecastStart(req))
+	step := periodDays(req.Frequency)
 
-	// Simple linear trend (in a real implementation, you'd use more sophisticated algorithms)
-	trend := 0.001 // 0.1% change per period
+	// Fit the trend to resampled history when there is enough, otherwise assume 0.1% change per day
+	trend := 0.001
+	if samples.usable() {
+		trend = samples.linearTrend()
+	}
 
 	for i := 0; i < req.Periods; i++ {
 		period := i + 1
-		rate := currentRate * (1 + trend*float64(period))
-		amount := req.Amount * rate
+		rate := currentRate * math.Exp(trend*step*float64(period))
 
 		var change, changePercent float64
 		if i > 0 {
-			prevRate := currentRate * (1 + trend*float64(i))
+			prevRate := currentRate * math.Exp(trend*step*float64(i))
 			change = rate - prevRate
 			changePercent = (change / prevRate) * 100
 		}
 
 		forecasts[i] = models.ForecastPeriod{
 			Period:        period,
-			Date:          time.Now().AddDate(0, 0, period).Format("2006-01-02"),
+			Date:          dates[i],
 			Rate:          math.Round(rate*10000) / 10000, // Round to 4 decimal places
-			Amount:        math.Round(amount*100) / 100,   // Round to 2 decimal places
+			Amount:        fs.convertAmount(req, rate),
 			Change:        math.Round(change*10000) / 10000,
 			ChangePercent: math.Round(changePercent*100) / 100,
 		}
@@ -275,29 +678,33 @@ func (fs *ForecastingService) generateLinearForecast(currentRate float64, req *m
 }
 
 // generateExponentialForecast generates an exponential forecast
-func (fs *ForecastingService) generateExponentialForecast(currentRate float64, req *models.ForecastRequest) ([]models.ForecastPeriod, float64) {
+func (fs *ForecastingService) generateExponentialForecast(currentRate float64, req *models.ForecastRequest, samples rateSamples) ([]models.ForecastPeriod, float64) {
 	forecasts := make([]models.ForecastPeriod, req.Periods)
+	dates := forecastDates(req, fs.forecastStart(req))
+	step := periodDays(req.Frequency)
 
-	// Simple exponential trend
-	growthRate := 0.002 // 0.2% growth per period
+	// Compound the growth of resampled history when there is enough, otherwise assume 0.2% growth per day
+	growthRate := 0.002
+	if samples.usable() {
+		growthRate = samples.growthRate()
+	}
 
 	for i := 0; i < req.Periods; i++ {
 		period := i + 1
-		rate := currentRate * math.Pow(1+growthRate, float64(period))
-		amount := req.Amount * rate
+		rate := currentRate * math.Pow(1+growthRate, step*float64(period))
 
 		var change, changePercent float64
 		if i > 0 {
-			prevRate := currentRate * math.Pow(1+growthRate, float64(i))
+			prevRate := currentRate * math.Pow(1+growthRate, step*float64(i))
 			change = rate - prevRate
 			changePercent = (change / prevRate) * 100
 		}
 
 		forecasts[i] = models.ForecastPeriod{
 			Period:        period,
-			Date:          time.Now().AddDate(0, 0, period).Format("2006-01-02"),
+			Date:          dates[i],
 			Rate:          math.Round(rate*10000) / 10000,
-			Amount:        math.Round(amount*100) / 100,
+			Amount:        fs.convertAmount(req, rate),
 			Change:        math.Round(change*10000) / 10000,
 			ChangePercent: math.Round(changePercent*100) / 100,
 		}
@@ -308,23 +715,27 @@ func (fs *ForecastingService) generateExponentialForecast(currentRate float64, r
 }
 
 // generateMovingAverageForecast generates a moving average forecast
-func (fs *ForecastingService) generateMovingAverageForecast(currentRate float64, req *models.ForecastRequest) ([]models.ForecastPeriod, float64) {
+func (fs *ForecastingService) generateMovingAverageForecast(currentRate float64, req *models.ForecastRequest, samples rateSamples) ([]models.ForecastPeriod, float64) {
 	forecasts := make([]models.ForecastPeriod, req.Periods)
+	dates := forecastDates(req, fs.forecastStart(req))
+	step := periodDays(req.Frequency)
 
-	// Simple moving average with some volatility
+	// Average the latest resampled closes when there are enough, with some volatility
 	baseRate := currentRate
+	if samples.usable() {
+		baseRate = samples.movingAverage()
+	}
 	volatility := 0.01 // 1% volatility
 
 	for i := 0; i < req.Periods; i++ {
 		period := i + 1
 		// Add some random-like variation based on period
-		variation := math.Sin(float64(period)*0.1) * volatility
+		variation := math.Sin(step*float64(period)*0.1) * volatility
 		rate := baseRate * (1 + variation)
-		amount := req.Amount * rate
 
 		var change, changePercent float64
 		if i > 0 {
-			prevVariation := math.Sin(float64(i)*0.1) * volatility
+			prevVariation := math.Sin(step*float64(i)*0.1) * volatility
 			prevRate := baseRate * (1 + prevVariation)
 			change = rate - prevRate
 			changePercent = (change / prevRate) * 100
@@ -332,9 +743,9 @@ func (fs *ForecastingService) generateMovingAverageForecast(currentRate float64,
 
 		forecasts[i] = models.ForecastPeriod{
 			Period:        period,
-			Date:          time.Now().AddDate(0, 0, period).Format("2006-01-02"),
+			Date:          dates[i],
 			Rate:          math.Round(rate*10000) / 10000,
-			Amount:        math.Round(amount*100) / 100,
+			Amount:        fs.convertAmount(req, rate),
 			Change:        math.Round(change*10000) / 10000,
 			ChangePercent: math.Round(changePercent*100) / 100,
 		}
@@ -1,12 +1,22 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dalfonso89/financial-forecasting-service/config"
+	"github.com/dalfonso89/financial-forecasting-service/history"
 	"github.com/dalfonso89/financial-forecasting-service/logger"
 	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
 )
 
 // TestNewForecastingService tests the service constructor
@@ -23,7 +33,7 @@ func TestForecastingService_NewForecastingService(t *testing.T) {
 		t.Fatal("Expected service to be created, got nil")
 	}
 
-	if service.config != cfg {
+	if service.config.Load() != cfg {
 		t.Error("Expected config to be set correctly")
 	}
 
@@ -137,6 +147,154 @@ func TestForecastingService_AnalyzeTrend(t *testing.T) {
 	}
 }
 
+// TestForecastingService_GenerateForecast_Tracing tests that forecast spans share one trace with the upstream call
+func TestForecastingService_GenerateForecast_Tracing(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	previous := tracing.Default()
+	tracing.SetDefault(tracing.NewTracer(exporter))
+	defer tracing.SetDefault(previous)
+
+	var upstreamTraceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamTraceparent = r.Header.Get(tracing.TraceparentHeader)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR"},
+		CurrencyExchangeServiceURL: server.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+		ForecastCacheTTL:           time.Minute,
+	}
+	service := NewForecastingService(cfg, logger.New("debug"))
+
+	_, err := service.GenerateForecast(context.Background(), &models.ForecastRequest{
+		BaseCurrency:   "USD",
+		TargetCurrency: "EUR",
+		Amount:         1000,
+		Periods:        3,
+		ForecastType:   "linear",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	spansByName := make(map[string]tracing.SpanData)
+	for _, span := range exporter.Spans() {
+		spansByName[span.Name] = span
+	}
+	root, ok := spansByName["ForecastingService.GenerateForecast"]
+	if !ok {
+		t.Fatalf("Expected ForecastingService.GenerateForecast span, got %v", exporter.Spans())
+	}
+	for _, name := range []string{"forecast.cache_lookup", "CurrencyClient.get_rates", "forecast.compute"} {
+		span, ok := spansByName[name]
+		if !ok {
+			t.Errorf("Expected %s span to be exported", name)
+			continue
+		}
+		if span.SpanContext.TraceID != root.SpanContext.TraceID {
+			t.Errorf("Expected %s to share trace %s, got %s", name, root.SpanContext.TraceID, span.SpanContext.TraceID)
+		}
+		if span.ParentSpanID != root.SpanContext.SpanID {
+			t.Errorf("Expected %s to be a child of the service span", name)
+		}
+	}
+	if spansByName["forecast.compute"].Attributes["forecast.type"] != "linear" {
+		t.Errorf("Expected forecast.type attribute linear, got %v", spansByName["forecast.compute"].Attributes["forecast.type"])
+	}
+
+	remote, err := tracing.ParseTraceparent(upstreamTraceparent)
+	if err != nil {
+		t.Fatalf("Expected upstream request to carry a traceparent, got %q", upstreamTraceparent)
+	}
+	if remote.TraceID != root.SpanContext.TraceID {
+		t.Errorf("Expected upstream traceparent to carry trace %s, got %s", root.SpanContext.TraceID, remote.TraceID)
+	}
+}
+
+// TestForecastingService_GenerateForecast_LogFields tests that service and client logs carry request-scoped fields
+func TestForecastingService_GenerateForecast_LogFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR"},
+		CurrencyExchangeServiceURL: server.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+	}
+	loggerInstance := logger.New("debug")
+	var buf bytes.Buffer
+	loggerInstance.(*logger.LogrusLogger).SetOutput(&buf)
+	service := NewForecastingService(cfg, loggerInstance)
+
+	ctx := logger.ContextWithFields(context.Background(), logger.Fields{"request_id": "req-123", "route": "/api/v1/forecast"})
+	_, err := service.GenerateForecast(ctx, &models.ForecastRequest{
+		BaseCurrency:   "USD",
+		TargetCurrency: "EUR",
+		Amount:         1000,
+		Periods:        3,
+		ForecastType:   "linear",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var sawClient, sawService bool
+	for _, line := range lines {
+		for _, expected := range []string{`"request_id":"req-123"`, `"route":"/api/v1/forecast"`, `"currency_pair":"USD/EUR"`} {
+			if !strings.Contains(line, expected) {
+				t.Errorf("Expected log line to contain %s, got: %s", expected, line)
+			}
+		}
+		sawClient = sawClient || strings.Contains(line, "Fetching rates")
+		sawService = sawService || strings.Contains(line, "Generated linear forecast")
+	}
+	if !sawClient || !sawService {
+		t.Errorf("Expected both client and service log lines, got: %s", buf.String())
+	}
+}
+
+// TestForecastingService_modelFor tests per-pair model defaults
+func TestForecastingService_modelFor(t *testing.T) {
+	cfg := &config.Config{
+		DefaultForecastPeriods: 30,
+		DefaultForecastType:    "exponential",
+		ModelPairs: map[string]config.ModelConfig{
+			"USD/JPY": {ForecastType: "moving_average", Periods: 60},
+			"USD/EUR": {Periods: 7},
+		},
+	}
+	service := NewForecastingService(cfg, logger.New("error"))
+
+	tests := []struct {
+		base, target string
+		expected     config.ModelConfig
+	}{
+		{"USD", "JPY", config.ModelConfig{ForecastType: "moving_average", Periods: 60}},
+		{"USD", "EUR", config.ModelConfig{ForecastType: "exponential", Periods: 7}},
+		{"EUR", "USD", config.ModelConfig{ForecastType: "exponential", Periods: 30}},
+	}
+
+	for _, tt := range tests {
+		if got := service.modelFor(tt.base, tt.target); got != tt.expected {
+			t.Errorf("modelFor(%s, %s) = %+v, expected %+v", tt.base, tt.target, got, tt.expected)
+		}
+	}
+
+	// Configs built without a default type keep the historical linear default
+	service = NewForecastingService(&config.Config{DefaultForecastPeriods: 30}, logger.New("error"))
+	if got := service.modelFor("USD", "EUR").ForecastType; got != "linear" {
+		t.Errorf("Expected linear default, got %s", got)
+	}
+}
+
 // TestForecastingService_validateForecastRequest tests request validation
 func TestForecastingService_validateForecastRequest(t *testing.T) {
 	cfg := &config.Config{
@@ -206,6 +364,33 @@ func TestForecastingService_validateForecastRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "NaN amount",
+			request: &models.ForecastRequest{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Amount:         math.NaN(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "infinite amount",
+			request: &models.ForecastRequest{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Amount:         math.Inf(1),
+			},
+			wantErr: true,
+		},
+		{
+			name: "amount too large",
+			request: &models.ForecastRequest{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Amount:         1e300,
+			},
+			wantErr: true,
+		},
 		{
 			name: "negative periods",
 			request: &models.ForecastRequest{
@@ -236,6 +421,68 @@ func TestForecastingService_validateForecastRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "business calendar",
+			request: &models.ForecastRequest{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Amount:         1000,
+				Calendar:       "business",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown calendar",
+			request: &models.ForecastRequest{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Amount:         1000,
+				Calendar:       "lunar",
+			},
+			wantErr: true,
+		},
+		{
+			name: "monthly frequency",
+			request: &models.ForecastRequest{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Amount:         1000,
+				Frequency:      "monthly",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown frequency",
+			request: &models.ForecastRequest{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Amount:         1000,
+				Frequency:      "fortnightly",
+			},
+			wantErr: true,
+		},
+		{
+			name: "quarterly horizon of five years",
+			request: &models.ForecastRequest{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Amount:         1000,
+				Periods:        20,
+				Frequency:      "quarterly",
+			},
+			wantErr: false,
+		},
+		{
+			name: "quarterly horizon beyond five years",
+			request: &models.ForecastRequest{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Amount:         1000,
+				Periods:        21,
+				Frequency:      "quarterly",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -261,7 +508,7 @@ func TestForecastingService_generateLinearForecast(t *testing.T) {
 		Periods:        5,
 	}
 
-	forecasts, confidence := service.generateLinearForecast(1.2, req)
+	forecasts, confidence := service.generateLinearForecast(1.2, req, rateSamples{})
 
 	if len(forecasts) != 5 {
 		t.Errorf("Expected 5 forecasts, got %d", len(forecasts))
@@ -306,7 +553,7 @@ func TestForecastingService_generateLinearForecast_EdgeCases(t *testing.T) {
 				Periods:        tt.periods,
 			}
 
-			forecasts, confidence := service.generateLinearForecast(tt.currentRate, req)
+			forecasts, confidence := service.generateLinearForecast(tt.currentRate, req, rateSamples{})
 
 			if tt.periods == 0 {
 				if len(forecasts) != 0 {
@@ -331,8 +578,8 @@ func TestForecastingService_generateLinearForecast_EdgeCases(t *testing.T) {
 				if forecast.Rate <= 0 {
 					t.Errorf("Expected positive rate, got %f", forecast.Rate)
 				}
-				if forecast.Amount <= 0 {
-					t.Errorf("Expected positive amount, got %f", forecast.Amount)
+				if forecast.Amount.Sign() <= 0 {
+					t.Errorf("Expected positive amount, got %s", forecast.Amount)
 				}
 			}
 		})
@@ -353,7 +600,7 @@ func TestForecastingService_generateExponentialForecast(t *testing.T) {
 		Periods:        5,
 	}
 
-	forecasts, confidence := service.generateExponentialForecast(currentRate, req)
+	forecasts, confidence := service.generateExponentialForecast(currentRate, req, rateSamples{})
 
 	if len(forecasts) != 5 {
 		t.Errorf("Expected 5 forecasts, got %d", len(forecasts))
@@ -392,7 +639,7 @@ func TestForecastingService_generateMovingAverageForecast(t *testing.T) {
 		Periods:        5,
 	}
 
-	forecasts, confidence := service.generateMovingAverageForecast(currentRate, req)
+	forecasts, confidence := service.generateMovingAverageForecast(currentRate, req, rateSamples{})
 
 	if len(forecasts) != 5 {
 		t.Errorf("Expected 5 forecasts, got %d", len(forecasts))
@@ -500,4 +747,251 @@ func TestForecastingService_generateCacheKey(t *testing.T) {
 	if key1 == key3 {
 		t.Error("Expected different cache keys for different requests")
 	}
+
+	businessReq := *req
+	businessReq.Calendar = "business"
+	if service.generateCacheKey(&businessReq) == key1 {
+		t.Error("Expected different cache keys for different calendars")
+	}
+}
+
+// TestForecastDates_Frequency tests period spacing and date formats for each frequency
+func TestForecastDates_Frequency(t *testing.T) {
+	start := time.Date(2025, 1, 31, 22, 30, 0, 0, time.UTC) // Friday
+	tests := []struct {
+		frequency string
+		calendar  string
+		expected  []string
+	}{
+		{"hourly", "", []string{"2025-01-31T23:00:00Z", "2025-02-01T00:00:00Z", "2025-02-01T01:00:00Z"}},
+		{"hourly", "weekdays", []string{"2025-01-31T23:00:00Z", "2025-02-03T00:00:00Z", "2025-02-03T01:00:00Z"}},
+		{"daily", "", []string{"2025-02-01", "2025-02-02", "2025-02-03"}},
+		{"weekly", "", []string{"2025-02-07", "2025-02-14", "2025-02-21"}},
+		{"monthly", "", []string{"2025-02-28", "2025-03-31", "2025-04-30"}},
+		{"monthly", "business", []string{"2025-02-28", "2025-03-31", "2025-04-30"}},
+		{"quarterly", "", []string{"2025-04-30", "2025-07-31", "2025-10-31"}},
+		{"quarterly", "business", []string{"2025-04-30", "2025-07-31", "2025-10-31"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.frequency+" "+tt.calendar, func(t *testing.T) {
+			req := &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Periods: len(tt.expected), Calendar: tt.calendar, Frequency: tt.frequency}
+			dates := forecastDates(req, start)
+			if strings.Join(dates, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, dates)
+			}
+		})
+	}
+}
+
+// TestForecastingService_generateLinearForecast_Frequency tests that the daily trend scales with the period length
+func TestForecastingService_generateLinearForecast_Frequency(t *testing.T) {
+	service := NewForecastingService(&config.Config{}, logger.New("error"))
+	daily, _ := service.generateLinearForecast(1, &models.ForecastRequest{Amount: 1, Periods: 7, Frequency: "daily"}, rateSamples{})
+	weekly, _ := service.generateLinearForecast(1, &models.ForecastRequest{Amount: 1, Periods: 1, Frequency: "weekly"}, rateSamples{})
+	if weekly[0].Rate != daily[6].Rate {
+		t.Errorf("Expected one weekly period to match seven daily periods, got %.4f and %.4f", weekly[0].Rate, daily[6].Rate)
+	}
+}
+
+// TestForecastDates tests that period dates step over the days the calendar closes
+func TestForecastDates(t *testing.T) {
+	start := time.Date(2025, 12, 23, 15, 0, 0, 0, time.UTC) // Tuesday
+	tests := []struct {
+		calendar string
+		target   string
+		expected []string
+	}{
+		{"", "EUR", []string{"2025-12-24", "2025-12-25", "2025-12-26", "2025-12-27"}},
+		{"calendar", "EUR", []string{"2025-12-24", "2025-12-25", "2025-12-26", "2025-12-27"}},
+		{"weekdays", "EUR", []string{"2025-12-24", "2025-12-25", "2025-12-26", "2025-12-29"}},
+		{"business", "EUR", []string{"2025-12-24", "2025-12-29", "2025-12-30", "2025-12-31"}},
+		{"business", "JPY", []string{"2025-12-24", "2025-12-26", "2025-12-29", "2025-12-30"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.calendar+" "+tt.target, func(t *testing.T) {
+			req := &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: tt.target, Periods: len(tt.expected), Calendar: tt.calendar}
+			dates := forecastDates(req, start)
+			if strings.Join(dates, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, dates)
+			}
+		})
+	}
+}
+
+// TestForecastingService_UpdateConfig tests that reloaded settings take effect on the next request
+func TestForecastingService_UpdateConfig(t *testing.T) {
+	var upstreamCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85,"GBP":0.75}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR"},
+		CurrencyExchangeServiceURL: server.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+		ForecastCacheTTL:           time.Hour,
+		DefaultForecastPeriods:     5,
+	}
+	service := NewForecastingService(cfg, logger.New("error"))
+	newRequest := func(target string) *models.ForecastRequest {
+		return &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: target, Amount: 100}
+	}
+
+	if _, err := service.GenerateForecast(context.Background(), newRequest("GBP")); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected GBP to be rejected before reload, got %v", err)
+	}
+	if _, err := service.GenerateForecast(context.Background(), newRequest("EUR")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	updated := *cfg
+	updated.SupportedCurrencies = []string{"USD", "EUR", "GBP"}
+	updated.DefaultForecastPeriods = 7
+	updated.ForecastCacheTTL = time.Nanosecond
+	service.UpdateConfig(&updated)
+
+	response, err := service.GenerateForecast(context.Background(), newRequest("GBP"))
+	if err != nil {
+		t.Fatalf("Expected GBP to be accepted after reload, got %v", err)
+	}
+	if response.Periods != 7 {
+		t.Errorf("Expected reloaded default periods 7, got %d", response.Periods)
+	}
+
+	// The shorter TTL expires the EUR forecast cached before the reload
+	callsBefore := upstreamCalls
+	if _, err := service.GenerateForecast(context.Background(), &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 100, Periods: 5}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if upstreamCalls != callsBefore+1 {
+		t.Errorf("Expected expired cache entry to be recomputed, got %d upstream calls", upstreamCalls-callsBefore)
+	}
+}
+
+// failingHistory is a history store whose writes always fail
+type failingHistory struct {
+	*history.MemoryStore
+}
+
+// Save implements history.Store
+func (failingHistory) Save(ctx context.Context, forecast *models.ForecastResponse) error {
+	return errors.New("disk full")
+}
+
+// newTestService returns a service pinned to 2025-03-10 whose upstream quotes USD at 0.8 EUR and 150 JPY
+func newTestService(t *testing.T) *ForecastingService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1741564800,"rates":{"EUR":0.8,"JPY":150}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR", "JPY", "GBP"},
+		CurrencyExchangeServiceURL: server.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+		DefaultForecastPeriods:     30,
+	}
+	service := NewForecastingService(cfg, logger.New("error"))
+	service.SetClock(ClockFunc(func() time.Time { return time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC) }))
+	return service
+}
+
+// seedRateHistory records 40 days of USD snapshots in which JPY moves with EUR (sign 1) or against it (sign -1)
+func seedRateHistory(service *ForecastingService, sign float64) {
+	store := ratehistory.NewMemoryStore()
+	start := time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < 40; day++ {
+		shock := 0.01 * math.Sin(float64(day))
+		store.Record(context.Background(), ratehistory.Snapshot{
+			Base:  "USD",
+			Date:  ratehistory.DateOf(start.AddDate(0, 0, day)),
+			Rates: map[string]float64{"EUR": 0.8 * math.Exp(shock), "JPY": 150 * math.Exp(sign*shock)},
+		})
+	}
+	service.SetRateHistory(store)
+}
+
+// TestForecastingService_History tests that generated forecasts are recorded and retrievable
+func TestForecastingService_History(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base":"USD","timestamp":1640995200,"rates":{"EUR":0.85}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		SupportedCurrencies:        []string{"USD", "EUR"},
+		CurrencyExchangeServiceURL: server.URL,
+		CurrencyExchangeTimeout:    5 * time.Second,
+	}
+	request := func() *models.ForecastRequest {
+		return &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 100, Periods: 3, ForecastType: "linear"}
+	}
+
+	service := NewForecastingService(cfg, logger.New("error"))
+	response, err := service.GenerateForecast(context.Background(), request())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	stored, err := service.GetForecast(context.Background(), response.ForecastID)
+	if err != nil {
+		t.Fatalf("Expected forecast %s in history, got %v", response.ForecastID, err)
+	}
+	if stored.CurrentRate != response.CurrentRate || len(stored.Forecasts) != 3 {
+		t.Errorf("Expected stored forecast to match, got %+v", stored)
+	}
+
+	// A cached response is the same forecast and keeps its ID
+	cached, _ := service.GenerateForecast(context.Background(), request())
+	if cached.ForecastID != response.ForecastID {
+		t.Errorf("Expected cached forecast ID %s, got %s", response.ForecastID, cached.ForecastID)
+	}
+
+	if _, err := service.GetForecast(context.Background(), "missing"); !errors.Is(err, ErrNotFound) || ErrorCode(err) != CodeForecastNotFound {
+		t.Errorf("Expected %s, got %v", CodeForecastNotFound, err)
+	}
+
+	// History failures are logged and do not fail the request
+	service = NewForecastingServiceWithHistory(cfg, logger.New("error"), failingHistory{history.NewMemoryStore()})
+	response, err = service.GenerateForecast(context.Background(), request())
+	if err != nil {
+		t.Fatalf("Expected forecast despite history failure, got %v", err)
+	}
+	if response.ForecastID != "" {
+		t.Errorf("Expected no forecast_id when history is unavailable, got %s", response.ForecastID)
+	}
+}
+
+// TestForecastingService_convertAmount tests decimal conversion rounded to the target currency's minor unit
+func TestForecastingService_convertAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		target   string
+		amount   float64
+		rate     float64
+		expected string
+	}{
+		{"two decimals", "half_up", "EUR", 1000, 0.8585, "858.50"},
+		{"no binary drift", "half_up", "EUR", 0.1, 3, "0.30"},
+		{"yen has no minor unit", "half_up", "JPY", 1000, 150.12345, "150123"},
+		{"dinar has three decimals", "half_up", "KWD", 1000, 0.30745, "307.450"},
+		{"half up", "half_up", "EUR", 1, 0.125, "0.13"},
+		{"half even", "half_even", "EUR", 1, 0.125, "0.12"},
+		{"unset mode rounds half up", "", "EUR", 1, 0.125, "0.13"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewForecastingService(&config.Config{RoundingMode: tt.mode}, logger.New("error"))
+			req := &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: tt.target, Amount: tt.amount}
+			if got := service.convertAmount(req, tt.rate).String(); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
 }
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// DependencyCheck probes one dependency of the forecasting service
+type DependencyCheck struct {
+	Name string
+	// Critical checks make the service not ready when they fail; others only degrade it
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// DependencyChecks returns the readiness probes for the service's dependencies
+func (fs *ForecastingService) DependencyChecks() []DependencyCheck {
+	return []DependencyCheck{
+		{Name: "currency_exchange_service", Critical: true, Check: fs.checkUpstream},
+		{Name: "forecast_cache", Critical: false, Check: fs.checkCache},
+		{Name: "forecast_history", Critical: false, Check: fs.history.Ping},
+		{Name: "rate_history", Critical: false, Check: fs.rateHistory.Ping},
+	}
+}
+
+// checkUpstream verifies the currency exchange service answers its health endpoint
+func (fs *ForecastingService) checkUpstream(ctx context.Context) error {
+	if err := fs.currencyClient.HealthCheck(ctx); err != nil {
+		return newUpstreamError(err)
+	}
+	return nil
+}
+
+// checkCache verifies the forecast cache is initialized and its lock can be acquired
+func (fs *ForecastingService) checkCache(ctx context.Context) error {
+	fs.cacheMutex.RLock()
+	initialized := fs.cache != nil
+	fs.cacheMutex.RUnlock()
+
+	if !initialized {
+		return errors.New("forecast cache is not initialized")
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/calendar"
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+	"github.com/dalfonso89/financial-forecasting-service/curve"
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
+)
+
+// GenerateHedgingAnalysis prices a forward for every forecast period by covered interest parity from the interest rate
+// curves, and compares converting the amount at the forward with converting it at the model's forecast rate
+func (fs *ForecastingService) GenerateHedgingAnalysis(ctx context.Context, req *models.HedgingRequest) (response *models.HedgingResponse, err error) {
+	req.BaseCurrency, req.TargetCurrency = currency.Normalize(req.BaseCurrency), currency.Normalize(req.TargetCurrency)
+	ctx = logger.ContextWithFields(ctx, logger.Fields{"currency_pair": req.BaseCurrency + "/" + req.TargetCurrency})
+	requestLogger := fs.logger.WithContext(ctx)
+	ctx, span := tracing.Start(ctx, "ForecastingService.GenerateHedgingAnalysis")
+	span.SetAttribute("currency.pair", req.BaseCurrency+"/"+req.TargetCurrency)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// The unhedged leg is an ordinary forecast of the pair
+	forecastReq := &models.ForecastRequest{
+		BaseCurrency:   req.BaseCurrency,
+		TargetCurrency: req.TargetCurrency,
+		Amount:         req.Amount,
+		Periods:        req.Periods,
+		ForecastType:   req.ForecastType,
+		Calendar:       req.Calendar,
+		Frequency:      req.Frequency,
+		StartDate:      req.StartDate,
+		Timezone:       req.Timezone,
+	}
+	if err := fs.validateForecastRequest(forecastReq); err != nil {
+		return nil, err
+	}
+
+	// Set defaults
+	model := fs.modelFor(req.BaseCurrency, req.TargetCurrency)
+	if forecastReq.Periods == 0 {
+		forecastReq.Periods = model.Periods
+	}
+	if forecastReq.ForecastType == "" {
+		forecastReq.ForecastType = model.ForecastType
+	}
+	if forecastReq.Calendar == "" {
+		forecastReq.Calendar = calendar.Daily
+	}
+	if forecastReq.Frequency == "" {
+		forecastReq.Frequency = "daily"
+	}
+	if forecastReq.Timezone == "" {
+		forecastReq.Timezone = defaultTimezone
+	}
+	if !IsForecastTypeSupported(forecastReq.ForecastType) {
+		return nil, newValidationError(CodeUnsupportedForecastType, "unsupported forecast type: %s", forecastReq.ForecastType)
+	}
+	span.SetAttribute("forecast.type", forecastReq.ForecastType)
+	span.SetAttribute("forecast.periods", forecastReq.Periods)
+
+	curves := *fs.curves.Load()
+	baseCurve, exists := curves[req.BaseCurrency]
+	if !exists {
+		return nil, newNotFoundError(CodeCurveNotFound, "no interest rate curve is configured for %s", req.BaseCurrency)
+	}
+	targetCurve, exists := curves[req.TargetCurrency]
+	if !exists {
+		return nil, newNotFoundError(CodeCurveNotFound, "no interest rate curve is configured for %s", req.TargetCurrency)
+	}
+
+	rates, err := fs.getRates(ctx, req.BaseCurrency)
+	if err != nil {
+		return nil, newUpstreamError(err)
+	}
+	spotRate, exists := rates.Rates[req.TargetCurrency]
+	if !exists {
+		return nil, newNotFoundError(CodeCurrencyNotFound, "target currency %s not found in exchange rates", req.TargetCurrency)
+	}
+
+	// Each forward settles on its forecast period's date so it lines up with the forecast it is compared to
+	forecasts, _ := fs.generatePeriods(ctx, spotRate, forecastReq)
+	location, err := loadTimezone(forecastReq.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	spot := fs.clock.Now().In(location)
+	response = &models.HedgingResponse{
+		BaseCurrency:   req.BaseCurrency,
+		TargetCurrency: req.TargetCurrency,
+		SpotRate:       spotRate,
+		Amount:         fs.baseAmount(req.Amount, req.BaseCurrency),
+		ForecastType:   forecastReq.ForecastType,
+		Calendar:       forecastReq.Calendar,
+		Frequency:      forecastReq.Frequency,
+		StartDate:      forecastReq.StartDate,
+		Timezone:       forecastReq.Timezone,
+		Periods:        forecastReq.Periods,
+		Forwards:       make([]models.HedgingPeriod, len(forecasts)),
+		GeneratedAt:    fs.clock.Now(),
+	}
+	for i, forecast := range forecasts {
+		days, err := termDays(forecast.Date, spot)
+		if err != nil {
+			return nil, err
+		}
+		if days < 0 {
+			return nil, newValidationError(CodeValidationError, "period %d on %s settles before spot; start_date must not be in the past", forecast.Period, forecast.Date)
+		}
+		forwardRate, err := curve.Forward(spotRate, baseCurve, targetCurve, days)
+		if err != nil {
+			return nil, newValidationError(CodeValidationError, "cannot price a forward for period %d over %.0f days: %v", forecast.Period, days, err)
+		}
+		hedged := fs.convertAmount(forecastReq, forwardRate)
+		benefit := hedged.Sub(forecast.Amount)
+
+		var benefitPercent float64
+		if !forecast.Amount.IsZero() {
+			benefitPercent = benefit.Float64() / forecast.Amount.Float64() * 100
+		}
+		response.Forwards[i] = models.HedgingPeriod{
+			Period:             forecast.Period,
+			Date:               forecast.Date,
+			Days:               math.Round(days*100) / 100,
+			BaseInterestRate:   math.Round(baseCurve.Rate(days)*1e4) / 1e4,
+			TargetInterestRate: math.Round(targetCurve.Rate(days)*1e4) / 1e4,
+			ForwardRate:        math.Round(forwardRate*1e6) / 1e6,
+			ForwardPoints:      math.Round((forwardRate-spotRate)*1e6) / 1e6,
+			ForecastRate:       forecast.Rate,
+			HedgedAmount:       hedged,
+			UnhedgedAmount:     forecast.Amount,
+			HedgingBenefit:     benefit,
+			BenefitPercent:     math.Round(benefitPercent*100) / 100,
+		}
+		response.HedgedTotal = response.HedgedTotal.Add(hedged)
+		response.UnhedgedTotal = response.UnhedgedTotal.Add(forecast.Amount)
+	}
+	response.HedgingBenefit = response.HedgedTotal.Sub(response.UnhedgedTotal)
+
+	requestLogger.Infof("Priced %d forwards for %s/%s against the %s forecast", len(forecasts), req.BaseCurrency, req.TargetCurrency, forecastReq.ForecastType)
+	return response, nil
+}
+
+// termDays returns the days from spot until a forecast period's date; periods dated by day count whole days from the
+// spot date, and hourly periods count from the spot instant
+func termDays(date string, spot time.Time) (float64, error) {
+	if settles, err := time.Parse(time.RFC3339, date); err == nil {
+		return settles.Sub(spot).Hours() / 24, nil
+	}
+	settles, err := time.ParseInLocation("2006-01-02", date, spot.Location())
+	if err != nil {
+		return 0, err
+	}
+	spotDate := time.Date(spot.Year(), spot.Month(), spot.Day(), 0, 0, 0, 0, spot.Location())
+	return math.Round(settles.Sub(spotDate).Hours() / 24), nil
+}
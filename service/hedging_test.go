@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/dalfonso89/financial-forecasting-service/curve"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
+)
+
+// newHedgingTestService returns the shared test service with flat curves of 5% for USD and 3% for EUR
+func newHedgingTestService(t *testing.T) *ForecastingService {
+	t.Helper()
+	service := newTestService(t)
+	curves, err := curve.Parse([]byte("USD: {rates: {1M: 5, 1Y: 5}}\nEUR: {rates: {1M: 3, 1Y: 3}}\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse curves: %v", err)
+	}
+	service.SetInterestRateCurves(curves)
+	return service
+}
+
+func TestForecastingService_GenerateHedgingAnalysis(t *testing.T) {
+	service := newHedgingTestService(t)
+
+	response, err := service.GenerateHedgingAnalysis(context.Background(), &models.HedgingRequest{
+		BaseCurrency:   "usd",
+		TargetCurrency: "eur",
+		Amount:         1000,
+		Periods:        3,
+		ForecastType:   "linear",
+		Frequency:      "monthly",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.SpotRate != 0.8 || len(response.Forwards) != 3 {
+		t.Fatalf("Unexpected hedging analysis: %+v", response)
+	}
+
+	// The service is pinned to 2025-03-10, so the monthly periods settle on the 10th of April, May and June
+	hedgedTotal, unhedgedTotal := money.Decimal{}, money.Decimal{}
+	for i, forward := range response.Forwards {
+		days := []float64{31, 61, 92}[i]
+		expected := 0.8 * math.Pow(1.03, days/360) / math.Pow(1.05, days/360)
+		if forward.Days != days {
+			t.Errorf("Expected period %d to settle in %v days, got %v", i+1, days, forward.Days)
+		}
+		if math.Abs(forward.ForwardRate-expected) > 1e-6 {
+			t.Errorf("Expected period %d forward %v, got %v", i+1, expected, forward.ForwardRate)
+		}
+		if forward.BaseInterestRate != 5 || forward.TargetInterestRate != 3 {
+			t.Errorf("Expected interest rates 5 and 3, got %v and %v", forward.BaseInterestRate, forward.TargetInterestRate)
+		}
+		// EUR pays less interest than USD, so the forward trades below spot
+		if forward.ForwardPoints >= 0 {
+			t.Errorf("Expected negative forward points, got %v", forward.ForwardPoints)
+		}
+		if !forward.HedgedAmount.Equal(money.NewFromFloat(math.Round(1000*expected*100) / 100)) {
+			t.Errorf("Expected period %d hedged amount of 1000 at the forward, got %s", i+1, forward.HedgedAmount)
+		}
+		// The linear model forecasts a rising rate, so hedging costs against it
+		if !forward.HedgingBenefit.Equal(forward.HedgedAmount.Sub(forward.UnhedgedAmount)) || forward.HedgingBenefit.Sign() >= 0 {
+			t.Errorf("Expected a cost of hedging period %d, got %+v", i+1, forward)
+		}
+		hedgedTotal, unhedgedTotal = hedgedTotal.Add(forward.HedgedAmount), unhedgedTotal.Add(forward.UnhedgedAmount)
+	}
+	if !response.HedgedTotal.Equal(hedgedTotal) || !response.UnhedgedTotal.Equal(unhedgedTotal) {
+		t.Errorf("Expected totals %s and %s, got %s and %s", hedgedTotal, unhedgedTotal, response.HedgedTotal, response.UnhedgedTotal)
+	}
+	if !response.HedgingBenefit.Equal(hedgedTotal.Sub(unhedgedTotal)) {
+		t.Errorf("Expected benefit %s, got %s", hedgedTotal.Sub(unhedgedTotal), response.HedgingBenefit)
+	}
+}
+
+func TestForecastingService_GenerateHedgingAnalysis_SettlementDates(t *testing.T) {
+	service := newHedgingTestService(t)
+
+	// 2025-03-10 is a Monday, so weekday periods from a Friday start skip the weekend
+	response, err := service.GenerateHedgingAnalysis(context.Background(), &models.HedgingRequest{
+		BaseCurrency:   "USD",
+		TargetCurrency: "EUR",
+		Amount:         1000,
+		Periods:        2,
+		Calendar:       "weekdays",
+		StartDate:      "2025-03-14",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Forwards[0].Date != "2025-03-17" || response.Forwards[0].Days != 7 || response.Forwards[1].Days != 8 {
+		t.Errorf("Expected terms of 7 and 8 days from spot, got %+v", response.Forwards)
+	}
+
+	if _, err := service.GenerateHedgingAnalysis(context.Background(), &models.HedgingRequest{
+		BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1000, Periods: 1, StartDate: "2025-03-01",
+	}); ErrorCode(err) != CodeValidationError {
+		t.Errorf("Expected a validation error for a period before spot, got %v", err)
+	}
+}
+
+func TestForecastingService_GenerateHedgingAnalysis_OutOfRangeCurve(t *testing.T) {
+	service := newHedgingTestService(t)
+	curves, err := curve.Parse([]byte("USD: {rates: {1Y: -99.99}}\nEUR: {rates: {1Y: 3}}\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse curves: %v", err)
+	}
+	service.SetInterestRateCurves(curves)
+
+	_, err = service.GenerateHedgingAnalysis(context.Background(), &models.HedgingRequest{
+		BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1000, Periods: 365, Frequency: "quarterly",
+	})
+	if ErrorCode(err) != CodeValidationError {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestForecastingService_GenerateHedgingAnalysis_Validation(t *testing.T) {
+	service := newHedgingTestService(t)
+
+	tests := []struct {
+		name    string
+		request models.HedgingRequest
+		code    string
+	}{
+		{"amount", models.HedgingRequest{BaseCurrency: "USD", TargetCurrency: "EUR"}, CodeValidationError},
+		{"unsupported currency", models.HedgingRequest{BaseCurrency: "USD", TargetCurrency: "CHF", Amount: 1}, CodeUnsupportedCurrency},
+		{"forecast type", models.HedgingRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1, ForecastType: "neural"}, CodeUnsupportedForecastType},
+		{"frequency", models.HedgingRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1, Frequency: "yearly"}, CodeUnsupportedFrequency},
+		{"no curve", models.HedgingRequest{BaseCurrency: "USD", TargetCurrency: "JPY", Amount: 1}, CodeCurveNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.GenerateHedgingAnalysis(context.Background(), &tt.request); ErrorCode(err) != tt.code {
+				t.Errorf("Expected code %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/analytics"
+	"github.com/dalfonso89/financial-forecasting-service/calendar"
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
+)
+
+// Portfolio risk defaults
+const (
+	defaultConfidenceLevel = 0.95
+	defaultRiskWindowDays  = 90
+	maxRiskWindowDays      = 3650
+	// minRiskObservations is the fewest daily returns volatility and correlation are estimated from
+	minRiskObservations = 20
+	// defaultDailyVolatility is assumed, uncorrelated, when there is too little rate history; about 10% a year
+	defaultDailyVolatility = 0.006
+)
+
+// riskEstimate holds the daily covariance of log returns of each currency against a reporting currency
+type riskEstimate struct {
+	model      models.RiskModel
+	covariance [][]float64
+	returns    [][]float64 // Daily log returns of each currency in the window, zero for the reporting currency
+}
+
+// GeneratePortfolioForecast forecasts the value of each holding in the reporting currency and the total value with a band
+// that accounts for the correlation between the holdings' rates
+func (fs *ForecastingService) GeneratePortfolioForecast(ctx context.Context, req *models.PortfolioForecastRequest) (response *models.PortfolioForecastResponse, err error) {
+	req.ReportingCurrency = currency.Normalize(req.ReportingCurrency)
+	for i := range req.Holdings {
+		req.Holdings[i].Currency = currency.Normalize(req.Holdings[i].Currency)
+	}
+
+	ctx = logger.ContextWithFields(ctx, logger.Fields{"reporting_currency": req.ReportingCurrency})
+	requestLogger := fs.logger.WithContext(ctx)
+	ctx, span := tracing.Start(ctx, "ForecastingService.GeneratePortfolioForecast")
+	span.SetAttribute("currency.reporting", req.ReportingCurrency)
+	span.SetAttribute("portfolio.holdings", len(req.Holdings))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// Set defaults; per-pair overrides do not apply across several holdings
+	model := fs.modelFor("", "")
+	if req.Periods == 0 {
+		req.Periods = model.Periods
+	}
+	if req.ForecastType == "" {
+		req.ForecastType = model.ForecastType
+	}
+	if req.Calendar == "" {
+		req.Calendar = calendar.Daily
+	}
+	if req.Frequency == "" {
+		req.Frequency = "daily"
+	}
+	if req.Timezone == "" {
+		req.Timezone = defaultTimezone
+	}
+	if req.ConfidenceLevel == 0 {
+		req.ConfidenceLevel = defaultConfidenceLevel
+	}
+	if req.WindowDays == 0 {
+		req.WindowDays = defaultRiskWindowDays
+	}
+	if err := fs.validatePortfolioRequest(req); err != nil {
+		return nil, err
+	}
+
+	rates, err := fs.getRates(ctx, req.ReportingCurrency)
+	if err != nil {
+		return nil, newUpstreamError(err)
+	}
+
+	// Every holding shares the portfolio's period dates, which step over the joint calendar of all its currencies
+	currencies := make([]string, len(req.Holdings))
+	for i, holding := range req.Holdings {
+		currencies[i] = holding.Currency
+	}
+	schedule := &models.ForecastRequest{Periods: req.Periods, Frequency: req.Frequency, StartDate: req.StartDate, Timezone: req.Timezone}
+	dates := periodDates(schedule, fs.forecastStart(schedule), periodCalendar(req.Calendar, append(currencies, req.ReportingCurrency)...))
+
+	_, computeSpan := tracing.Start(ctx, "forecast.compute")
+	computeSpan.SetAttribute("forecast.type", req.ForecastType)
+	computeStart := time.Now()
+	holdings := make([]models.HoldingForecast, len(req.Holdings))
+	currentValue := money.Decimal{}
+	for i, holding := range req.Holdings {
+		forecastReq := &models.ForecastRequest{
+			BaseCurrency:   holding.Currency,
+			TargetCurrency: req.ReportingCurrency,
+			Amount:         holding.Amount,
+			Periods:        req.Periods,
+			ForecastType:   req.ForecastType,
+			Calendar:       req.Calendar,
+			Frequency:      req.Frequency,
+			StartDate:      req.StartDate,
+			Timezone:       req.Timezone,
+		}
+
+		// Quotes are units of the holding's currency per unit of the reporting currency
+		rate := 1.0
+		var forecasts []models.ForecastPeriod
+		if holding.Currency == req.ReportingCurrency {
+			forecasts = fs.constantPeriods(forecastReq)
+		} else {
+			quote, exists := rates.Rates[holding.Currency]
+			if !exists || quote <= 0 {
+				computeSpan.End()
+				return nil, newNotFoundError(CodeCurrencyNotFound, "currency %s not found in exchange rates", holding.Currency)
+			}
+			rate = 1 / quote
+			forecasts, _ = fs.generatePeriods(ctx, rate, forecastReq)
+		}
+		for period := range forecasts {
+			forecasts[period].Date = dates[period]
+		}
+
+		value := fs.convertAmount(forecastReq, rate)
+		currentValue = currentValue.Add(value)
+		holdings[i] = models.HoldingForecast{
+			Currency:     holding.Currency,
+			Amount:       fs.baseAmount(holding.Amount, holding.Currency),
+			CurrentRate:  math.Round(rate*1e8) / 1e8,
+			CurrentValue: value,
+			Forecasts:    forecasts,
+		}
+	}
+	metrics.ForecastDuration.WithLabelValues(req.ForecastType).Observe(time.Since(computeStart).Seconds())
+	computeSpan.End()
+
+	risk, err := fs.estimateRisk(ctx, req.ReportingCurrency, currencies, req.WindowDays)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holdings {
+		holdings[i].Volatility = math.Round(math.Sqrt(risk.covariance[i][i])*1e6) / 1e6
+	}
+
+	response = &models.PortfolioForecastResponse{
+		ReportingCurrency: req.ReportingCurrency,
+		CurrentValue:      currentValue,
+		ForecastType:      req.ForecastType,
+		Calendar:          req.Calendar,
+		Frequency:         req.Frequency,
+		StartDate:         req.StartDate,
+		Timezone:          req.Timezone,
+		Periods:           req.Periods,
+		ConfidenceLevel:   req.ConfidenceLevel,
+		Holdings:          holdings,
+		Totals:            fs.portfolioTotals(req, holdings, risk.covariance),
+		RiskModel:         risk.model,
+		GeneratedAt:       fs.clock.Now(),
+	}
+
+	requestLogger.Infof("Generated portfolio forecast for %d holdings from %s rate history", len(holdings), risk.model.Source)
+	return response, nil
+}
+
+// validatePortfolioRequest validates a portfolio request after defaults are applied
+func (fs *ForecastingService) validatePortfolioRequest(req *models.PortfolioForecastRequest) error {
+	if err := fs.validateHoldings(req.ReportingCurrency, req.Holdings); err != nil {
+		return err
+	}
+	if req.Periods < 1 || req.Periods > 365 {
+		return newValidationError(CodeValidationError, "periods must be between 1 and 365")
+	}
+	if !IsForecastTypeSupported(req.ForecastType) {
+		return newValidationError(CodeUnsupportedForecastType, "unsupported forecast type: %s", req.ForecastType)
+	}
+	if !isCalendarSupported(req.Calendar) {
+		return newValidationError(CodeUnsupportedCalendar, "unsupported calendar: %s", req.Calendar)
+	}
+	if !isFrequencySupported(req.Frequency) {
+		return newValidationError(CodeUnsupportedFrequency, "unsupported frequency: %s", req.Frequency)
+	}
+	if err := validateHorizon(req.Periods, req.Frequency); err != nil {
+		return err
+	}
+	if req.ConfidenceLevel <= 0.5 || req.ConfidenceLevel >= 1 {
+		return newValidationError(CodeValidationError, "confidence_level must be greater than 0.5 and less than 1")
+	}
+	if req.WindowDays < 2 || req.WindowDays > maxRiskWindowDays {
+		return newValidationError(CodeValidationError, "window_days must be between 2 and %d", maxRiskWindowDays)
+	}
+	return validateSchedule(req.StartDate, req.Timezone)
+}
+
+// validateHoldings validates a reporting currency and the holdings valued in it
+func (fs *ForecastingService) validateHoldings(reportingCurrency string, holdings []models.Holding) error {
+	if reportingCurrency == "" {
+		return newValidationError(CodeValidationError, "reporting currency is required")
+	}
+	if !fs.isCurrencySupported(reportingCurrency) {
+		return newValidationError(CodeUnsupportedCurrency, "reporting currency %s is not supported", reportingCurrency)
+	}
+	if len(holdings) == 0 {
+		return newValidationError(CodeValidationError, "at least one holding is required")
+	}
+	held := make(map[string]bool, len(holdings))
+	for _, holding := range holdings {
+		switch {
+		case !fs.isCurrencySupported(holding.Currency):
+			return newValidationError(CodeUnsupportedCurrency, "holding currency %s is not supported", holding.Currency)
+		case held[holding.Currency]:
+			return newValidationError(CodeValidationError, "currency %s is held more than once", holding.Currency)
+		case holding.Amount == 0 || math.IsNaN(holding.Amount) || math.IsInf(holding.Amount, 0):
+			return newValidationError(CodeValidationError, "holding amount for %s must be a non-zero number", holding.Currency)
+		case math.Abs(holding.Amount) > maxAmount:
+			return newValidationError(CodeValidationError, "holding amount for %s cannot exceed %.0f", holding.Currency, maxAmount)
+		}
+		held[holding.Currency] = true
+	}
+	return nil
+}
+
+// constantPeriods returns periods that hold the request amount at a rate of 1, for holdings in the reporting currency
+func (fs *ForecastingService) constantPeriods(req *models.ForecastRequest) []models.ForecastPeriod {
+	forecasts := make([]models.ForecastPeriod, req.Periods)
+	for i := range forecasts {
+		forecasts[i] = models.ForecastPeriod{Period: i + 1, Rate: 1, Amount: fs.convertAmount(req, 1)}
+	}
+	return forecasts
+}
+
+// estimateRisk estimates the daily covariance of the log returns of each currency against the reporting currency from
+// the rate history over the last windowDays, falling back to uncorrelated default volatility when there is too little history
+func (fs *ForecastingService) estimateRisk(ctx context.Context, reportingCurrency string, currencies []string, windowDays int) (riskEstimate, error) {
+	// The reporting currency has no exchange risk, so only the foreign currencies are estimated
+	var foreign []string
+	for _, code := range currencies {
+		if code != reportingCurrency {
+			foreign = append(foreign, code)
+		}
+	}
+
+	now := fs.clock.Now()
+	points, err := ratehistory.Series(ctx, fs.rateHistory, reportingCurrency, now.AddDate(0, 0, -windowDays), now)
+	if err != nil {
+		return riskEstimate{}, fmt.Errorf("failed to read rate history: %w", err)
+	}
+	returns := analytics.LogReturns(points, foreign)
+
+	model := models.RiskModel{Source: "default", WindowDays: windowDays, Currencies: currencies}
+	foreignCovariance := analytics.Covariance(returns)
+	if len(foreign) > 0 && len(returns[0]) >= minRiskObservations {
+		model.Source, model.Observations = "history", len(returns[0])
+	} else {
+		for i := range foreignCovariance {
+			for j := range foreignCovariance[i] {
+				foreignCovariance[i][j] = 0
+			}
+			foreignCovariance[i][i] = defaultDailyVolatility * defaultDailyVolatility
+		}
+	}
+
+	// Expand to every holding in request order, with zero variance for the reporting currency
+	position := make(map[string]int, len(foreign))
+	for i, code := range foreign {
+		position[code] = i
+	}
+	covariance := make([][]float64, len(currencies))
+	expanded := make([][]float64, len(currencies))
+	for i, a := range currencies {
+		covariance[i] = make([]float64, len(currencies))
+		for j, b := range currencies {
+			x, okA := position[a]
+			y, okB := position[b]
+			if okA && okB {
+				covariance[i][j] = foreignCovariance[x][y]
+			}
+		}
+		if x, ok := position[a]; ok {
+			expanded[i] = returns[x]
+		} else {
+			expanded[i] = make([]float64, model.Observations)
+		}
+	}
+
+	model.Correlation = roundMatrix(analytics.Correlation(covariance))
+	if model.Source != "history" {
+		expanded = nil
+	}
+	return riskEstimate{model: model, covariance: covariance, returns: expanded}, nil
+}
+
+// portfolioTotals sums the holdings in each period and bands the total by its standard deviation at the period's horizon
+func (fs *ForecastingService) portfolioTotals(req *models.PortfolioForecastRequest, holdings []models.HoldingForecast, covariance [][]float64) []models.PortfolioPeriod {
+	z := analytics.NormalQuantile(0.5 + req.ConfidenceLevel/2)
+	places := money.MinorUnits(req.ReportingCurrency)
+	step := periodDays(req.Frequency)
+
+	totals := make([]models.PortfolioPeriod, req.Periods)
+	weights := make([]float64, len(holdings))
+	for period := range totals {
+		value := money.Decimal{}
+		for i, holding := range holdings {
+			value = value.Add(holding.Forecasts[period].Amount)
+			weights[i] = holding.Forecasts[period].Amount.Float64()
+		}
+
+		// Variance of daily returns scales with the horizon in days
+		horizon := step * float64(period+1)
+		width := money.NewFromFloat(z * math.Sqrt(analytics.PortfolioVariance(weights, covariance)*horizon))
+		totals[period] = models.PortfolioPeriod{
+			Period: period + 1,
+			Date:   holdings[0].Forecasts[period].Date,
+			Value:  value,
+			Lower:  value.Sub(width).Round(places, fs.roundingMode()),
+			Upper:  value.Add(width).Round(places, fs.roundingMode()),
+		}
+	}
+	return totals
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
+)
+
+// bandWidth returns the distance from the first period's value to its upper bound
+func bandWidth(response *models.PortfolioForecastResponse) float64 {
+	return response.Totals[0].Upper.Sub(response.Totals[0].Value).Float64()
+}
+
+func TestForecastingService_GeneratePortfolioForecast(t *testing.T) {
+	service := newTestService(t)
+
+	response, err := service.GeneratePortfolioForecast(context.Background(), &models.PortfolioForecastRequest{
+		ReportingCurrency: "usd",
+		Holdings:          []models.Holding{{Currency: "USD", Amount: 1000}, {Currency: "eur", Amount: 800}},
+		Periods:           3,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.ReportingCurrency != "USD" || !response.CurrentValue.Equal(money.NewFromInt(2000)) {
+		t.Errorf("Expected a current value of 2000 USD, got %s %s", response.CurrentValue, response.ReportingCurrency)
+	}
+	if response.RiskModel.Source != "default" || response.ConfidenceLevel != 0.95 {
+		t.Errorf("Expected the default risk model at 95%%, got %s at %v", response.RiskModel.Source, response.ConfidenceLevel)
+	}
+	cash := response.Holdings[0]
+	if cash.Volatility != 0 || !cash.Forecasts[2].Amount.Equal(money.NewFromInt(1000)) {
+		t.Errorf("Expected reporting currency cash to hold its value, got %+v", cash)
+	}
+	if euros := response.Holdings[1]; euros.CurrentRate != 1.25 || euros.Volatility != defaultDailyVolatility {
+		t.Errorf("Expected EUR at 1.25 USD with default volatility, got %+v", euros)
+	}
+
+	for i, total := range response.Totals {
+		expected := response.Holdings[0].Forecasts[i].Amount.Add(response.Holdings[1].Forecasts[i].Amount)
+		if !total.Value.Equal(expected) {
+			t.Errorf("Expected period %d total %s, got %s", total.Period, expected, total.Value)
+		}
+		if date := fmt.Sprintf("2025-03-%d", 11+i); total.Date != date {
+			t.Errorf("Expected period %d on %s, got %s", total.Period, date, total.Date)
+		}
+		if total.Lower.Cmp(total.Value) >= 0 || total.Upper.Cmp(total.Value) <= 0 {
+			t.Errorf("Expected the band to surround the value, got %+v", total)
+		}
+	}
+
+	// One day ahead the band is z × σ × the EUR holding's value
+	expectedWidth := 1.959964 * defaultDailyVolatility * response.Holdings[1].Forecasts[0].Amount.Float64()
+	if width := bandWidth(response); math.Abs(width-expectedWidth) > 0.01 {
+		t.Errorf("Expected a band width of %.2f, got %.2f", expectedWidth, width)
+	}
+	// Bands widen with the square root of the horizon
+	if third := response.Totals[2].Upper.Sub(response.Totals[2].Value).Float64(); third < bandWidth(response)*1.6 {
+		t.Errorf("Expected the third period's band to be about √3 times wider, got %.2f", third)
+	}
+}
+
+func TestForecastingService_GeneratePortfolioForecast_Correlation(t *testing.T) {
+	// 1000 USD of EUR and 1000 USD of JPY
+	request := func() *models.PortfolioForecastRequest {
+		return &models.PortfolioForecastRequest{
+			ReportingCurrency: "USD",
+			Holdings:          []models.Holding{{Currency: "EUR", Amount: 800}, {Currency: "JPY", Amount: 150000}},
+			Periods:           1,
+		}
+	}
+
+	correlated := newTestService(t)
+	seedRateHistory(correlated, 1)
+	together, err := correlated.GeneratePortfolioForecast(context.Background(), request())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	hedged := newTestService(t)
+	seedRateHistory(hedged, -1)
+	opposed, err := hedged.GeneratePortfolioForecast(context.Background(), request())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if together.RiskModel.Source != "history" || together.RiskModel.Observations < minRiskObservations {
+		t.Fatalf("Expected estimates from history, got %+v", together.RiskModel)
+	}
+	if rho := together.RiskModel.Correlation[0][1]; rho < 0.99 {
+		t.Errorf("Expected correlation near 1, got %v", rho)
+	}
+	if rho := opposed.RiskModel.Correlation[0][1]; rho > -0.99 {
+		t.Errorf("Expected correlation near -1, got %v", rho)
+	}
+
+	// Perfectly opposed holdings of equal value offset each other; perfectly correlated ones add up
+	single := 1.959964 * together.Holdings[0].Volatility * together.Holdings[0].Forecasts[0].Amount.Float64()
+	if width := bandWidth(together); math.Abs(width-2*single) > 0.05 {
+		t.Errorf("Expected a correlated band width of %.2f, got %.2f", 2*single, width)
+	}
+	if width := bandWidth(opposed); width > 0.05 {
+		t.Errorf("Expected offsetting holdings to have almost no band, got %.2f", width)
+	}
+}
+
+func TestForecastingService_GeneratePortfolioForecast_Validation(t *testing.T) {
+	service := newTestService(t)
+	euros := []models.Holding{{Currency: "EUR", Amount: 100}}
+
+	tests := []struct {
+		name    string
+		request models.PortfolioForecastRequest
+		code    string
+	}{
+		{"unsupported reporting currency", models.PortfolioForecastRequest{ReportingCurrency: "CHF", Holdings: euros}, CodeUnsupportedCurrency},
+		{"unsupported holding", models.PortfolioForecastRequest{ReportingCurrency: "USD", Holdings: []models.Holding{{Currency: "CHF", Amount: 1}}}, CodeUnsupportedCurrency},
+		{"duplicate holding", models.PortfolioForecastRequest{ReportingCurrency: "USD", Holdings: []models.Holding{{Currency: "EUR", Amount: 1}, {Currency: "eur", Amount: 2}}}, CodeValidationError},
+		{"zero amount", models.PortfolioForecastRequest{ReportingCurrency: "USD", Holdings: []models.Holding{{Currency: "EUR"}}}, CodeValidationError},
+		{"amount too large", models.PortfolioForecastRequest{ReportingCurrency: "USD", Holdings: []models.Holding{{Currency: "EUR", Amount: 1e300}}}, CodeValidationError},
+		{"confidence level", models.PortfolioForecastRequest{ReportingCurrency: "USD", Holdings: euros, ConfidenceLevel: 1}, CodeValidationError},
+		{"window", models.PortfolioForecastRequest{ReportingCurrency: "USD", Holdings: euros, WindowDays: 1}, CodeValidationError},
+		{"calendar", models.PortfolioForecastRequest{ReportingCurrency: "USD", Holdings: euros, Calendar: "lunar"}, CodeUnsupportedCalendar},
+		{"periods", models.PortfolioForecastRequest{ReportingCurrency: "USD", Holdings: euros, Periods: 366}, CodeValidationError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.GeneratePortfolioForecast(context.Background(), &tt.request); ErrorCode(err) != tt.code {
+				t.Errorf("Expected code %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
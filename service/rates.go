@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	currencymodels "github.com/dalfonso89/currency-exchange-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+)
+
+// SetRateHistory replaces the store that daily rate snapshots are recorded in; call it before the service handles requests
+func (fs *ForecastingService) SetRateHistory(store ratehistory.Store) {
+	fs.rateHistory = store
+}
+
+// getRates fetches the current rates for a base currency; requests do not record them, the snapshot job does
+func (fs *ForecastingService) getRates(ctx context.Context, baseCurrency string) (*currencymodels.RatesResponse, error) {
+	return fs.currencyClient.GetRates(ctx, baseCurrency)
+}
+
+// recordRates stores a rates response in the rate history; failures are logged and do not fail the caller
+func (fs *ForecastingService) recordRates(ctx context.Context, rates *currencymodels.RatesResponse) {
+	if rates.Base == "" || len(rates.Rates) == 0 {
+		return
+	}
+	quotedAt := fs.clock.Now()
+	if rates.Timestamp > 0 {
+		quotedAt = time.Unix(rates.Timestamp, 0)
+	}
+	snapshot := ratehistory.Snapshot{Base: rates.Base, Date: ratehistory.DateOf(quotedAt), Rates: rates.Rates}
+	if err := fs.rateHistory.Record(ctx, snapshot); err != nil {
+		metrics.RateHistoryWriteErrors.Inc()
+		fs.logger.WithContext(ctx).Errorf("Failed to record %s rates in rate history: %v", rates.Base, err)
+	}
+}
+
+// SnapshotRates records the current rates of the first supported currency; the others are priced from it by triangulation
+func (fs *ForecastingService) SnapshotRates(ctx context.Context) error {
+	supported := fs.SupportedCurrencies()
+	if len(supported) == 0 {
+		return nil
+	}
+	rates, err := fs.getRates(ctx, supported[0])
+	if err != nil {
+		return newUpstreamError(err)
+	}
+	fs.recordRates(ctx, rates)
+	return nil
+}
+
+// RunRateSnapshots records a rate snapshot immediately and then every interval until ctx is done, so the history
+// holds the last quotes of each day
+func (fs *ForecastingService) RunRateSnapshots(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := fs.SnapshotRates(ctx); err != nil {
+			fs.logger.Warnf("Rate snapshot failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+)
+
+func TestForecastingService_RecordsRatesFromSnapshotsOnly(t *testing.T) {
+	service := newTestService(t)
+	store := ratehistory.NewMemoryStore()
+	service.SetRateHistory(store)
+	ctx := context.Background()
+	from, to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	request := models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1, Periods: 1}
+	if _, err := service.GenerateForecast(ctx, &request); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if snapshots, _ := store.Snapshots(ctx, from, to); len(snapshots) != 0 {
+		t.Errorf("Expected requests not to record rates, got %+v", snapshots)
+	}
+
+	if err := service.SnapshotRates(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	snapshots, _ := store.Snapshots(ctx, from, to)
+	if len(snapshots) != 1 || snapshots[0].Date != "2025-03-10" || snapshots[0].Rates["EUR"] != 0.8 {
+		t.Errorf("Expected one USD snapshot on 2025-03-10, got %+v", snapshots)
+	}
+}
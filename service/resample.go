@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+)
+
+// Resampling of rate history into the periods of a forecast's frequency
+const (
+	resampleLookbackPeriods = 30 // Periods of rate history read before the current one
+	minResampledCloses      = 3  // Fewest closes, including the current rate, that replace the default model parameters
+	movingAverageWindow     = 5  // Closes averaged by the moving average model
+)
+
+// rateSamples holds a pair's closing rate in each period of a forecast's frequency, oldest first, ending at the current rate
+type rateSamples struct {
+	closes []float64
+	days   float64 // Length of one sample in days; hourly forecasts sample daily, the finest the history records
+}
+
+// usable reports whether there are enough closes to fit the models to
+func (s rateSamples) usable() bool {
+	return len(s.closes) >= minResampledCloses
+}
+
+// resampleRates reads the pair's rate history and keeps the last rate of each period at the request's frequency; the
+// current rate closes the current period. History that cannot be read leaves only the current rate.
+func (fs *ForecastingService) resampleRates(ctx context.Context, currentRate float64, req *models.ForecastRequest) rateSamples {
+	samples := rateSamples{days: periodDays(req.Frequency)}
+	if req.Frequency == "hourly" {
+		samples.days = 1
+	}
+
+	now := fs.clock.Now().UTC()
+	lookback := time.Duration(math.Ceil(samples.days*resampleLookbackPeriods)) * 24 * time.Hour
+	points, err := ratehistory.Series(ctx, fs.rateHistory, req.BaseCurrency, now.Add(-lookback), now)
+	if err != nil {
+		fs.logger.WithContext(ctx).Warnf("Failed to read rate history for %s/%s: %v", req.BaseCurrency, req.TargetCurrency, err)
+		points = nil
+	}
+
+	var lastKey string
+	add := func(key string, rate float64) {
+		if key == lastKey && len(samples.closes) > 0 {
+			samples.closes[len(samples.closes)-1] = rate
+			return
+		}
+		samples.closes = append(samples.closes, rate)
+		lastKey = key
+	}
+	for _, point := range points {
+		rate, ok := point.Rates[req.TargetCurrency]
+		date, err := time.Parse("2006-01-02", point.Date)
+		if !ok || rate <= 0 || err != nil {
+			continue
+		}
+		add(samplePeriod(date, req.Frequency), rate)
+	}
+	add(samplePeriod(now, req.Frequency), currentRate)
+	return samples
+}
+
+// samplePeriod names the period of a frequency that a day falls in; hourly and daily forecasts sample by day
+func samplePeriod(date time.Time, frequency string) string {
+	switch frequency {
+	case "weekly":
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "monthly":
+		return date.Format("2006-01")
+	case "quarterly":
+		return fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())-1)/3+1)
+	default:
+		return date.Format("2006-01-02")
+	}
+}
+
+// linearTrend returns the least squares slope of the log closes per day, the continuously compounded daily change
+func (s rateSamples) linearTrend() float64 {
+	n := float64(len(s.closes))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, rate := range s.closes {
+		x, y := float64(i), math.Log(rate)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	return slope / s.days
+}
+
+// growthRate returns the compound daily growth from the first close to the last
+func (s rateSamples) growthRate() float64 {
+	first, last := s.closes[0], s.closes[len(s.closes)-1]
+	return math.Pow(last/first, 1/(float64(len(s.closes)-1)*s.days)) - 1
+}
+
+// movingAverage returns the mean of the latest closes
+func (s rateSamples) movingAverage() float64 {
+	window := s.closes[max(len(s.closes)-movingAverageWindow, 0):]
+	var sum float64
+	for _, rate := range window {
+		sum += rate
+	}
+	return sum / float64(len(window))
+}
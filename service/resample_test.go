@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+)
+
+// seedDailyRates records a USD snapshot for each day from 2025-02-10 to 2025-03-09 with EUR at rate(day)
+func seedDailyRates(service *ForecastingService, rate func(day int) float64) {
+	store := ratehistory.NewMemoryStore()
+	start := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < 28; day++ {
+		store.Record(context.Background(), ratehistory.Snapshot{
+			Base:  "USD",
+			Date:  ratehistory.DateOf(start.AddDate(0, 0, day)),
+			Rates: map[string]float64{"EUR": rate(day)},
+		})
+	}
+	service.SetRateHistory(store)
+}
+
+func TestSamplePeriod(t *testing.T) {
+	date := time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		frequency string
+		expected  string
+	}{
+		{"hourly", "2025-12-29"},
+		{"daily", "2025-12-29"},
+		{"weekly", "2026-W01"},
+		{"monthly", "2025-12"},
+		{"quarterly", "2025-Q4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.frequency, func(t *testing.T) {
+			if key := samplePeriod(date, tt.frequency); key != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, key)
+			}
+		})
+	}
+}
+
+func TestForecastingService_resampleRates(t *testing.T) {
+	service := newTestService(t)
+	seedDailyRates(service, func(day int) float64 { return 0.7 + 0.001*float64(day) })
+
+	tests := []struct {
+		frequency string
+		days      float64
+		closes    []float64
+	}{
+		// Each week closes on its Sunday, and the current rate closes the week of 2025-03-10
+		{"weekly", 7, []float64{0.706, 0.713, 0.72, 0.727, 0.8}},
+		// February closes on the 28th and March on the current rate
+		{"monthly", 365.25 / 12, []float64{0.718, 0.8}},
+		{"quarterly", 365.25 / 4, []float64{0.8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.frequency, func(t *testing.T) {
+			samples := service.resampleRates(context.Background(), 0.8, &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Frequency: tt.frequency})
+			if samples.days != tt.days || len(samples.closes) != len(tt.closes) {
+				t.Fatalf("Expected %d closes of %v days, got %+v", len(tt.closes), tt.days, samples)
+			}
+			for i, expected := range tt.closes {
+				if math.Abs(samples.closes[i]-expected) > 1e-9 {
+					t.Errorf("Expected close %d to be %v, got %v", i, expected, samples.closes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateSamples_Parameters(t *testing.T) {
+	samples := rateSamples{closes: []float64{1, 2, 4}, days: 7}
+	if trend := samples.linearTrend(); math.Abs(trend-math.Ln2/7) > 1e-12 {
+		t.Errorf("Expected linear trend %v, got %v", math.Ln2/7, trend)
+	}
+
+	samples = rateSamples{closes: []float64{1, 2, 4}, days: 1}
+	if growth := samples.growthRate(); math.Abs(growth-1) > 1e-12 {
+		t.Errorf("Expected growth rate 1, got %v", growth)
+	}
+
+	samples = rateSamples{closes: []float64{100, 1, 2, 3, 4, 5}, days: 1}
+	if average := samples.movingAverage(); average != 3 {
+		t.Errorf("Expected moving average 3, got %v", average)
+	}
+}
+
+func TestForecastingService_GenerateForecast_FitsRateHistory(t *testing.T) {
+	request := models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1, Periods: 1, ForecastType: "linear", Frequency: "weekly"}
+
+	// Without history the linear model assumes a rising rate
+	service := newTestService(t)
+	response, err := service.GenerateForecast(context.Background(), &request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Forecasts[0].Rate <= 0.8 {
+		t.Errorf("Expected the default trend to raise the rate, got %v", response.Forecasts[0].Rate)
+	}
+
+	// Falling weekly closes pull the forecast down
+	service = newTestService(t)
+	seedDailyRates(service, func(day int) float64 { return 0.9 - 0.001*float64(day) })
+	response, err = service.GenerateForecast(context.Background(), &request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Forecasts[0].Rate >= 0.8 {
+		t.Errorf("Expected the fitted trend to lower the rate, got %v", response.Forecasts[0].Rate)
+	}
+}
+
+func TestForecastingService_GenerateForecast_FallingHistoryStaysPositive(t *testing.T) {
+	service := newTestService(t)
+	seedDailyRates(service, func(day int) float64 { return 0.9 - 0.002*float64(day) })
+
+	response, err := service.GenerateForecast(context.Background(), &models.ForecastRequest{BaseCurrency: "USD", TargetCurrency: "EUR", Amount: 1000, Periods: 365, ForecastType: "linear"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	previous := 0.8
+	for _, period := range response.Forecasts {
+		if period.Rate <= 0 || period.Amount.Sign() <= 0 || period.Rate > previous {
+			t.Fatalf("Expected rates to fall towards zero without crossing it, got period %d at rate %v and amount %s", period.Period, period.Rate, period.Amount)
+		}
+		previous = period.Rate
+	}
+}
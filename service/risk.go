@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"slices"
+
+	"github.com/dalfonso89/financial-forecasting-service/analytics"
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
+)
+
+// Value at risk methods
+const (
+	methodHistorical = "historical"
+	methodParametric = "parametric"
+	methodMonteCarlo = "monte_carlo"
+)
+
+// Value at risk defaults and limits
+const (
+	defaultVaRWindowDays   = 365
+	defaultVaRSimulations  = 10000
+	maxVaRSimulations      = 100000
+	maxVaRHorizonDays      = 250
+	maxVaRMeasureDimension = 10 // Most confidence levels or horizons in one request
+)
+
+var (
+	defaultVaRConfidenceLevels = []float64{0.95, 0.99}
+	defaultVaRHorizonDays      = []int{1, 10}
+	valueAtRiskMethods         = []string{methodHistorical, methodParametric, methodMonteCarlo}
+)
+
+// CalculateValueAtRisk estimates the value at risk and expected shortfall of currency positions in the reporting currency
+// by historical simulation over the rate history, the variance-covariance method and Monte Carlo simulation
+func (fs *ForecastingService) CalculateValueAtRisk(ctx context.Context, req *models.ValueAtRiskRequest) (response *models.ValueAtRiskResponse, err error) {
+	req.ReportingCurrency = currency.Normalize(req.ReportingCurrency)
+	for i := range req.Positions {
+		req.Positions[i].Currency = currency.Normalize(req.Positions[i].Currency)
+	}
+
+	ctx = logger.ContextWithFields(ctx, logger.Fields{"reporting_currency": req.ReportingCurrency})
+	requestLogger := fs.logger.WithContext(ctx)
+	ctx, span := tracing.Start(ctx, "ForecastingService.CalculateValueAtRisk")
+	span.SetAttribute("currency.reporting", req.ReportingCurrency)
+	span.SetAttribute("risk.positions", len(req.Positions))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// Set defaults; methods default after the rate history is read
+	if len(req.ConfidenceLevels) == 0 {
+		req.ConfidenceLevels = slices.Clone(defaultVaRConfidenceLevels)
+	}
+	if len(req.HorizonDays) == 0 {
+		req.HorizonDays = slices.Clone(defaultVaRHorizonDays)
+	}
+	if req.WindowDays == 0 {
+		req.WindowDays = defaultVaRWindowDays
+	}
+	if req.Simulations == 0 {
+		req.Simulations = defaultVaRSimulations
+	}
+	if err := fs.validateValueAtRiskRequest(req); err != nil {
+		return nil, err
+	}
+
+	rates, err := fs.getRates(ctx, req.ReportingCurrency)
+	if err != nil {
+		return nil, newUpstreamError(err)
+	}
+
+	// Quotes are units of the position's currency per unit of the reporting currency
+	currencies := make([]string, len(req.Positions))
+	positions := make([]models.PositionValue, len(req.Positions))
+	values := make([]float64, len(req.Positions))
+	portfolioValue := money.Decimal{}
+	for i, position := range req.Positions {
+		rate := 1.0
+		if position.Currency != req.ReportingCurrency {
+			quote, exists := rates.Rates[position.Currency]
+			if !exists || quote <= 0 {
+				return nil, newNotFoundError(CodeCurrencyNotFound, "currency %s not found in exchange rates", position.Currency)
+			}
+			rate = 1 / quote
+		}
+		value := fs.convertAmount(&models.ForecastRequest{BaseCurrency: position.Currency, TargetCurrency: req.ReportingCurrency, Amount: position.Amount}, rate)
+		currencies[i] = position.Currency
+		values[i] = value.Float64()
+		portfolioValue = portfolioValue.Add(value)
+		positions[i] = models.PositionValue{
+			Currency:     position.Currency,
+			Amount:       fs.baseAmount(position.Amount, position.Currency),
+			CurrentRate:  math.Round(rate*1e8) / 1e8,
+			CurrentValue: value,
+		}
+	}
+
+	risk, err := fs.estimateRisk(ctx, req.ReportingCurrency, currencies, req.WindowDays)
+	if err != nil {
+		return nil, err
+	}
+
+	// Historical simulation needs enough overlapping returns at the longest horizon
+	scenarios := 0
+	if risk.returns != nil {
+		scenarios = risk.model.Observations - slices.Max(req.HorizonDays) + 1
+	}
+	historyUsable := scenarios >= minRiskObservations
+	if len(req.Methods) == 0 {
+		req.Methods = []string{methodParametric, methodMonteCarlo}
+		if historyUsable {
+			req.Methods = slices.Clone(valueAtRiskMethods)
+		}
+	} else if slices.Contains(req.Methods, methodHistorical) && !historyUsable {
+		return nil, newValidationError(CodeInsufficientHistory,
+			"historical value at risk needs at least %d returns over %d days of rate history; %d available",
+			minRiskObservations, slices.Max(req.HorizonDays), max(scenarios, 0))
+	}
+	span.SetAttribute("risk.methods", len(req.Methods))
+
+	response = &models.ValueAtRiskResponse{
+		ReportingCurrency: req.ReportingCurrency,
+		PortfolioValue:    portfolioValue,
+		Positions:         positions,
+		RiskModel:         risk.model,
+		GeneratedAt:       fs.clock.Now(),
+	}
+	for _, method := range valueAtRiskMethods {
+		if !slices.Contains(req.Methods, method) {
+			continue
+		}
+		var measures []models.RiskMeasure
+		switch method {
+		case methodHistorical:
+			measures = fs.historicalValueAtRisk(req, values, risk.returns)
+		case methodParametric:
+			measures = fs.parametricValueAtRisk(req, values, risk.covariance)
+		case methodMonteCarlo:
+			if req.Seed == 0 {
+				req.Seed = fs.clock.Now().UnixNano()
+			}
+			measures = fs.monteCarloValueAtRisk(req, values, risk.covariance)
+			response.Simulations, response.Seed = req.Simulations, req.Seed
+		}
+		response.Measures = append(response.Measures, measures...)
+	}
+
+	requestLogger.Infof("Calculated value at risk for %d positions by %v from %s rate history", len(positions), req.Methods, risk.model.Source)
+	return response, nil
+}
+
+// validateValueAtRiskRequest validates a value at risk request after defaults are applied
+func (fs *ForecastingService) validateValueAtRiskRequest(req *models.ValueAtRiskRequest) error {
+	if err := fs.validateHoldings(req.ReportingCurrency, req.Positions); err != nil {
+		return err
+	}
+	if len(req.ConfidenceLevels) > maxVaRMeasureDimension || len(req.HorizonDays) > maxVaRMeasureDimension {
+		return newValidationError(CodeValidationError, "at most %d confidence levels and %d horizons are allowed", maxVaRMeasureDimension, maxVaRMeasureDimension)
+	}
+	for _, level := range req.ConfidenceLevels {
+		if level <= 0.5 || level >= 1 {
+			return newValidationError(CodeValidationError, "confidence levels must be greater than 0.5 and less than 1")
+		}
+	}
+	for _, horizon := range req.HorizonDays {
+		if horizon < 1 || horizon > maxVaRHorizonDays {
+			return newValidationError(CodeValidationError, "horizon_days must be between 1 and %d", maxVaRHorizonDays)
+		}
+	}
+	for _, method := range req.Methods {
+		if !slices.Contains(valueAtRiskMethods, method) {
+			return newValidationError(CodeValidationError, "unsupported value at risk method: %s", method)
+		}
+	}
+	if req.WindowDays < 2 || req.WindowDays > maxRiskWindowDays {
+		return newValidationError(CodeValidationError, "window_days must be between 2 and %d", maxRiskWindowDays)
+	}
+	if req.Simulations < 100 || req.Simulations > maxVaRSimulations {
+		return newValidationError(CodeValidationError, "simulations must be between 100 and %d", maxVaRSimulations)
+	}
+	return nil
+}
+
+// historicalValueAtRisk revalues the positions under every overlapping run of past daily returns as long as the horizon
+func (fs *ForecastingService) historicalValueAtRisk(req *models.ValueAtRiskRequest, values []float64, returns [][]float64) []models.RiskMeasure {
+	var measures []models.RiskMeasure
+	for _, horizon := range req.HorizonDays {
+		sums := analytics.RollingSums(returns, horizon)
+		pnl := make([]float64, len(sums[0]))
+		for scenario := range pnl {
+			for i, value := range values {
+				pnl[scenario] += revalue(value, sums[i][scenario])
+			}
+		}
+		measures = append(measures, fs.tailMeasures(req, methodHistorical, horizon, pnl)...)
+	}
+	return measures
+}
+
+// parametricValueAtRisk assumes normally distributed profit and loss that is linear in the daily log returns
+func (fs *ForecastingService) parametricValueAtRisk(req *models.ValueAtRiskRequest, values []float64, covariance [][]float64) []models.RiskMeasure {
+	dailyVariance := analytics.PortfolioVariance(values, covariance)
+	var measures []models.RiskMeasure
+	for _, horizon := range req.HorizonDays {
+		stddev := math.Sqrt(dailyVariance * float64(horizon))
+		for _, level := range req.ConfidenceLevels {
+			valueAtRisk, expectedShortfall := analytics.NormalTailRisk(stddev, level)
+			measures = append(measures, fs.riskMeasure(req, methodParametric, level, horizon, valueAtRisk, expectedShortfall, 0))
+		}
+	}
+	return measures
+}
+
+// monteCarloValueAtRisk revalues the positions under correlated normal returns drawn from the covariance
+func (fs *ForecastingService) monteCarloValueAtRisk(req *models.ValueAtRiskRequest, values []float64, covariance [][]float64) []models.RiskMeasure {
+	lower := analytics.Cholesky(covariance)
+	random := rand.New(rand.NewSource(req.Seed))
+
+	// Every horizon reuses the same draws, scaled by the square root of its length
+	pnl := make([][]float64, len(req.HorizonDays))
+	for i := range pnl {
+		pnl[i] = make([]float64, req.Simulations)
+	}
+	draws := make([]float64, len(values))
+	for path := 0; path < req.Simulations; path++ {
+		for i := range draws {
+			draws[i] = random.NormFloat64()
+		}
+		for i, value := range values {
+			var daily float64
+			for k := 0; k <= i; k++ {
+				daily += lower[i][k] * draws[k]
+			}
+			for h, horizon := range req.HorizonDays {
+				pnl[h][path] += revalue(value, daily*math.Sqrt(float64(horizon)))
+			}
+		}
+	}
+
+	var measures []models.RiskMeasure
+	for h, horizon := range req.HorizonDays {
+		measures = append(measures, fs.tailMeasures(req, methodMonteCarlo, horizon, pnl[h])...)
+	}
+	return measures
+}
+
+// tailMeasures returns a measure at every confidence level from a profit and loss sample
+func (fs *ForecastingService) tailMeasures(req *models.ValueAtRiskRequest, method string, horizon int, pnl []float64) []models.RiskMeasure {
+	measures := make([]models.RiskMeasure, len(req.ConfidenceLevels))
+	for i, level := range req.ConfidenceLevels {
+		valueAtRisk, expectedShortfall := analytics.TailRisk(pnl, level)
+		measures[i] = fs.riskMeasure(req, method, level, horizon, valueAtRisk, expectedShortfall, len(pnl))
+	}
+	return measures
+}
+
+// riskMeasure rounds a value at risk and expected shortfall to the reporting currency's minor unit
+func (fs *ForecastingService) riskMeasure(req *models.ValueAtRiskRequest, method string, level float64, horizon int, valueAtRisk, expectedShortfall float64, scenarios int) models.RiskMeasure {
+	return models.RiskMeasure{
+		Method:            method,
+		ConfidenceLevel:   level,
+		HorizonDays:       horizon,
+		ValueAtRisk:       money.RoundAmount(money.NewFromFloat(valueAtRisk), req.ReportingCurrency, fs.roundingMode()),
+		ExpectedShortfall: money.RoundAmount(money.NewFromFloat(expectedShortfall), req.ReportingCurrency, fs.roundingMode()),
+		Scenarios:         scenarios,
+	}
+}
+
+// revalue returns the change in a position's reporting currency value when its quote's log return is logReturn; a rising
+// quote means the position's currency buys less of the reporting currency
+func revalue(value, logReturn float64) float64 {
+	return value * (math.Exp(-logReturn) - 1)
+}
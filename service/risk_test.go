@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
+)
+
+// measure returns the measure for a method, confidence level and horizon
+func measure(t *testing.T, response *models.ValueAtRiskResponse, method string, level float64, horizon int) models.RiskMeasure {
+	t.Helper()
+	for _, m := range response.Measures {
+		if m.Method == method && m.ConfidenceLevel == level && m.HorizonDays == horizon {
+			return m
+		}
+	}
+	t.Fatalf("Expected a %s measure at %v over %d days, got %+v", method, level, horizon, response.Measures)
+	return models.RiskMeasure{}
+}
+
+func TestForecastingService_CalculateValueAtRisk(t *testing.T) {
+	service := newTestService(t)
+
+	response, err := service.CalculateValueAtRisk(context.Background(), &models.ValueAtRiskRequest{
+		ReportingCurrency: "usd",
+		Positions:         []models.Holding{{Currency: "eur", Amount: 800}, {Currency: "USD", Amount: 500}},
+		Seed:              7,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.PortfolioValue.Equal(money.NewFromInt(1500)) {
+		t.Errorf("Expected a portfolio value of 1500, got %s", response.PortfolioValue)
+	}
+	// Without history the defaults skip historical simulation
+	if len(response.Measures) != 8 || response.Simulations != defaultVaRSimulations || response.Seed != 7 {
+		t.Fatalf("Expected parametric and Monte Carlo measures at two levels and horizons, got %d measures, %d simulations and seed %d",
+			len(response.Measures), response.Simulations, response.Seed)
+	}
+
+	// 1000 USD of EUR at the default daily volatility
+	parametric := measure(t, response, methodParametric, 0.99, 10)
+	expected := 2.326348 * defaultDailyVolatility * 1000 * math.Sqrt(10)
+	if math.Abs(parametric.ValueAtRisk.Float64()-expected) > 0.01 {
+		t.Errorf("Expected parametric VaR %.2f, got %s", expected, parametric.ValueAtRisk)
+	}
+	if parametric.ExpectedShortfall.Cmp(parametric.ValueAtRisk) <= 0 {
+		t.Errorf("Expected ES above VaR, got %+v", parametric)
+	}
+
+	simulated := measure(t, response, methodMonteCarlo, 0.99, 10)
+	if relative := math.Abs(simulated.ValueAtRisk.Float64()/expected - 1); relative > 0.05 {
+		t.Errorf("Expected Monte Carlo VaR within 5%% of %.2f, got %s", expected, simulated.ValueAtRisk)
+	}
+	if simulated.Scenarios != defaultVaRSimulations {
+		t.Errorf("Expected %d scenarios, got %d", defaultVaRSimulations, simulated.Scenarios)
+	}
+
+	// A fixed seed repeats the simulation
+	again, err := service.CalculateValueAtRisk(context.Background(), &models.ValueAtRiskRequest{
+		ReportingCurrency: "USD",
+		Positions:         []models.Holding{{Currency: "EUR", Amount: 800}, {Currency: "USD", Amount: 500}},
+		Methods:           []string{methodMonteCarlo},
+		Seed:              7,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repeat := measure(t, again, methodMonteCarlo, 0.99, 10); !repeat.ValueAtRisk.Equal(simulated.ValueAtRisk) {
+		t.Errorf("Expected seed 7 to repeat VaR %s, got %s", simulated.ValueAtRisk, repeat.ValueAtRisk)
+	}
+}
+
+func TestForecastingService_CalculateValueAtRisk_History(t *testing.T) {
+	request := func() *models.ValueAtRiskRequest {
+		return &models.ValueAtRiskRequest{
+			ReportingCurrency: "USD",
+			Positions:         []models.Holding{{Currency: "EUR", Amount: 800}, {Currency: "JPY", Amount: 150000}},
+			HorizonDays:       []int{1},
+			Seed:              1,
+		}
+	}
+
+	correlated := newTestService(t)
+	seedRateHistory(correlated, 1)
+	together, err := correlated.CalculateValueAtRisk(context.Background(), request())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	hedged := newTestService(t)
+	seedRateHistory(hedged, -1)
+	opposed, err := hedged.CalculateValueAtRisk(context.Background(), request())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if together.RiskModel.Source != "history" || len(together.Measures) != 6 {
+		t.Fatalf("Expected every method from history, got %s with %d measures", together.RiskModel.Source, len(together.Measures))
+	}
+	for _, method := range valueAtRiskMethods {
+		correlatedVaR := measure(t, together, method, 0.95, 1).ValueAtRisk.Float64()
+		opposedVaR := measure(t, opposed, method, 0.95, 1).ValueAtRisk.Float64()
+		if correlatedVaR < 1 || opposedVaR > correlatedVaR/10 {
+			t.Errorf("Expected %s VaR of offsetting positions well below %.2f, got %.2f", method, correlatedVaR, opposedVaR)
+		}
+	}
+
+	historical := measure(t, together, methodHistorical, 0.95, 1)
+	if historical.Scenarios != together.RiskModel.Observations {
+		t.Errorf("Expected one scenario per daily return, got %d of %d", historical.Scenarios, together.RiskModel.Observations)
+	}
+}
+
+func TestForecastingService_CalculateValueAtRisk_Validation(t *testing.T) {
+	service := newTestService(t)
+	euros := []models.Holding{{Currency: "EUR", Amount: 100}}
+
+	tests := []struct {
+		name    string
+		request models.ValueAtRiskRequest
+		code    string
+	}{
+		{"unsupported position", models.ValueAtRiskRequest{ReportingCurrency: "USD", Positions: []models.Holding{{Currency: "CHF", Amount: 1}}}, CodeUnsupportedCurrency},
+		{"duplicate position", models.ValueAtRiskRequest{ReportingCurrency: "USD", Positions: []models.Holding{{Currency: "EUR", Amount: 1}, {Currency: "EUR", Amount: 2}}}, CodeValidationError},
+		{"confidence level", models.ValueAtRiskRequest{ReportingCurrency: "USD", Positions: euros, ConfidenceLevels: []float64{0.95, 1}}, CodeValidationError},
+		{"horizon", models.ValueAtRiskRequest{ReportingCurrency: "USD", Positions: euros, HorizonDays: []int{0}}, CodeValidationError},
+		{"method", models.ValueAtRiskRequest{ReportingCurrency: "USD", Positions: euros, Methods: []string{"delta_gamma"}}, CodeValidationError},
+		{"simulations", models.ValueAtRiskRequest{ReportingCurrency: "USD", Positions: euros, Simulations: maxVaRSimulations + 1}, CodeValidationError},
+		{"historical without history", models.ValueAtRiskRequest{ReportingCurrency: "USD", Positions: euros, Methods: []string{methodHistorical}}, CodeInsufficientHistory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CalculateValueAtRisk(context.Background(), &tt.request); ErrorCode(err) != tt.code {
+				t.Errorf("Expected code %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
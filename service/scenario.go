@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dalfonso89/financial-forecasting-service/analytics"
+	"github.com/dalfonso89/financial-forecasting-service/calendar"
+	"github.com/dalfonso89/financial-forecasting-service/currency"
+	"github.com/dalfonso89/financial-forecasting-service/logger"
+	"github.com/dalfonso89/financial-forecasting-service/metrics"
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/money"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+	"github.com/dalfonso89/financial-forecasting-service/stress"
+	"github.com/dalfonso89/financial-forecasting-service/tracing"
+)
+
+// Shock types
+const (
+	shockJump       = "jump"
+	shockDrift      = "drift"
+	shockVolatility = "volatility"
+)
+
+// StressScenarios returns the library of historical stress scenarios, oldest first
+func (fs *ForecastingService) StressScenarios() []models.StressScenario {
+	library := stress.All()
+	scenarios := make([]models.StressScenario, len(library))
+	for i, scenario := range library {
+		scenarios[i] = stressScenarioModel(scenario)
+	}
+	return scenarios
+}
+
+// GenerateScenarioForecast forecasts each currency against the base with and without shocks and reports the impact of the
+// shocks on the amount held in each currency
+func (fs *ForecastingService) GenerateScenarioForecast(ctx context.Context, req *models.ScenarioForecastRequest) (response *models.ScenarioForecastResponse, err error) {
+	req.BaseCurrency = currency.Normalize(req.BaseCurrency)
+	for i, code := range req.Currencies {
+		req.Currencies[i] = currency.Normalize(code)
+	}
+	for i := range req.Shocks {
+		req.Shocks[i].Currency = currency.Normalize(req.Shocks[i].Currency)
+		req.Shocks[i].Type = strings.ToLower(req.Shocks[i].Type)
+	}
+	req.StressScenario = strings.ToLower(strings.TrimSpace(req.StressScenario))
+
+	ctx = logger.ContextWithFields(ctx, logger.Fields{"base_currency": req.BaseCurrency})
+	requestLogger := fs.logger.WithContext(ctx)
+	ctx, span := tracing.Start(ctx, "ForecastingService.GenerateScenarioForecast")
+	span.SetAttribute("currency.base", req.BaseCurrency)
+	span.SetAttribute("currency.count", len(req.Currencies))
+	span.SetAttribute("scenario.shocks", len(req.Shocks))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// Set defaults; per-pair overrides do not apply across several targets
+	model := fs.modelFor("", "")
+	if req.Periods == 0 {
+		req.Periods = model.Periods
+	}
+	if req.ForecastType == "" {
+		req.ForecastType = model.ForecastType
+	}
+	if req.Calendar == "" {
+		req.Calendar = calendar.Daily
+	}
+	if req.Frequency == "" {
+		req.Frequency = "daily"
+	}
+	if req.Timezone == "" {
+		req.Timezone = defaultTimezone
+	}
+	if req.ConfidenceLevel == 0 {
+		req.ConfidenceLevel = defaultConfidenceLevel
+	}
+	if req.WindowDays == 0 {
+		req.WindowDays = defaultRiskWindowDays
+	}
+	for i := range req.Shocks {
+		if req.Shocks[i].StartPeriod == 0 {
+			req.Shocks[i].StartPeriod = 1
+		}
+		if req.Shocks[i].EndPeriod == 0 && req.Shocks[i].Type == shockDrift {
+			req.Shocks[i].EndPeriod = req.Periods
+		}
+	}
+	if err := fs.validateScenarioRequest(req); err != nil {
+		return nil, err
+	}
+
+	rates, err := fs.getRates(ctx, req.BaseCurrency)
+	if err != nil {
+		return nil, newUpstreamError(err)
+	}
+
+	response = &models.ScenarioForecastResponse{
+		BaseCurrency:    req.BaseCurrency,
+		Amount:          fs.baseAmount(req.Amount, req.BaseCurrency),
+		ForecastType:    req.ForecastType,
+		Calendar:        req.Calendar,
+		Frequency:       req.Frequency,
+		StartDate:       req.StartDate,
+		Timezone:        req.Timezone,
+		Periods:         req.Periods,
+		ConfidenceLevel: req.ConfidenceLevel,
+		Shocks:          req.Shocks,
+	}
+	if req.StressScenario != "" {
+		scenario, _ := stress.Lookup(req.StressScenario)
+		replayed, unavailable, err := fs.replayStressScenario(ctx, req, scenario)
+		if err != nil {
+			return nil, err
+		}
+		model := stressScenarioModel(scenario)
+		response.StressScenario = &model
+		response.Shocks = append(append([]models.Shock{}, req.Shocks...), replayed...)
+		response.Unavailable = unavailable
+	}
+
+	risk, err := fs.estimateRisk(ctx, req.BaseCurrency, req.Currencies, req.WindowDays)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every currency shares the scenario's period dates, which step over the joint calendar of all of them
+	schedule := &models.ForecastRequest{Periods: req.Periods, Frequency: req.Frequency, StartDate: req.StartDate, Timezone: req.Timezone}
+	dates := periodDates(schedule, fs.forecastStart(schedule), periodCalendar(req.Calendar, append([]string{req.BaseCurrency}, req.Currencies...)...))
+
+	_, computeSpan := tracing.Start(ctx, "forecast.compute")
+	computeSpan.SetAttribute("forecast.type", req.ForecastType)
+	computeStart := time.Now()
+	z := analytics.NormalQuantile(0.5 + req.ConfidenceLevel/2)
+	step := periodDays(req.Frequency)
+	for i, code := range req.Currencies {
+		rate, exists := rates.Rates[code]
+		if !exists || rate <= 0 {
+			computeSpan.End()
+			return nil, newNotFoundError(CodeCurrencyNotFound, "currency %s not found in exchange rates", code)
+		}
+		forecastReq := &models.ForecastRequest{
+			BaseCurrency:   req.BaseCurrency,
+			TargetCurrency: code,
+			Amount:         req.Amount,
+			Periods:        req.Periods,
+			ForecastType:   req.ForecastType,
+			Calendar:       req.Calendar,
+			Frequency:      req.Frequency,
+			StartDate:      req.StartDate,
+			Timezone:       req.Timezone,
+		}
+		baseline, _ := fs.generatePeriods(ctx, rate, forecastReq)
+
+		// A currency whose value rises by a factor buys fewer units of it per unit of the base
+		valueFactors, volatilityFactors := shockPath(response.Shocks, code, req.Periods)
+		volatility := math.Sqrt(risk.covariance[i][i])
+		forecast := models.ScenarioForecast{
+			Currency:    code,
+			CurrentRate: rate,
+			Volatility:  math.Round(volatility*1e6) / 1e6,
+			Baseline:    make([]models.ScenarioPeriod, req.Periods),
+			Shocked:     make([]models.ScenarioPeriod, req.Periods),
+		}
+		var baselineVariance, shockedVariance float64
+		for period := range baseline {
+			baselineVariance += step * volatility * volatility
+			shockedVariance += step * math.Pow(volatility*volatilityFactors[period], 2)
+
+			baselineRate := baseline[period].Rate
+			baselinePeriod := fs.scenarioPeriod(req, rate, baselineRate, z*math.Sqrt(baselineVariance))
+			shockedPeriod := fs.scenarioPeriod(req, rate, baselineRate/valueFactors[period], z*math.Sqrt(shockedVariance))
+			shockedPeriod.Impact = shockedPeriod.Value.Sub(baselinePeriod.Value)
+			baselinePeriod.Period, shockedPeriod.Period = period+1, period+1
+			baselinePeriod.Date, shockedPeriod.Date = dates[period], dates[period]
+			forecast.Baseline[period], forecast.Shocked[period] = baselinePeriod, shockedPeriod
+		}
+		response.Currencies = append(response.Currencies, forecast)
+	}
+	metrics.ForecastDuration.WithLabelValues(req.ForecastType).Observe(time.Since(computeStart).Seconds())
+	computeSpan.End()
+
+	response.Totals = make([]models.ScenarioTotal, req.Periods)
+	for period := range response.Totals {
+		total := models.ScenarioTotal{Period: period + 1, Date: dates[period]}
+		for _, forecast := range response.Currencies {
+			total.BaselineValue = total.BaselineValue.Add(forecast.Baseline[period].Value)
+			total.ShockedValue = total.ShockedValue.Add(forecast.Shocked[period].Value)
+		}
+		total.Impact = total.ShockedValue.Sub(total.BaselineValue)
+		response.Totals[period] = total
+	}
+	response.RiskModel = risk.model
+	response.GeneratedAt = fs.clock.Now()
+
+	requestLogger.Infof("Generated scenario forecast for %d currencies with %d shocks", len(response.Currencies), len(response.Shocks))
+	return response, nil
+}
+
+// validateScenarioRequest validates a scenario request after defaults are applied
+func (fs *ForecastingService) validateScenarioRequest(req *models.ScenarioForecastRequest) error {
+	if !fs.isCurrencySupported(req.BaseCurrency) {
+		return newValidationError(CodeUnsupportedCurrency, "base currency %s is not supported", req.BaseCurrency)
+	}
+	if len(req.Currencies) == 0 {
+		return newValidationError(CodeValidationError, "at least one currency is required")
+	}
+	listed := make(map[string]bool, len(req.Currencies))
+	for _, code := range req.Currencies {
+		switch {
+		case !fs.isCurrencySupported(code):
+			return newValidationError(CodeUnsupportedCurrency, "currency %s is not supported", code)
+		case code == req.BaseCurrency:
+			return newValidationError(CodeValidationError, "currency %s is the base currency", code)
+		case listed[code]:
+			return newValidationError(CodeValidationError, "currency %s is listed more than once", code)
+		}
+		listed[code] = true
+	}
+	if err := validateAmount(req.Amount); err != nil {
+		return err
+	}
+	if req.Amount <= 0 {
+		return newValidationError(CodeValidationError, "amount must be greater than 0")
+	}
+	if len(req.Shocks) == 0 && req.StressScenario == "" {
+		return newValidationError(CodeValidationError, "at least one shock or a stress scenario is required")
+	}
+	if _, ok := stress.Lookup(req.StressScenario); req.StressScenario != "" && !ok {
+		return newValidationError(CodeValidationError, "unknown stress scenario: %s", req.StressScenario)
+	}
+	if req.Periods < 1 || req.Periods > 365 {
+		return newValidationError(CodeValidationError, "periods must be between 1 and 365")
+	}
+	for _, shock := range req.Shocks {
+		if err := validateShock(shock, listed, req.Periods); err != nil {
+			return err
+		}
+	}
+	if !IsForecastTypeSupported(req.ForecastType) {
+		return newValidationError(CodeUnsupportedForecastType, "unsupported forecast type: %s", req.ForecastType)
+	}
+	if !isCalendarSupported(req.Calendar) {
+		return newValidationError(CodeUnsupportedCalendar, "unsupported calendar: %s", req.Calendar)
+	}
+	if !isFrequencySupported(req.Frequency) {
+		return newValidationError(CodeUnsupportedFrequency, "unsupported frequency: %s", req.Frequency)
+	}
+	if err := validateHorizon(req.Periods, req.Frequency); err != nil {
+		return err
+	}
+	if req.ConfidenceLevel <= 0.5 || req.ConfidenceLevel >= 1 {
+		return newValidationError(CodeValidationError, "confidence_level must be greater than 0.5 and less than 1")
+	}
+	if req.WindowDays < 2 || req.WindowDays > maxRiskWindowDays {
+		return newValidationError(CodeValidationError, "window_days must be between 2 and %d", maxRiskWindowDays)
+	}
+	return validateSchedule(req.StartDate, req.Timezone)
+}
+
+// validateShock validates a shock on one of the listed currencies after defaults are applied
+func validateShock(shock models.Shock, listed map[string]bool, periods int) error {
+	if !listed[shock.Currency] {
+		return newValidationError(CodeValidationError, "shocked currency %s is not in currencies", shock.Currency)
+	}
+	if shock.StartPeriod < 1 || shock.StartPeriod > periods {
+		return newValidationError(CodeValidationError, "start_period must be between 1 and %d", periods)
+	}
+	switch shock.Type {
+	case shockJump, shockDrift:
+		if shock.ChangePercent <= -100 || math.IsNaN(shock.ChangePercent) || math.IsInf(shock.ChangePercent, 0) {
+			return newValidationError(CodeValidationError, "change_percent must be greater than -100")
+		}
+		if shock.Type == shockDrift && (shock.EndPeriod < shock.StartPeriod || shock.EndPeriod > periods) {
+			return newValidationError(CodeValidationError, "end_period must be between start_period and %d", periods)
+		}
+	case shockVolatility:
+		if shock.Multiplier <= 0 || math.IsInf(shock.Multiplier, 0) {
+			return newValidationError(CodeValidationError, "multiplier must be greater than 0")
+		}
+	default:
+		return newValidationError(CodeValidationError, "unsupported shock type: %s", shock.Type)
+	}
+	return nil
+}
+
+// shockPath returns the factor the shocks on a currency apply to its value and to its volatility in each period
+func shockPath(shocks []models.Shock, code string, periods int) (value, volatility []float64) {
+	value = make([]float64, periods)
+	volatility = make([]float64, periods)
+	for period := range value {
+		value[period], volatility[period] = 1, 1
+		t := period + 1
+		for _, shock := range shocks {
+			if shock.Currency != code || t < shock.StartPeriod {
+				continue
+			}
+			switch shock.Type {
+			case shockJump:
+				value[period] *= 1 + shock.ChangePercent/100
+			case shockDrift:
+				// Geometric interpolation reaches the full change at the end period and holds it
+				progress := math.Min(1, float64(t-shock.StartPeriod+1)/float64(shock.EndPeriod-shock.StartPeriod+1))
+				value[period] *= math.Pow(1+shock.ChangePercent/100, progress)
+			case shockVolatility:
+				volatility[period] *= shock.Multiplier
+			}
+		}
+	}
+	return value, volatility
+}
+
+// scenarioPeriod values the amount converted at currentRate when the rate has moved to rate, with a rate band of width
+// deviation in log terms
+func (fs *ForecastingService) scenarioPeriod(req *models.ScenarioForecastRequest, currentRate, rate, deviation float64) models.ScenarioPeriod {
+	amount := fs.baseAmount(req.Amount, req.BaseCurrency)
+	value := money.RoundAmount(amount.Mul(money.NewFromFloat(currentRate/rate)), req.BaseCurrency, fs.roundingMode())
+	return models.ScenarioPeriod{
+		Rate:  math.Round(rate*10000) / 10000,
+		Lower: math.Round(rate*math.Exp(-deviation)*10000) / 10000,
+		Upper: math.Round(rate*math.Exp(deviation)*10000) / 10000,
+		Value: value,
+		PnL:   value.Sub(amount),
+	}
+}
+
+// replayStressScenario turns each currency's move against the base during a stress scenario into a drift over as many
+// periods as the scenario lasted, returning the currencies with no rate history on both of its dates separately
+func (fs *ForecastingService) replayStressScenario(ctx context.Context, req *models.ScenarioForecastRequest, scenario stress.Scenario) ([]models.Shock, []string, error) {
+	from, to := scenario.Bounds()
+	points, err := ratehistory.Series(ctx, fs.rateHistory, req.BaseCurrency,
+		from.Add(-scenarioQuoteTolerance), to.Add(scenarioQuoteTolerance))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read rate history: %w", err)
+	}
+
+	periods := int(math.Round(float64(scenario.Days()) / periodDays(req.Frequency)))
+	periods = max(1, min(periods, req.Periods))
+	var shocks []models.Shock
+	var unavailable []string
+	for _, code := range req.Currencies {
+		// Quotes inside the episode only would replay part of the move as if it were all of it
+		first, last := quoteNear(points, code, from), quoteNear(points, code, to)
+		if first == nil || last == nil || first == last {
+			unavailable = append(unavailable, code)
+			continue
+		}
+		// The currency's value against the base is the inverse of its rate
+		change := (first.Rates[code]/last.Rates[code] - 1) * 100
+		shocks = append(shocks, models.Shock{
+			Currency:      code,
+			Type:          shockDrift,
+			ChangePercent: math.Round(change*1e4) / 1e4,
+			StartPeriod:   1,
+			EndPeriod:     periods,
+		})
+	}
+	if len(shocks) == 0 {
+		return nil, nil, newValidationError(CodeInsufficientHistory,
+			"rate history against %s has no rates for %s within a day of both %s and %s",
+			req.BaseCurrency, strings.Join(req.Currencies, ", "), scenario.From, scenario.To)
+	}
+	return shocks, unavailable, nil
+}
+
+// scenarioQuoteTolerance is how far from an episode's start or end date a replayed quote may be
+const scenarioQuoteTolerance = 24 * time.Hour
+
+// quoteNear returns the point closest to date that quotes code, at most scenarioQuoteTolerance away, or nil
+func quoteNear(points []ratehistory.Point, code string, date time.Time) *ratehistory.Point {
+	var nearest *ratehistory.Point
+	var nearestDistance time.Duration
+	for i := range points {
+		if _, ok := points[i].Rates[code]; !ok {
+			continue
+		}
+		pointDate, err := time.Parse("2006-01-02", points[i].Date)
+		if err != nil {
+			continue
+		}
+		distance := pointDate.Sub(date)
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance <= scenarioQuoteTolerance && (nearest == nil || distance < nearestDistance) {
+			nearest, nearestDistance = &points[i], distance
+		}
+	}
+	return nearest
+}
+
+// stressScenarioModel converts a library scenario to its API representation
+func stressScenarioModel(scenario stress.Scenario) models.StressScenario {
+	return models.StressScenario{Name: scenario.Name, Description: scenario.Description, From: scenario.From, To: scenario.To}
+}
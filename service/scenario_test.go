@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/dalfonso89/financial-forecasting-service/models"
+	"github.com/dalfonso89/financial-forecasting-service/ratehistory"
+)
+
+func TestForecastingService_GenerateScenarioForecast(t *testing.T) {
+	service := newTestService(t)
+
+	response, err := service.GenerateScenarioForecast(context.Background(), &models.ScenarioForecastRequest{
+		BaseCurrency: "usd",
+		Currencies:   []string{"eur", "JPY"},
+		Amount:       1000,
+		Periods:      5,
+		Shocks: []models.Shock{
+			{Currency: "EUR", Type: "jump", ChangePercent: -10},
+			{Currency: "jpy", Type: "drift", ChangePercent: 20, StartPeriod: 2, EndPeriod: 3},
+			{Currency: "JPY", Type: "volatility", Multiplier: 2, StartPeriod: 4},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	euros, yen := response.Currencies[0], response.Currencies[1]
+	// Losing 10% of its value, EUR cuts the position's value by 10% in every period
+	for period, shocked := range euros.Shocked {
+		baseline := euros.Baseline[period]
+		if ratio := shocked.Value.Float64() / baseline.Value.Float64(); math.Abs(ratio-0.9) > 1e-4 {
+			t.Errorf("Expected period %d at 90%% of the baseline value, got %v", period+1, ratio)
+		}
+		if !shocked.Impact.Equal(shocked.Value.Sub(baseline.Value)) || !baseline.Impact.IsZero() {
+			t.Errorf("Expected the impact to be the shocked less the baseline value, got %+v", shocked)
+		}
+	}
+
+	// The drift starts in period 2, is halfway in period 2 and complete from period 3
+	expectedFactors := []float64{1, math.Sqrt(1.2), 1.2, 1.2, 1.2}
+	for period, factor := range expectedFactors {
+		if ratio := yen.Shocked[period].Value.Float64() / yen.Baseline[period].Value.Float64(); math.Abs(ratio-factor) > 1e-3 {
+			t.Errorf("Expected JPY period %d at %v of the baseline value, got %v", period+1, factor, ratio)
+		}
+	}
+
+	// Doubled volatility from period 4 widens the band relative to the baseline only from then on
+	width := func(p models.ScenarioPeriod) float64 { return math.Log(p.Upper / p.Rate) }
+	if math.Abs(width(yen.Shocked[2])-width(yen.Baseline[2])) > 1e-4 {
+		t.Errorf("Expected equal bands before the volatility shock, got %v and %v", width(yen.Shocked[2]), width(yen.Baseline[2]))
+	}
+	// Variance over periods 1 to 4 is 3σ² + 4σ² against 4σ²
+	if ratio := width(yen.Shocked[3]) / width(yen.Baseline[3]); math.Abs(ratio-math.Sqrt(7.0/4)) > 1e-3 {
+		t.Errorf("Expected the period 4 band %v times wider, got %v", math.Sqrt(7.0/4), ratio)
+	}
+
+	last := response.Totals[4]
+	if expected := euros.Shocked[4].Impact.Add(yen.Shocked[4].Impact); !last.Impact.Equal(expected) {
+		t.Errorf("Expected a total impact of %s, got %s", expected, last.Impact)
+	}
+	if last.Date != euros.Shocked[4].Date || last.Date == "" {
+		t.Errorf("Expected totals on the period dates, got %s", last.Date)
+	}
+}
+
+func TestForecastingService_GenerateScenarioForecast_StressScenario(t *testing.T) {
+	service := newTestService(t)
+	store := ratehistory.NewMemoryStore()
+	// EUR's last quote is a day after the episode ends, and JPY is only quoted for part of the episode
+	for date, rates := range map[string]map[string]float64{
+		"2016-06-23": {"EUR": 0.88, "JPY": 104},
+		"2016-06-25": {"EUR": 0.9, "JPY": 102},
+		"2016-06-28": {"EUR": 0.9},
+	} {
+		store.Record(context.Background(), ratehistory.Snapshot{Base: "USD", Date: date, Rates: rates})
+	}
+	service.SetRateHistory(store)
+
+	response, err := service.GenerateScenarioForecast(context.Background(), &models.ScenarioForecastRequest{
+		BaseCurrency:   "USD",
+		Currencies:     []string{"EUR", "JPY"},
+		Amount:         1000,
+		Periods:        10,
+		StressScenario: "Brexit_Referendum",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.StressScenario == nil || response.StressScenario.Name != "brexit_referendum" {
+		t.Fatalf("Expected the Brexit referendum scenario, got %+v", response.StressScenario)
+	}
+	if len(response.Unavailable) != 1 || response.Unavailable[0] != "JPY" {
+		t.Errorf("Expected JPY to be unavailable, got %v", response.Unavailable)
+	}
+	// EUR went from 0.88 to 0.90 per USD, losing 2.2222% of its value over 4 days
+	if len(response.Shocks) != 1 {
+		t.Fatalf("Expected one replayed shock, got %+v", response.Shocks)
+	}
+	shock := response.Shocks[0]
+	if shock.Currency != "EUR" || shock.Type != "drift" || shock.ChangePercent != -2.2222 || shock.EndPeriod != 4 {
+		t.Errorf("Expected a 4-period EUR drift of -2.2222%%, got %+v", shock)
+	}
+
+	if _, err := service.GenerateScenarioForecast(context.Background(), &models.ScenarioForecastRequest{
+		BaseCurrency: "USD", Currencies: []string{"EUR"}, Amount: 1000, StressScenario: "covid_crash",
+	}); ErrorCode(err) != CodeInsufficientHistory {
+		t.Errorf("Expected code %s without history for the scenario, got %v", CodeInsufficientHistory, err)
+	}
+}
+
+func TestForecastingService_GenerateScenarioForecast_Validation(t *testing.T) {
+	service := newTestService(t)
+	request := func(shocks ...models.Shock) models.ScenarioForecastRequest {
+		return models.ScenarioForecastRequest{BaseCurrency: "USD", Currencies: []string{"EUR"}, Amount: 1000, Periods: 5, Shocks: shocks}
+	}
+	withScenario := request()
+	withScenario.StressScenario = "alien_invasion"
+
+	tests := []struct {
+		name    string
+		request models.ScenarioForecastRequest
+		code    string
+	}{
+		{"no shocks", request(), CodeValidationError},
+		{"unknown stress scenario", withScenario, CodeValidationError},
+		{"base currency listed", models.ScenarioForecastRequest{BaseCurrency: "USD", Currencies: []string{"USD"}, Amount: 1, Shocks: []models.Shock{{Currency: "USD", Type: "jump"}}}, CodeValidationError},
+		{"unsupported currency", models.ScenarioForecastRequest{BaseCurrency: "USD", Currencies: []string{"CHF"}, Amount: 1, Shocks: []models.Shock{{Currency: "CHF", Type: "jump"}}}, CodeUnsupportedCurrency},
+		{"shock on unlisted currency", request(models.Shock{Currency: "JPY", Type: "jump", ChangePercent: 5}), CodeValidationError},
+		{"shock type", request(models.Shock{Currency: "EUR", Type: "crash"}), CodeValidationError},
+		{"total loss", request(models.Shock{Currency: "EUR", Type: "jump", ChangePercent: -100}), CodeValidationError},
+		{"start period", request(models.Shock{Currency: "EUR", Type: "jump", ChangePercent: 5, StartPeriod: 6}), CodeValidationError},
+		{"drift end period", request(models.Shock{Currency: "EUR", Type: "drift", ChangePercent: 5, StartPeriod: 3, EndPeriod: 2}), CodeValidationError},
+		{"multiplier", request(models.Shock{Currency: "EUR", Type: "volatility"}), CodeValidationError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.GenerateScenarioForecast(context.Background(), &tt.request); ErrorCode(err) != tt.code {
+				t.Errorf("Expected code %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
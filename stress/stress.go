@@ -0,0 +1,58 @@
+// Package stress holds the library of named historical stress scenarios that scenario forecasts replay from rate history
+package stress
+
+import (
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Scenario is a historical market episode between two dates
+type Scenario struct {
+	Name        string
+	Description string
+	From        string // YYYY-MM-DD, the last close before the episode
+	To          string // YYYY-MM-DD, the end of the episode
+}
+
+// Days returns the number of days the episode spans
+func (s Scenario) Days() int {
+	from, _ := time.Parse(dateLayout, s.From)
+	to, _ := time.Parse(dateLayout, s.To)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// Bounds returns the episode's start and end as midnight UTC
+func (s Scenario) Bounds() (from, to time.Time) {
+	from, _ = time.Parse(dateLayout, s.From)
+	to, _ = time.Parse(dateLayout, s.To)
+	return from, to
+}
+
+// library is ordered by date
+var library = []Scenario{
+	{Name: "gfc_lehman", Description: "Lehman Brothers bankruptcy and the flight to the dollar and yen", From: "2008-09-12", To: "2008-10-24"},
+	{Name: "snb_floor_removal", Description: "Swiss National Bank abandons the EUR/CHF 1.20 floor", From: "2015-01-14", To: "2015-01-16"},
+	{Name: "yuan_devaluation", Description: "People's Bank of China devalues the renminbi fixing", From: "2015-08-10", To: "2015-08-13"},
+	{Name: "brexit_referendum", Description: "UK votes to leave the European Union", From: "2016-06-23", To: "2016-06-27"},
+	{Name: "covid_crash", Description: "COVID-19 market crash and dollar funding squeeze", From: "2020-03-09", To: "2020-03-23"},
+	{Name: "ukraine_invasion", Description: "Russia invades Ukraine", From: "2022-02-23", To: "2022-03-07"},
+	{Name: "uk_mini_budget", Description: "UK mini-budget sends sterling to a record low against the dollar", From: "2022-09-22", To: "2022-09-26"},
+}
+
+// All returns every scenario in the library, oldest first
+func All() []Scenario {
+	return append([]Scenario(nil), library...)
+}
+
+// Lookup returns the scenario with a name, ignoring case
+func Lookup(name string) (Scenario, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, scenario := range library {
+		if scenario.Name == name {
+			return scenario, true
+		}
+	}
+	return Scenario{}, false
+}
@@ -0,0 +1,40 @@
+package stress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLibrary(t *testing.T) {
+	names := make(map[string]bool)
+	previous := ""
+	for _, scenario := range All() {
+		if names[scenario.Name] {
+			t.Errorf("Expected unique names, got %s twice", scenario.Name)
+		}
+		names[scenario.Name] = true
+
+		for _, date := range []string{scenario.From, scenario.To} {
+			if _, err := time.Parse(dateLayout, date); err != nil {
+				t.Errorf("Expected %s to have YYYY-MM-DD dates, got %s", scenario.Name, date)
+			}
+		}
+		if scenario.Days() < 1 {
+			t.Errorf("Expected %s to span at least a day, got %d", scenario.Name, scenario.Days())
+		}
+		if scenario.From < previous {
+			t.Errorf("Expected the library in date order, got %s after %s", scenario.From, previous)
+		}
+		previous = scenario.From
+	}
+}
+
+func TestLookup(t *testing.T) {
+	scenario, ok := Lookup(" Brexit_Referendum ")
+	if !ok || scenario.From != "2016-06-23" || scenario.Days() != 4 {
+		t.Errorf("Expected the Brexit referendum over 4 days, got %+v", scenario)
+	}
+	if _, ok := Lookup("alien_invasion"); ok {
+		t.Error("Expected an unknown scenario not to be found")
+	}
+}
@@ -0,0 +1,263 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InMemoryExporter keeps finished spans in memory; intended for tests
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter creates an empty in-memory exporter
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// ExportSpan implements Exporter
+func (e *InMemoryExporter) ExportSpan(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+// Shutdown implements Exporter
+func (e *InMemoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans returns a copy of the exported spans in end order
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
+
+// Reset discards all exported spans
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
+
+// OTLPExporterConfig configures the OTLP/HTTP exporter
+type OTLPExporterConfig struct {
+	// Endpoint is the collector base URL, e.g. "http://localhost:4318"
+	Endpoint      string
+	ServiceName   string
+	BatchSize     int
+	FlushInterval time.Duration
+	HTTPClient    *http.Client
+}
+
+// OTLPExporter batches spans and sends them to an OTLP/HTTP collector using the JSON encoding
+type OTLPExporter struct {
+	url         string
+	serviceName string
+	batchSize   int
+	httpClient  *http.Client
+
+	queue    chan SpanData
+	flushReq chan chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewOTLPExporter creates an exporter and starts its background batching loop
+func NewOTLPExporter(cfg OTLPExporterConfig) *OTLPExporter {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 256
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	exporter := &OTLPExporter{
+		url:         strings.TrimRight(cfg.Endpoint, "/") + "/v1/traces",
+		serviceName: cfg.ServiceName,
+		batchSize:   cfg.BatchSize,
+		httpClient:  cfg.HTTPClient,
+		queue:       make(chan SpanData, cfg.BatchSize*4),
+		flushReq:    make(chan chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go exporter.run(cfg.FlushInterval)
+	return exporter
+}
+
+// ExportSpan implements Exporter; spans are dropped when the queue is full
+func (e *OTLPExporter) ExportSpan(span SpanData) {
+	select {
+	case e.queue <- span:
+	default:
+	}
+}
+
+// Flush sends all queued spans
+func (e *OTLPExporter) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+	select {
+	case e.flushReq <- flushed:
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown implements Exporter; it flushes pending spans and stops the batching loop
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	err := e.Flush(ctx)
+	e.stopOnce.Do(func() { close(e.done) })
+	return err
+}
+
+// run batches queued spans and sends them on size, interval or flush request
+func (e *OTLPExporter) run(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, e.batchSize)
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		_ = e.send(batch)
+		batch = batch[:0]
+	}
+	drain := func() {
+		for {
+			select {
+			case span := <-e.queue:
+				batch = append(batch, span)
+			default:
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= e.batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case flushed := <-e.flushReq:
+			drain()
+			send()
+			close(flushed)
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// send posts a batch of spans to the collector
+func (e *OTLPExporter) send(spans []SpanData) error {
+	payload, err := json.Marshal(otlpRequest(e.serviceName, spans))
+	if err != nil {
+		return fmt.Errorf("failed to marshal spans: %w", err)
+	}
+
+	resp, err := e.httpClient.Post(e.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// otlpRequest builds an ExportTraceServiceRequest in the OTLP JSON encoding
+func otlpRequest(serviceName string, spans []SpanData) map[string]interface{} {
+	otlpSpans := make([]map[string]interface{}, 0, len(spans))
+	for _, span := range spans {
+		otlpSpan := map[string]interface{}{
+			"traceId":           span.SpanContext.TraceID.String(),
+			"spanId":            span.SpanContext.SpanID.String(),
+			"name":              span.Name,
+			"kind":              int(span.Kind),
+			"startTimeUnixNano": strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			"attributes":        otlpAttributes(span.Attributes),
+			"status":            map[string]interface{}{"code": int(span.StatusCode), "message": span.StatusMessage},
+		}
+		if span.ParentSpanID.IsValid() {
+			otlpSpan["parentSpanId"] = span.ParentSpanID.String()
+		}
+		otlpSpans = append(otlpSpans, otlpSpan)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": otlpAttributes(map[string]interface{}{"service.name": serviceName}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": "github.com/dalfonso89/financial-forecasting-service/tracing"},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+// otlpAttributes converts attributes to OTLP KeyValue objects, sorted by key
+func otlpAttributes(attributes map[string]interface{}) []map[string]interface{} {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		var value map[string]interface{}
+		switch typed := attributes[key].(type) {
+		case string:
+			value = map[string]interface{}{"stringValue": typed}
+		case bool:
+			value = map[string]interface{}{"boolValue": typed}
+		case int:
+			value = map[string]interface{}{"intValue": strconv.Itoa(typed)}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(typed, 10)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": typed}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(typed)}
+		}
+		result = append(result, map[string]interface{}{"key": key, "value": value})
+	}
+	return result
+}
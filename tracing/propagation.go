@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is the W3C Trace Context header name
+const TraceparentHeader = "traceparent"
+
+// ParseTraceparent parses a W3C traceparent header value
+func ParseTraceparent(value string) (SpanContext, error) {
+	value = strings.TrimSpace(value)
+	parts := strings.Split(value, "-")
+	if len(parts) < 4 {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", value)
+	}
+
+	version, traceIDHex, spanIDHex, flagsHex := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == "ff" || (version == "00" && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("unsupported traceparent version %q", version)
+	}
+	if len(traceIDHex) != 32 || len(spanIDHex) != 16 || len(flagsHex) != 2 {
+		return SpanContext{}, fmt.Errorf("invalid traceparent field lengths in %q", value)
+	}
+	if strings.ToLower(value) != value {
+		return SpanContext{}, fmt.Errorf("traceparent must be lowercase: %q", value)
+	}
+
+	var spanContext SpanContext
+	if _, err := hex.Decode(spanContext.TraceID[:], []byte(traceIDHex)); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace ID: %w", err)
+	}
+	if _, err := hex.Decode(spanContext.SpanID[:], []byte(spanIDHex)); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid span ID: %w", err)
+	}
+	flags, err := hex.DecodeString(flagsHex)
+	if err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace flags: %w", err)
+	}
+	if !spanContext.IsValid() {
+		return SpanContext{}, fmt.Errorf("traceparent contains an all-zero ID: %q", value)
+	}
+
+	spanContext.Sampled = flags[0]&0x01 == 0x01
+	spanContext.Remote = true
+	return spanContext, nil
+}
+
+// FormatTraceparent renders a span context as a W3C traceparent header value
+func FormatTraceparent(spanContext SpanContext) string {
+	flags := "00"
+	if spanContext.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", spanContext.TraceID, spanContext.SpanID, flags)
+}
+
+// Inject writes the active span context from ctx into outbound request headers
+func Inject(ctx context.Context, header http.Header) {
+	if spanContext := SpanContextFromContext(ctx); spanContext.IsValid() {
+		header.Set(TraceparentHeader, FormatTraceparent(spanContext))
+	}
+}
+
+// Extract reads a traceparent from inbound headers into ctx; invalid values are ignored
+func Extract(ctx context.Context, header http.Header) context.Context {
+	value := header.Get(TraceparentHeader)
+	if value == "" {
+		return ctx
+	}
+	spanContext, err := ParseTraceparent(value)
+	if err != nil {
+		return ctx
+	}
+	return ContextWithRemoteSpanContext(ctx, spanContext)
+}
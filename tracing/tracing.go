@@ -0,0 +1,282 @@
+// Package tracing provides lightweight distributed tracing with W3C Trace Context
+// propagation and OTLP-compatible span export.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+// String returns the lowercase hex encoding of the trace ID
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// IsValid reports whether the trace ID is non-zero
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// String returns the lowercase hex encoding of the span ID
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsValid reports whether the span ID is non-zero
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanContext carries the identity of a span across process boundaries
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool
+}
+
+// IsValid reports whether both IDs are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// SpanKind describes the relationship of a span to its callers and callees
+type SpanKind int
+
+// Span kinds, numbered as in the OTLP protocol
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// StatusCode is the outcome of a span, numbered as in the OTLP protocol
+type StatusCode int
+
+// Span status codes
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// SpanData is an immutable snapshot of a finished span
+type SpanData struct {
+	Name          string
+	Kind          SpanKind
+	SpanContext   SpanContext
+	ParentSpanID  SpanID
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]interface{}
+	StatusCode    StatusCode
+	StatusMessage string
+}
+
+// Span is an in-progress unit of work
+type Span struct {
+	tracer *Tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext returns the identity of the span
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.SpanContext
+}
+
+// SetAttribute records a key/value pair on the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes[key] = value
+}
+
+// RecordError marks the span as failed with the error message
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.StatusCode = StatusError
+	s.data.StatusMessage = err.Error()
+}
+
+// SetStatus sets the span status
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.StatusCode = code
+	s.data.StatusMessage = message
+}
+
+// End finishes the span and hands it to the exporter; later calls are ignored
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	snapshot := s.data
+	snapshot.Attributes = make(map[string]interface{}, len(s.data.Attributes))
+	for key, value := range s.data.Attributes {
+		snapshot.Attributes[key] = value
+	}
+	s.mu.Unlock()
+
+	if snapshot.SpanContext.Sampled && s.tracer.exporter != nil {
+		s.tracer.exporter.ExportSpan(snapshot)
+	}
+}
+
+// Exporter receives finished spans
+type Exporter interface {
+	ExportSpan(span SpanData)
+	Shutdown(ctx context.Context) error
+}
+
+// Tracer creates spans and forwards them to an exporter
+type Tracer struct {
+	exporter Exporter
+}
+
+// NewTracer creates a tracer; a nil exporter still propagates context but drops spans
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// Start creates a span as a child of the span or remote span context in ctx
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+
+	spanContext := SpanContext{Sampled: true}
+	if parent.IsValid() {
+		spanContext.TraceID = parent.TraceID
+		spanContext.Sampled = parent.Sampled
+	} else {
+		spanContext.TraceID = newTraceID()
+	}
+	spanContext.SpanID = newSpanID()
+
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			Name:        name,
+			Kind:        kind,
+			SpanContext: spanContext,
+			StartTime:   time.Now(),
+			Attributes:  make(map[string]interface{}),
+		},
+	}
+	if parent.IsValid() {
+		span.data.ParentSpanID = parent.SpanID
+	}
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Shutdown flushes and stops the exporter
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t.exporter == nil {
+		return nil
+	}
+	return t.exporter.Shutdown(ctx)
+}
+
+var (
+	defaultMutex  sync.RWMutex
+	defaultTracer = NewTracer(nil)
+)
+
+// SetDefault replaces the process-wide tracer used by Start
+func SetDefault(tracer *Tracer) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultTracer = tracer
+}
+
+// Default returns the process-wide tracer
+func Default() *Tracer {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultTracer
+}
+
+// Start creates an internal span using the default tracer
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return Default().Start(ctx, name, SpanKindInternal)
+}
+
+// StartWithKind creates a span of the given kind using the default tracer
+func StartWithKind(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	return Default().Start(ctx, name, kind)
+}
+
+type spanKey struct{}
+
+type remoteSpanContextKey struct{}
+
+// SpanFromContext returns the active span, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteSpanContext stores a span context received from a caller
+func ContextWithRemoteSpanContext(ctx context.Context, spanContext SpanContext) context.Context {
+	spanContext.Remote = true
+	return context.WithValue(ctx, remoteSpanContextKey{}, spanContext)
+}
+
+// SpanContextFromContext returns the active span's context, falling back to a remote parent
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext()
+	}
+	spanContext, _ := ctx.Value(remoteSpanContextKey{}).(SpanContext)
+	return spanContext
+}
+
+// newTraceID generates a random trace ID
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// newSpanID generates a random span ID
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
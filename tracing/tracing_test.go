@@ -0,0 +1,211 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expectError bool
+		sampled     bool
+	}{
+		{
+			name:    "valid sampled",
+			value:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			sampled: true,
+		},
+		{
+			name:  "valid not sampled",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		},
+		{
+			name:        "uppercase",
+			value:       "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			expectError: true,
+		},
+		{
+			name:        "zero trace ID",
+			value:       "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			expectError: true,
+		},
+		{
+			name:        "invalid version",
+			value:       "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectError: true,
+		},
+		{
+			name:        "short span ID",
+			value:       "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa-01",
+			expectError: true,
+		},
+		{
+			name:        "garbage",
+			value:       "not-a-traceparent",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spanContext, err := ParseTraceparent(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q, got nil", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if spanContext.Sampled != tt.sampled {
+				t.Errorf("Expected sampled %v, got %v", tt.sampled, spanContext.Sampled)
+			}
+			if !spanContext.Remote {
+				t.Error("Expected parsed span context to be remote")
+			}
+			if got := FormatTraceparent(spanContext); got != tt.value {
+				t.Errorf("Expected round trip %s, got %s", tt.value, got)
+			}
+		})
+	}
+}
+
+func TestTracer_StartChildSpan(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter)
+
+	ctx, parent := tracer.Start(context.Background(), "parent", SpanKindServer)
+	_, child := tracer.Start(ctx, "child", SpanKindInternal)
+	child.SetAttribute("key", "value")
+	child.End()
+	parent.End()
+	parent.End()
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "child" || spans[1].Name != "parent" {
+		t.Errorf("Expected spans [child parent], got [%s %s]", spans[0].Name, spans[1].Name)
+	}
+	if spans[0].SpanContext.TraceID != spans[1].SpanContext.TraceID {
+		t.Error("Expected child to share the parent's trace ID")
+	}
+	if spans[0].ParentSpanID != spans[1].SpanContext.SpanID {
+		t.Errorf("Expected child parent ID %s, got %s", spans[1].SpanContext.SpanID, spans[0].ParentSpanID)
+	}
+	if spans[1].ParentSpanID.IsValid() {
+		t.Error("Expected root span to have no parent")
+	}
+	if spans[0].Attributes["key"] != "value" {
+		t.Errorf("Expected attribute key=value, got %v", spans[0].Attributes["key"])
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter)
+
+	inbound := http.Header{}
+	inbound.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := Extract(context.Background(), inbound)
+
+	ctx, span := tracer.Start(ctx, "server", SpanKindServer)
+	outbound := http.Header{}
+	Inject(ctx, outbound)
+	span.End()
+
+	remote, err := ParseTraceparent(outbound.Get(TraceparentHeader))
+	if err != nil {
+		t.Fatalf("Expected valid outbound traceparent, got %v", err)
+	}
+	if remote.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected inbound trace ID to propagate, got %s", remote.TraceID)
+	}
+	if remote.SpanID != span.SpanContext().SpanID {
+		t.Errorf("Expected outbound parent %s, got %s", span.SpanContext().SpanID, remote.SpanID)
+	}
+	if got := exporter.Spans()[0].ParentSpanID.String(); got != "00f067aa0ba902b7" {
+		t.Errorf("Expected server span parent 00f067aa0ba902b7, got %s", got)
+	}
+}
+
+func TestTracer_UnsampledSpansNotExported(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter)
+
+	inbound := http.Header{}
+	inbound.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	_, span := tracer.Start(Extract(context.Background(), inbound), "server", SpanKindServer)
+	span.End()
+
+	if len(exporter.Spans()) != 0 {
+		t.Errorf("Expected unsampled span to be dropped, got %d spans", len(exporter.Spans()))
+	}
+}
+
+func TestOTLPExporter_Flush(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []map[string]interface{}
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("Expected path /v1/traces, got %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Expected JSON body, got %v", err)
+		}
+		mu.Lock()
+		requests = append(requests, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPExporter(OTLPExporterConfig{
+		Endpoint:      collector.URL,
+		ServiceName:   "test-service",
+		FlushInterval: time.Hour,
+	})
+	tracer := NewTracer(exporter)
+
+	_, span := tracer.Start(context.Background(), "operation", SpanKindInternal)
+	span.SetAttribute("periods", 5)
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected no shutdown error, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 export request, got %d", len(requests))
+	}
+
+	resourceSpans := requests[0]["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	scopeSpans := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})
+	spans := scopeSpans["spans"].([]interface{})
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 exported span, got %d", len(spans))
+	}
+	exported := spans[0].(map[string]interface{})
+	if exported["name"] != "operation" {
+		t.Errorf("Expected span name operation, got %v", exported["name"])
+	}
+	if exported["traceId"] != span.SpanContext().TraceID.String() {
+		t.Errorf("Expected trace ID %s, got %v", span.SpanContext().TraceID, exported["traceId"])
+	}
+}
@@ -0,0 +1,29 @@
+package uuid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// NewV7 builds an RFC 9562 version 7 UUID: 48-bit Unix milliseconds followed by random bits from crypto/rand
+func NewV7(now time.Time) string {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[6:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+
+	milliseconds := uint64(now.UnixMilli())
+	uuid[0] = byte(milliseconds >> 40)
+	uuid[1] = byte(milliseconds >> 32)
+	uuid[2] = byte(milliseconds >> 24)
+	uuid[3] = byte(milliseconds >> 16)
+	uuid[4] = byte(milliseconds >> 8)
+	uuid[5] = byte(milliseconds)
+	uuid[6] = (uuid[6] & 0x0f) | 0x70 // version 7
+	uuid[8] = (uuid[8] & 0x3f) | 0x80 // RFC 9562 variant
+
+	encoded := hex.EncodeToString(uuid[:])
+	return encoded[0:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:32]
+}
@@ -0,0 +1,27 @@
+package uuid
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestNewV7_Format(t *testing.T) {
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if id := NewV7(time.Now()); !uuidPattern.MatchString(id) {
+		t.Errorf("Expected canonical UUIDv7, got: %s", id)
+	}
+}
+
+func TestNewV7_TimeOrdered(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	earlier := NewV7(now)
+	later := NewV7(now.Add(time.Millisecond))
+
+	if earlier[:13] >= later[:13] {
+		t.Errorf("Expected timestamp prefix of %s to sort before %s", earlier, later)
+	}
+	if got := earlier[:8] + earlier[9:13]; got != "018cc820d888" {
+		t.Errorf("Expected timestamp prefix 018cc820d888, got %s", got)
+	}
+}
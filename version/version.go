@@ -0,0 +1,47 @@
+// Package version reports the build version of the service.
+package version
+
+import "runtime/debug"
+
+// Version is set at build time with -ldflags "-X github.com/dalfonso89/financial-forecasting-service/version.Version=v1.2.3"
+var Version string
+
+// Commit is set at build time with -ldflags "-X github.com/dalfonso89/financial-forecasting-service/version.Commit=abc1234"
+var Commit string
+
+// readBuildInfo is replaced in tests
+var readBuildInfo = debug.ReadBuildInfo
+
+// Info describes the running build
+type Info struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+}
+
+// Get returns the build version, preferring ldflags values and falling back to Go build info
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit}
+
+	if buildInfo, ok := readBuildInfo(); ok {
+		if info.Version == "" && buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+			info.Version = buildInfo.Main.Version
+		}
+		if info.Commit == "" {
+			for _, setting := range buildInfo.Settings {
+				if setting.Key == "vcs.revision" {
+					info.Commit = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	return info
+}
+
+// String returns the build version
+func String() string {
+	return Get().Version
+}
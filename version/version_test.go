@@ -0,0 +1,61 @@
+package version
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	originalRead := readBuildInfo
+	originalVersion, originalCommit := Version, Commit
+	defer func() {
+		readBuildInfo = originalRead
+		Version, Commit = originalVersion, originalCommit
+	}()
+
+	tests := []struct {
+		name      string
+		ldVersion string
+		ldCommit  string
+		buildInfo *debug.BuildInfo
+		expected  Info
+	}{
+		{
+			name:      "ldflags take precedence",
+			ldVersion: "v1.2.3",
+			ldCommit:  "abc1234",
+			buildInfo: &debug.BuildInfo{Main: debug.Module{Version: "v0.9.0"}},
+			expected:  Info{Version: "v1.2.3", Commit: "abc1234"},
+		},
+		{
+			name: "module version and vcs revision from build info",
+			buildInfo: &debug.BuildInfo{
+				Main:     debug.Module{Version: "v0.9.0"},
+				Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "def5678"}},
+			},
+			expected: Info{Version: "v0.9.0", Commit: "def5678"},
+		},
+		{
+			name:      "devel build",
+			buildInfo: &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}},
+			expected:  Info{Version: "dev"},
+		},
+		{
+			name:     "no build info",
+			expected: Info{Version: "dev"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Version, Commit = tt.ldVersion, tt.ldCommit
+			readBuildInfo = func() (*debug.BuildInfo, bool) {
+				return tt.buildInfo, tt.buildInfo != nil
+			}
+
+			if got := Get(); got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}